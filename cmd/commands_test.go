@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/migrations"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+// useSQLite makes the commands connect to a new SQLite database file, and
+// returns a connection of the test to it.
+func useSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	open := func() (database.Dialect, *sql.DB, error) {
+		db, err := database.OpenSQLite(path)
+		if err != nil {
+			return "", nil, err
+		}
+		_, err = database.Migrate(context.Background(), db, migrations.SQLite())
+		return database.SQLite, db, err
+	}
+
+	previous := connect
+	connect = open
+	t.Cleanup(func() { connect = previous })
+
+	_, db, err := open()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	err := run(context.Background(), args, strings.NewReader(stdin), &stdout)
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	out, err := runCommand(t, "", "help")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "user reset-password --email email [--password password]")
+
+	_, err = runCommand(t, "", "user", "delete")
+	assert.ErrorContains(t, err, `unknown command "user delete"`)
+
+	_, err = runCommand(t, "", "migrate", "--force")
+	assert.EqualError(t, err, "migrate: flag provided but not defined: -force")
+}
+
+func TestMigrateCommand(t *testing.T) {
+	useSQLite(t)
+
+	// The SQLite database is migrated on connection.
+	out, err := runCommand(t, "", "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, "0 migrations applied\n", out)
+}
+
+func TestUserCommands(t *testing.T) {
+	db := useSQLite(t)
+
+	out, err := runCommand(t, "secret123\n", "user", "create", "--email", "admin@mail.com", "--name", "Admin", "--admin")
+	assert.NoError(t, err)
+	assert.Equal(t, "Created admin 1, admin@mail.com\n", out)
+
+	var password string
+	var admin bool
+	assert.NoError(t, db.QueryRow(`SELECT password, is_admin FROM users WHERE email = $1`, "admin@mail.com").Scan(&password, &admin))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(password), []byte("secret123")))
+	assert.True(t, admin)
+
+	_, err = runCommand(t, "", "user", "create", "--email", "admin@mail.com", "--name", "Admin", "--password", "secret123")
+	assert.EqualError(t, err, "user create: email already exists")
+
+	_, err = runCommand(t, "", "user", "create", "--email", "invalid", "--name", "User", "--password", "secret123")
+	assert.ErrorContains(t, err, "'email' tag")
+
+	out, err = runCommand(t, "", "user", "reset-password", "--email", "admin@mail.com", "--password", "changed123")
+	assert.NoError(t, err)
+	assert.Equal(t, "Password of admin@mail.com reset\n", out)
+
+	assert.NoError(t, db.QueryRow(`SELECT password FROM users WHERE email = $1`, "admin@mail.com").Scan(&password))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(password), []byte("changed123")))
+
+	_, err = runCommand(t, "", "user", "reset-password", "--email", "missing@mail.com", "--password", "changed123")
+	assert.EqualError(t, err, "user reset-password: user not found")
+
+	_, err = runCommand(t, "", "user", "reset-password", "--email", "admin@mail.com")
+	assert.EqualError(t, err, "user reset-password: no password: set --password or write it to stdin")
+}
+
+func TestSeedCommand(t *testing.T) {
+	db := useSQLite(t)
+
+	out, err := runCommand(t, "", "seed", "--file", "../seeds/development.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "Loaded 2 users and 2 news from ../seeds/development.yaml\n", out)
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news WHERE published_at IS NOT NULL`).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// Seeding again keeps what is already there.
+	_, err = runCommand(t, "", "seed", "--file", "../seeds/development.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news`).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	t.Run("generated", func(t *testing.T) {
+		out, err := runCommand(t, "", "seed", "--users", "3", "--news", "20")
+		assert.NoError(t, err)
+		assert.Regexp(t, `^Generated 3 users and 20 news; they sign in with password123, and \S+\.3@example\.com is an admin\n$`, out)
+
+		// Seeding again builds new users.
+		_, err = runCommand(t, "", "seed", "--users", "3")
+		assert.NoError(t, err)
+
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM users`).Scan(&count))
+		assert.Equal(t, 8, count)
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news`).Scan(&count))
+		assert.Equal(t, 22, count)
+	})
+
+	t.Run("rejects news without users", func(t *testing.T) {
+		_, err := runCommand(t, "", "seed", "--news", "20")
+		assert.EqualError(t, err, "seed: --news needs --users to write them")
+	})
+}
+
+func TestTokenCommand(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	out, err := runCommand(t, "", "token", "issue", "--user", "7", "--admin", "--ttl", "5m")
+	assert.NoError(t, err)
+
+	claims, message := middleware.ParseAuthorization("Bearer "+strings.TrimSpace(out), "test-secret")
+	assert.Empty(t, message)
+	assert.Equal(t, 7, claims.UserID)
+	assert.True(t, claims.Admin)
+
+	_, err = runCommand(t, "", "token", "issue")
+	assert.EqualError(t, err, "token issue: --user is required")
+}
+
+func TestRoutesCommand(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	out, err := runCommand(t, "", "routes")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "METHOD  PATH\n"))
+	assert.Regexp(t, `\nGET +/api/v1/news/:id\n`, out)
+	assert.Regexp(t, `\nPOST +/api/v1/graphql\n`, out)
+	assert.NotContains(t, out, "HEAD")
+	assert.Regexp(t, `\nGET +/api/v1/admin/jobs\n`, out)
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Setenv("DB_DRIVER", "sqlite")
+
+		// The admin routes of the Postgres only tables are not mounted.
+		out, err := runCommand(t, "", "routes")
+		assert.NoError(t, err)
+		assert.Regexp(t, `\nGET +/api/v1/news/:id\n`, out)
+		assert.NotContains(t, out, "/admin/jobs")
+		assert.NotContains(t, out, "/admin/tasks")
+		assert.NotContains(t, out, "/admin/webhooks")
+	})
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewModule(t *testing.T) {
+	m, err := newModule("example.com/app", "category", "title:string, unit_price:float,active:bool", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Category", m.Type)
+	assert.Equal(t, "categories", m.Table)
+	assert.Equal(t, "Categories", m.Plural)
+	assert.Equal(t, "categories", m.Collection())
+	assert.Equal(t, "UnitPrice", m.Fields[1].GoName)
+	assert.Equal(t, "title, unit_price, active", m.Columns())
+	assert.Equal(t, "title = $1, unit_price = $2, active = $3", m.Assignments())
+	assert.Equal(t, "$4", m.IDPlaceholder())
+	assert.Len(t, m.Required(), 2)
+
+	m, err = newModule("example.com/app", "news", "title:string", "news")
+	assert.NoError(t, err)
+	assert.Equal(t, "NewsList", m.Plural)
+
+	tests := []struct {
+		name   string
+		module string
+		fields string
+		table  string
+		err    string
+	}{
+		{"invalid name", "Product", "title:string", "", `invalid module name "Product"`},
+		{"keyword", "func", "title:string", "", `invalid module name "func"`},
+		{"package", "handler", "title:string", "", `invalid module name "handler"`},
+		{"no fields", "product", "", "", "no fields"},
+		{"no type", "product", "title", "", `invalid field "title"`},
+		{"unknown type", "product", "title:blob", "", `invalid type "blob"`},
+		{"invalid field", "product", "Title:string", "", `invalid field name "Title"`},
+		{"common field", "product", "created_at:time", "", `field "created_at" is defined twice`},
+		{"duplicate field", "product", "title:string,title:text", "", `field "title" is defined twice`},
+		{"invalid table", "product", "title:string", "Products", `invalid table name "Products"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newModule("example.com/app", tt.module, tt.fields, tt.table)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for noun, want := range map[string]string{"product": "products", "category": "categories", "key": "keys",
+		"box": "boxes", "address": "addresses", "batch": "batches"} {
+		assert.Equal(t, want, plural(noun), noun)
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	write := func(name string, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+	}
+
+	modules, err := os.ReadFile("../modules.go")
+	assert.NoError(t, err)
+	write("go.mod", "module example.com/app\n\ngo 1.24.0\n")
+	write("cmd/modules.go", string(modules))
+	write("migrations/000009_create_idempotency_keys.up.sql", "")
+	write("migrations/sqlite/000004_add_news_publishing_and_admins.up.sql", "")
+
+	var out bytes.Buffer
+	err = run([]string{"module", "product", "--fields", "title:string,price:int,released_at:time", "--dir", root}, &out)
+	assert.NoError(t, err)
+
+	for _, name := range []string{
+		"internal/product/model/product_model.go",
+		"internal/product/dto/product_dto.go",
+		"internal/product/dto/product_dto_test.go",
+		"internal/product/repository/product_repo.go",
+		"internal/product/repository/product_repo_test.go",
+		"internal/product/service/product_service.go",
+		"internal/product/service/product_service_test.go",
+		"internal/product/handler/product_handler.go",
+		"internal/product/handler/product_openapi.go",
+		"internal/product/dependency_injection/product_di.go",
+		"internal/product/dependency_injection/product_module.go",
+		"migrations/000010_create_products.up.sql",
+		"migrations/000010_create_products.down.sql",
+		"migrations/sqlite/000005_create_products.up.sql",
+		"migrations/sqlite/000005_create_products.down.sql",
+	} {
+		assert.FileExists(t, filepath.Join(root, name))
+		assert.Contains(t, out.String(), filepath.Join(root, name))
+	}
+
+	dto, err := os.ReadFile(filepath.Join(root, "internal/product/dto/product_dto.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(dto), "Title      string    `json:\"title\" validate:\"required,max=255\"`")
+
+	migration, err := os.ReadFile(filepath.Join(root, "migrations/000010_create_products.up.sql"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(migration), "price integer NOT NULL,")
+
+	registered, err := os.ReadFile(filepath.Join(root, "cmd/modules.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(registered), "\tproducts \"example.com/app/internal/product/dependency_injection\"\n")
+	assert.Contains(t, string(registered), "\t\t&outboxModule{},\n\t\tproducts.NewModule(),\n\t}")
+
+	t.Run("existing module", func(t *testing.T) {
+		err := run([]string{"module", "--dir", root, "--fields", "title:string", "product"}, &out)
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		err := run([]string{"model", "product"}, &out)
+		assert.True(t, strings.HasPrefix(err.Error(), "usage: gen module"))
+	})
+}
+
+// TestRunBuilds generates a module with every field type into a copy of the
+// repository, which must still build and pass vet.
+func TestRunBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the repository")
+	}
+
+	root := t.TempDir()
+	if !assert.NoError(t, copyRepository("../..", root)) {
+		return
+	}
+
+	fields := "title:string,body:text,stock:int,views:int64,price:float,active:bool,released_at:time"
+	if !assert.NoError(t, run([]string{"module", "product", "--fields", fields, "--dir", root}, &bytes.Buffer{})) {
+		return
+	}
+
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+		command := exec.Command("go", args...)
+		command.Dir = root
+		output, err := command.CombinedOutput()
+		assert.NoError(t, err, "go %s:\n%s", strings.Join(args, " "), output)
+	}
+}
+
+// copyRepository copies the files of the repository at source, without its
+// git history, to target.
+func copyRepository(source string, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return os.MkdirAll(filepath.Join(target, relative), 0o755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(target, relative), content, 0o644)
+	})
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	// pattern quotes a sqlmock query pattern inside a Go string literal.
+	"pattern": func(text string) string {
+		return strings.ReplaceAll(regexp.QuoteMeta(text), `\`, `\\`)
+	},
+}).ParseFS(templateFiles, "templates/*.tmpl"))
+
+// moduleFiles maps the files of a module, relative to internal/<name>, to
+// their templates.
+var moduleFiles = map[string]string{
+	"model/%s_model.go":                 "model.go.tmpl",
+	"dto/%s_dto.go":                     "dto.go.tmpl",
+	"dto/%s_dto_test.go":                "dto_test.go.tmpl",
+	"repository/%s_repo.go":             "repository.go.tmpl",
+	"repository/%s_repo_test.go":        "repository_test.go.tmpl",
+	"service/%s_service.go":             "service.go.tmpl",
+	"service/%s_service_test.go":        "service_test.go.tmpl",
+	"handler/%s_handler.go":             "handler.go.tmpl",
+	"handler/%s_openapi.go":             "openapi.go.tmpl",
+	"dependency_injection/%s_di.go":     "dependency_injection.go.tmpl",
+	"dependency_injection/%s_module.go": "module.go.tmpl",
+}
+
+// generate writes the module, its migrations for Postgres and SQLite, and
+// registers it in cmd/modules.go, all under root. Every file is
+// rendered before any is written, so a failure leaves the tree untouched.
+// It returns the written files.
+func generate(root string, m *module) ([]string, error) {
+	moduleDir := filepath.Join(root, "internal", m.Name)
+	if _, err := os.Stat(moduleDir); err == nil {
+		return nil, fmt.Errorf("%s already exists", moduleDir)
+	}
+
+	files := map[string][]byte{}
+	for name, tmpl := range moduleFiles {
+		source, err := render(tmpl, m)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(moduleDir, fmt.Sprintf(name, m.Name))] = source
+	}
+
+	for _, migrations := range []struct{ dir, dialect string }{
+		{filepath.Join(root, "migrations"), "postgres"},
+		{filepath.Join(root, "migrations", "sqlite"), "sqlite"},
+	} {
+		version, err := nextMigration(migrations.dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, direction := range []string{"up", "down"} {
+			source, err := render(migrations.dialect+"."+direction+".sql.tmpl", m)
+			if err != nil {
+				return nil, err
+			}
+			name := fmt.Sprintf("%06d_create_%s.%s.sql", version, m.Table, direction)
+			files[filepath.Join(migrations.dir, name)] = source
+		}
+	}
+
+	modulesFile := filepath.Join(root, "cmd", "modules.go")
+	modules, err := os.ReadFile(modulesFile)
+	if err != nil {
+		return nil, err
+	}
+	if files[modulesFile], err = registerModule(modules, m); err != nil {
+		return nil, fmt.Errorf("registering the module in %s: %w", modulesFile, err)
+	}
+
+	written := make([]string, 0, len(files))
+	for name := range files {
+		written = append(written, name)
+	}
+	sort.Strings(written)
+
+	for _, name := range written {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(name, files[name], 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return written, nil
+}
+
+// render executes a template with m, formatting Go files.
+func render(name string, m *module) ([]byte, error) {
+	var source bytes.Buffer
+	if err := templates.ExecuteTemplate(&source, name, m); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return source.Bytes(), nil
+	}
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return formatted, nil
+}
+
+// nextMigration returns the version following the last migration in dir.
+func nextMigration(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	next := 0
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has no version", file)
+		}
+		next = max(next, version+1)
+	}
+
+	return next, nil
+}
+
+// readModulePath returns the module path declared in root/go.mod.
+func readModulePath(root string) (string, error) {
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(goMod), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+}
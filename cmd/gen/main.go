@@ -0,0 +1,75 @@
+// Command gen generates code in this repository. Run it from the root of the
+// repository:
+//
+//	go run ./cmd/gen module product --fields title:string,price:int
+//
+// generates the internal/product module, with its tests and the migrations
+// creating its table, and registers it in cmd/modules.go.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: gen module <name> --fields name:type,... [--table name] [--dir root]
+
+Field types: string, text, int, int64, float, bool and time.`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "module" {
+		return errors.New(usage)
+	}
+
+	flags := flag.NewFlagSet("gen module", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	fields := flags.String("fields", "", "the fields of the model, e.g. title:string,price:int")
+	table := flags.String("table", "", "the table, and route, of the module; the plural of its name by default")
+	root := flags.String("dir", ".", "the root of the repository")
+
+	// The name comes before or after the flags.
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%v\n%s", err, usage)
+	}
+	name := flags.Arg(0)
+	if flags.NArg() > 0 {
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return fmt.Errorf("%v\n%s", err, usage)
+		}
+	}
+	if name == "" || flags.NArg() > 0 {
+		return errors.New(usage)
+	}
+
+	path, err := readModulePath(*root)
+	if err != nil {
+		return err
+	}
+
+	m, err := newModule(path, name, *fields, *table)
+	if err != nil {
+		return err
+	}
+
+	written, err := generate(*root, m)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range written {
+		fmt.Fprintln(stdout, file)
+	}
+	fmt.Fprintf(stdout, "\nModule %s generated. Run go test ./internal/%s/... and apply the migrations.\n", m.Name, m.Name)
+
+	return nil
+}
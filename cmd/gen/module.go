@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// module is what the templates of a generated module are rendered with.
+type module struct {
+	// Path is the Go module path of the repository.
+	Path string
+	// Name is the package directory under internal, e.g. "product".
+	Name string
+	// Type is the model type, e.g. "Product".
+	Type string
+	// Plural names lists in identifiers, e.g. "Products".
+	Plural string
+	// Table is the table and the route, e.g. "products".
+	Table  string
+	Fields []field
+}
+
+// field is a column of the module's table, given as name:kind.
+type field struct {
+	// Name is the column and the JSON name, e.g. "unit_price".
+	Name string
+	// GoName is the struct field, e.g. "UnitPrice".
+	GoName string
+	Kind   kind
+}
+
+// Matches is the Go expression comparing the field of variable with literal.
+func (f field) Matches(variable string, literal string) string {
+	value := variable + "." + f.GoName
+	switch {
+	case f.Kind.Go == "time.Time":
+		return value + ".Equal(" + literal + ")"
+	case literal == "true":
+		return value
+	case literal == "false":
+		return "!" + value
+	}
+	return value + " == " + literal
+}
+
+// kind is a field type of the --fields flag.
+type kind struct {
+	Go       string
+	Postgres string
+	SQLite   string
+	// Validate is the validate tag of request fields, if any.
+	Validate string
+	// Sample and Other are distinct Go literals of the type, and Zero its
+	// zero value, for tests.
+	Sample string
+	Other  string
+	Zero   string
+}
+
+var kinds = map[string]kind{
+	"string": {Go: "string", Postgres: "character varying(255) NOT NULL", SQLite: "varchar(255) NOT NULL",
+		Validate: "required,max=255", Sample: `"Test %s"`, Other: `"Other %s"`, Zero: `""`},
+	"text": {Go: "string", Postgres: "text NOT NULL", SQLite: "text NOT NULL",
+		Validate: "required", Sample: `"Test %s"`, Other: `"Other %s"`, Zero: `""`},
+	"int": {Go: "int", Postgres: "integer NOT NULL", SQLite: "integer NOT NULL",
+		Validate: "required", Sample: "10", Other: "20", Zero: "0"},
+	"int64": {Go: "int64", Postgres: "bigint NOT NULL", SQLite: "bigint NOT NULL",
+		Validate: "required", Sample: "int64(10)", Other: "int64(20)", Zero: "0"},
+	"float": {Go: "float64", Postgres: "double precision NOT NULL", SQLite: "real NOT NULL",
+		Validate: "required", Sample: "9.5", Other: "19.5", Zero: "0"},
+	"bool": {Go: "bool", Postgres: "boolean NOT NULL DEFAULT false", SQLite: "boolean NOT NULL DEFAULT false",
+		Sample: "true", Other: "false", Zero: "false"},
+	"time": {Go: "time.Time", Postgres: "timestamp without time zone NOT NULL", SQLite: "timestamp NOT NULL",
+		Validate: "required", Sample: "time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)", Other: "time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)",
+		Zero: "time.Time{}"},
+}
+
+var (
+	moduleName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	columnName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+// reserved names cannot name a module: they are the packages the generated
+// files import, the layers of a module, or the variables of its code.
+var reserved = map[string]bool{
+	"model": true, "dto": true, "repository": true, "service": true, "handler": true, "crud": true,
+	"response": true, "middleware": true, "openapi": true, "validator": true, "fiber": true, "sql": true,
+	"errors": true, "fmt": true, "log": true, "strconv": true, "time": true, "testing": true, "assert": true,
+	"mock": true, "sqlmock": true, "database": true, "config": true, "main": true,
+	// Variables of the generated code.
+	"request": true, "id": true, "err": true, "query": true, "db": true, "repo": true,
+	"args": true, "context": true, "rows": true, "columns": true, "validate": true, "tt": true, "t": true,
+}
+
+// newModule checks the name and the --fields flag, e.g.
+// "title:string,price:int". table defaults to the plural of the name.
+func newModule(path string, name string, fields string, table string) (*module, error) {
+	if !moduleName.MatchString(name) {
+		return nil, fmt.Errorf("invalid module name %q: use lowercase letters and digits, e.g. product", name)
+	}
+	if token.IsKeyword(name) || reserved[name] {
+		return nil, fmt.Errorf("invalid module name %q: it is taken by a Go keyword or package", name)
+	}
+
+	if table == "" {
+		table = plural(name)
+	}
+	if !columnName.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	m := &module{Path: path, Name: name, Type: camel(name), Plural: camel(table), Table: table}
+	if m.Plural == m.Type {
+		m.Plural += "List"
+	}
+
+	seen := map[string]bool{"id": true, "created_at": true, "updated_at": true}
+	for _, spec := range strings.Split(fields, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		fieldName, kindName, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q: use name:type", spec)
+		}
+		if !columnName.MatchString(fieldName) || token.IsKeyword(fieldName) {
+			return nil, fmt.Errorf("invalid field name %q: use snake_case, e.g. unit_price", fieldName)
+		}
+		if seen[fieldName] {
+			return nil, fmt.Errorf("field %q is defined twice, or by every module", fieldName)
+		}
+		seen[fieldName] = true
+
+		k, ok := kinds[kindName]
+		if !ok {
+			return nil, fmt.Errorf("invalid type %q of field %s: use one of string, text, int, int64, float, bool or time", kindName, fieldName)
+		}
+
+		goName := camel(fieldName)
+		if strings.Contains(k.Sample, "%s") {
+			k.Sample = fmt.Sprintf(k.Sample, goName)
+			k.Other = fmt.Sprintf(k.Other, goName)
+		}
+		m.Fields = append(m.Fields, field{Name: fieldName, GoName: goName, Kind: k})
+	}
+
+	if len(m.Fields) == 0 {
+		return nil, fmt.Errorf("no fields: pass them with --fields, e.g. title:string,price:int")
+	}
+
+	return m, nil
+}
+
+// HasTime tells whether a field is a time.Time, for the imports.
+func (m *module) HasTime() bool {
+	for _, f := range m.Fields {
+		if f.Kind.Go == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// Required are the fields a request cannot omit.
+func (m *module) Required() []field {
+	var required []field
+	for _, f := range m.Fields {
+		if strings.HasPrefix(f.Kind.Validate, "required") {
+			required = append(required, f)
+		}
+	}
+	return required
+}
+
+// Columns lists the columns of the fields, e.g. "title, price".
+func (m *module) Columns() string {
+	names := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// Placeholders are the $n of the fields in an INSERT.
+func (m *module) Placeholders() string {
+	placeholders := make([]string, len(m.Fields))
+	for i := range m.Fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// Assignments are the SETs of the fields in an UPDATE, e.g.
+// "title = $1, price = $2".
+func (m *module) Assignments() string {
+	assignments := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		assignments[i] = fmt.Sprintf("%s = $%d", f.Name, i+1)
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// IDPlaceholder is the $n of the ID in an UPDATE.
+func (m *module) IDPlaceholder() string {
+	return fmt.Sprintf("$%d", len(m.Fields)+1)
+}
+
+// Collection names the rows in variables, e.g. "products".
+func (m *module) Collection() string {
+	return strings.ToLower(m.Plural[:1]) + m.Plural[1:]
+}
+
+// camel turns a snake_case name into an exported identifier the way the
+// modules name their fields, e.g. "author_id" into "AuthorId".
+func camel(name string) string {
+	var result strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			result.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return result.String()
+}
+
+// plural is the English plural of most nouns: "product" into "products",
+// "category" into "categories" and "box" into "boxes".
+func plural(noun string) string {
+	switch {
+	case strings.HasSuffix(noun, "y") && len(noun) > 1 && !strings.ContainsRune("aeiou", rune(noun[len(noun)-2])):
+		return noun[:len(noun)-1] + "ies"
+	case strings.HasSuffix(noun, "s"), strings.HasSuffix(noun, "x"), strings.HasSuffix(noun, "z"),
+		strings.HasSuffix(noun, "ch"), strings.HasSuffix(noun, "sh"):
+		return noun + "es"
+	}
+	return noun + "s"
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// registerModule adds the module to the source of cmd/modules.go: the import
+// of its dependency_injection package, and its Module at the end of the
+// modules of the service.
+func registerModule(source []byte, m *module) ([]byte, error) {
+	modules := string(source)
+	alias := m.Collection()
+	if strings.Contains(modules, "\t"+alias+" \"") {
+		return nil, fmt.Errorf("an import is already named %s", alias)
+	}
+
+	insertions := []struct {
+		after  string
+		before string
+		line   string
+	}{
+		{"import (", "\n)", fmt.Sprintf("\t%s \"%s/internal/%s/dependency_injection\"", alias, m.Path, m.Name)},
+		{"return []app.Module{", "\n\t}", fmt.Sprintf("\t\t%s.NewModule(),", alias)},
+	}
+
+	for _, insertion := range insertions {
+		start := strings.Index(modules, insertion.after)
+		if start < 0 {
+			return nil, fmt.Errorf("%q not found", insertion.after)
+		}
+		end := strings.Index(modules[start:], insertion.before)
+		if end < 0 {
+			return nil, fmt.Errorf("the end of %q not found", insertion.after)
+		}
+		end += start
+
+		modules = modules[:end] + "\n" + insertion.line + modules[end:]
+	}
+
+	// Sorts the imports.
+	return format.Source([]byte(modules))
+}
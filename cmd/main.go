@@ -1,15 +1,32 @@
+// Command cmd serves the API and runs its maintenance tasks. Run it from the
+// root of the repository:
+//
+//	go run ./cmd [serve]
+//	go run ./cmd migrate [--baseline]
+//	go run ./cmd seed [--file seeds/development.yaml]
+//	go run ./cmd seed --users 100 --news 1000 [--seed 1]
+//	go run ./cmd user create --email admin@mail.com --name Admin [--password secret] [--admin]
+//	go run ./cmd user reset-password --email admin@mail.com [--password secret]
+//	go run ./cmd token issue --user 7 [--admin] [--ttl 1h]
+//	go run ./cmd routes
+//
+// Every command reads the same settings, from the environment and .env.
 package main
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 
 	"github.com/ahmadammarm/go-rest-api-template/config"
-	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
-	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
-	"github.com/go-playground/validator/v10"
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors" // Import middleware CORS
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
 	"github.com/joho/godotenv"
 )
 
@@ -17,39 +34,82 @@ func init() {
 	_ = godotenv.Load()
 }
 
+// command is a subcommand, run with the arguments following its name.
+type command struct {
+	name        string
+	flags       string
+	description string
+	run         func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"serve", "", "Serve the HTTP and gRPC APIs, the default", serve},
+	{"migrate", "[--baseline]", "Apply the database migrations", migrate},
+	{"seed", "[--file path | --users n --news n [--seed n]]", "Load users and news from a YAML or JSON file, or generate them", seed},
+	{"user create", "--email email --name name [--password password] [--admin]", "Create a user", createUser},
+	{"user reset-password", "--email email [--password password]", "Replace the password of a user", resetPassword},
+	{"token issue", "--user id [--admin] [--ttl duration]", "Sign a token, for debugging", issueToken},
+	{"routes", "", "Print the routes", printRoutes},
+}
+
+func usage() string {
+	var text strings.Builder
+	text.WriteString("usage: go run ./cmd <command> [flags]\n\nCommands:\n")
+	for _, command := range commands {
+		fmt.Fprintf(&text, "  %s\n      %s\n", strings.TrimSpace(command.name+" "+command.flags), command.description)
+	}
+	text.WriteString("\nWithout a command, serve.")
+	return text.String()
+}
+
 func main() {
-	db, error := config.PostgresConnect()
+	// Cancelled on SIGINT or SIGTERM, which stops the running command: serve
+	// shuts the server down and then stops the modules.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout)
+	stop()
 
-	if error != nil {
-		log.Printf("Failed to connect to database: %v", error)
+	if err != nil {
+		log.Print(err)
 		os.Exit(1)
 	}
+}
 
-	defer db.Close()
-
-	app := fiber.New(fiber.Config{
-		DisableStartupMessage: true,
-	})
+// run runs the command args name, with the arguments following it.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return serve(ctx, args, stdin, stdout)
+	}
+	if slices.Contains([]string{"help", "-h", "--help"}, args[0]) {
+		fmt.Fprintln(stdout, usage())
+		return nil
+	}
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     os.Getenv("CORS_ALLOW_ORIGINS"),
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
-		AllowCredentials: true,
-		MaxAge:           86400,
-	}))
+	for _, command := range commands {
+		words := strings.Fields(command.name)
+		if len(args) >= len(words) && slices.Equal(args[:len(words)], words) {
+			if err := command.run(ctx, args[len(words):], stdin, stdout); err != nil {
+				return fmt.Errorf("%s: %w", command.name, err)
+			}
+			return nil
+		}
+	}
 
-	users.InitializeUser(db, validator.New()).UserRouters(app)
-	news.InitializeNews(db, validator.New()).NewsRouters(app)
+	return fmt.Errorf("unknown command %q\n\n%s", strings.Join(args, " "), usage())
+}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// connect opens the database DB_DRIVER names. Tests replace it.
+var connect = func() (database.Dialect, *sql.DB, error) {
+	driver, err := config.DatabaseDriver()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if error := app.Listen(":" + port); error != nil {
-		log.Printf("Failed to start server: %v", error)
-		os.Exit(1)
+	db, err := config.DatabaseConnect()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+
+	return driver, db, nil
 }
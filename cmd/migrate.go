@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ahmadammarm/go-rest-api-template/migrations"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+// migrate applies the migrations the database lacks. SQLite databases are
+// already migrated on connection.
+func migrate(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	baseline := flags.Bool("baseline", false, "record the migrations as applied without running them, for a database migrated with psql")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	driver, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	files := migrations.Postgres()
+	if driver == database.SQLite {
+		files = migrations.SQLite()
+	}
+
+	apply, verb := database.Migrate, "applied"
+	if *baseline {
+		apply, verb = database.Baseline, "recorded"
+	}
+
+	versions, err := apply(ctx, db, files)
+	for _, version := range versions {
+		fmt.Fprintln(stdout, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d migrations %s\n", len(versions), verb)
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	attachments "github.com/ahmadammarm/go-rest-api-template/internal/attachment/dependency_injection"
+	feeds "github.com/ahmadammarm/go-rest-api-template/internal/feed/dependency_injection"
+	graphQL "github.com/ahmadammarm/go-rest-api-template/internal/graphql/dependency_injection"
+	grpcServer "github.com/ahmadammarm/go-rest-api-template/internal/grpc/dependency_injection"
+	jobs "github.com/ahmadammarm/go-rest-api-template/internal/job/dependency_injection"
+	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
+	newsRepository "github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	reactions "github.com/ahmadammarm/go-rest-api-template/internal/reaction/dependency_injection"
+	tasks "github.com/ahmadammarm/go-rest-api-template/internal/task/dependency_injection"
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	userRepository "github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
+	webhooks "github.com/ahmadammarm/go-rest-api-template/internal/webhook/dependency_injection"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	jobQueue "github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/go-playground/validator/v10"
+)
+
+// newContainer returns the singletons every module shares, reading lists
+// from reads. The outbox, webhooks, jobs and scheduled tasks need Postgres:
+// on SQLite, their workers are not started.
+func newContainer(driver database.Dialect, db *sql.DB, reads database.Querier, store storage.Storage) *app.Container {
+	return &app.Container{
+		Config:    app.Config{Driver: driver},
+		DB:        db,
+		Reads:     reads,
+		Validator: validator.New(),
+		Store:     store,
+		Bus:       outbox.NewBus(),
+		Queue:     jobQueue.NewQueue(db, config.JobMaxAttempts()),
+		Scheduler: scheduler.New(db, nil),
+	}
+}
+
+// newRegistry returns the modules of the service, initialized from
+// container.
+func newRegistry(container *app.Container, newsCache cache.Cache) (*app.Registry, error) {
+	if err := registerTasks(container.Scheduler, container.DB, container.Queue); err != nil {
+		return nil, fmt.Errorf("failed to register tasks: %w", err)
+	}
+
+	registry := app.New(container, modules(container, newsCache)...)
+	if err := registry.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize modules: %w", err)
+	}
+
+	return registry, nil
+}
+
+// modules returns the modules of the service. Those serving routes to
+// signed-in users come first, in the order their routes are mounted.
+func modules(c *app.Container, newsCache cache.Cache) []app.Module {
+	newsHooks := news.Hooks{
+		Events:   config.NewsEvents(),
+		CacheTTL: config.NewsCacheTTL(),
+	}
+	// Shared by every API, so that a change through one invalidates the
+	// reads of all, and keeps those in flight from being cached.
+	if newsCache != nil {
+		newsHooks.Cache = cache.NewFenced(newsCache)
+	}
+	// The events are written to the outbox with every change, whichever API
+	// makes it. On SQLite, changes emit no events: the attachments of deleted
+	// news are purged right after the delete instead.
+	if c.Config.Postgres() {
+		newsHooks.Change = []newsRepository.ChangeHook{outbox.Write}
+	} else {
+		newsHooks.Delete = []newsService.DeleteHook{attachments.NewsDeleteHook(c.DB, c.Store)}
+	}
+
+	return []app.Module{
+		users.NewModule(userHooks(c)),
+		news.NewModule(newsHooks),
+		reactions.NewModule(),
+		attachments.NewModule(),
+		feeds.NewModule(),
+		graphQL.NewModule(),
+		grpcServer.NewModule(),
+		webhooks.NewModule(),
+		jobs.NewModule(),
+		tasks.NewModule(),
+		&outboxModule{},
+	}
+}
+
+// userHooks returns the hooks of users, shared by the APIs and the commands
+// changing them.
+func userHooks(c *app.Container) users.Hooks {
+	hooks := users.Hooks{}
+	if c.Config.Postgres() {
+		hooks.Change = []userRepository.ChangeHook{outbox.Write}
+	}
+	return hooks
+}
+
+// outboxModule dispatches the events of the outbox to the subscribers the
+// other modules added to the bus on Init. It needs Postgres.
+type outboxModule struct {
+	app.Base
+	dispatcher *outbox.Dispatcher
+	runner     app.Runner
+}
+
+func (module *outboxModule) Name() string {
+	return "outbox"
+}
+
+func (module *outboxModule) Init(c *app.Container) error {
+	if c.Config.Postgres() {
+		module.dispatcher = outbox.NewDispatcher(c.DB, c.Bus, config.OutboxDispatcher())
+	}
+	return nil
+}
+
+func (module *outboxModule) Start(ctx context.Context) error {
+	if module.dispatcher != nil {
+		module.runner.Start(ctx, module.dispatcher.Run)
+	}
+	return nil
+}
+
+func (module *outboxModule) Stop(ctx context.Context) error {
+	return module.runner.Stop(ctx)
+}
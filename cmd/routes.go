@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/idempotency"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// apiVersion is a route set mounted under prefix. Versions are served side by
+// side, so a v2 gets its own entry and routes function while v1 keeps working
+// until it is deprecated.
+type apiVersion struct {
+	name        string
+	prefix      string
+	routes      func(root app.Mount) app.Routes
+	deprecation *middleware.DeprecationConfig
+}
+
+// registerRoutes mounts the routes of the modules of registry, initialized
+// from container, under every API version.
+func registerRoutes(server *fiber.App, container *app.Container, registry *app.Registry) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:   "Go REST API Template",
+		Version: "1.0.0",
+	})
+
+	versions := []apiVersion{
+		{name: "v1", prefix: "/api/v1", routes: v1},
+	}
+
+	if config.LegacyRoutesEnabled() {
+		versions = append(versions, apiVersion{
+			name:   "legacy",
+			routes: legacy,
+			deprecation: &middleware.DeprecationConfig{
+				Version:      "legacy",
+				Successor:    "/api/v1",
+				DeprecatedAt: config.LegacyRoutesDeprecatedAt(),
+				Sunset:       config.LegacyRoutesSunset(),
+			},
+		})
+	}
+
+	// Every POST, whichever version, can be retried with an Idempotency-Key.
+	// The keys are stored in Postgres.
+	if container.Config.Postgres() {
+		server.Use(middleware.Idempotency(idempotencyConfig(container.DB)))
+	}
+
+	doc.Routers(server)
+
+	for _, version := range versions {
+		var router fiber.Router = server
+		if version.prefix != "" {
+			router = server.Group(version.prefix)
+		}
+
+		// Per route rather than on a group: the legacy version has no prefix,
+		// and would mark the requests no route matched as deprecated too.
+		deprecated := version.deprecation != nil
+		if deprecated {
+			router = app.PerRoute(router, middleware.Deprecated(*version.deprecation))
+		}
+
+		routes := version.routes(app.NewMount(router, doc, version.prefix, deprecated))
+		registry.RegisterRoutes(routes)
+		routes.Admin.Add(debugVarsRouters, debugVarsOperations)
+	}
+
+	return doc
+}
+
+// v1 exposes every route, including those added since the unversioned ones.
+func v1(root app.Mount) app.Routes {
+	return app.Routes{
+		Root:      root,
+		Versioned: root,
+		Public:    root.Group("/public", middleware.OptionalJWTAuth(), middleware.HTTPCache(publicHTTPCache())),
+		Admin:     root.Group("/admin", middleware.JWTAuth(), middleware.RequireAdmin()),
+	}
+}
+
+// publicHTTPCache configures the HTTP caching of the public routes. Their
+// anonymous responses are also kept in memory unless the size is 0.
+func publicHTTPCache() middleware.HTTPCacheConfig {
+	httpCache := middleware.HTTPCacheConfig{MaxAge: config.HTTPCacheMaxAge()}
+	if size := config.HTTPResponseCacheSize(); size > 0 {
+		httpCache.Store = cache.NewMemory(size)
+	}
+
+	return httpCache
+}
+
+// idempotencyConfig configures the Idempotency-Key middleware, storing keys
+// in db.
+func idempotencyConfig(db *sql.DB) middleware.IdempotencyConfig {
+	return middleware.IdempotencyConfig{
+		Store: idempotency.NewStore(db),
+		TTL:   config.IdempotencyTTL(),
+		Lease: config.IdempotencyLease(),
+	}
+}
+
+// legacy exposes the routes every module had before /api/v1, to signed-in
+// users and anonymous callers.
+func legacy(root app.Mount) app.Routes {
+	return app.Routes{Root: root}
+}
+
+// printRoutes prints the routes the server registers, as configured, without
+// connecting to the database.
+func printRoutes(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("routes", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	driver, err := config.DatabaseDriver()
+	if err != nil {
+		return err
+	}
+
+	// Never connected: the modules only query the database while serving.
+	db, err := sql.Open("pgx", "")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	container := newContainer(driver, db, db, nil)
+	registry, err := newRegistry(container, nil)
+	if err != nil {
+		return err
+	}
+
+	server := fiber.New()
+	registerRoutes(server, container, registry)
+
+	var routes []fiber.Route
+	for _, route := range server.GetRoutes(true) {
+		// Fiber registers a HEAD route for every GET route.
+		if route.Method != fiber.MethodHead {
+			routes = append(routes, route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	table := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tPATH")
+	for _, route := range routes {
+		fmt.Fprintf(table, "%s\t%s\n", route.Method, route.Path)
+	}
+	return table.Flush()
+}
+
+func debugVarsRouters(router fiber.Router) {
+	router.Get("/debug/vars", adaptor.HTTPHandler(expvar.Handler()))
+}
+
+var debugVarsOperations = []openapi.Operation{
+	{Method: "GET", Path: "/debug/vars", Summary: "Runtime metrics, e.g. deprecated_api_requests", Tags: []string{"Admin"},
+		Security: openapi.BearerAuth, ContentType: "application/json", Errors: []int{403}},
+}
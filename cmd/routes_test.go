@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+)
+
+func newTestApp(t *testing.T) (*fiber.App, *openapi.Document) {
+	t.Helper()
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/files", []byte("test-secret"))
+	assert.NoError(t, err)
+
+	container := newContainer(database.Postgres, db, db, store)
+	registry, err := newRegistry(container, nil)
+	assert.NoError(t, err)
+
+	server := fiber.New()
+	doc := registerRoutes(server, container, registry)
+
+	return server, doc
+}
+
+// Fails when a route is added without documenting it, or the other way round.
+func TestEveryRouteIsDocumented(t *testing.T) {
+	app, doc := newTestApp(t)
+
+	undocumented := []string{"/openapi.json", "/docs", "/docs/redoc"}
+
+	registered := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		// Fiber registers a HEAD route for every GET route.
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		skip := false
+		for _, path := range undocumented {
+			skip = skip || route.Path == path
+		}
+		if skip {
+			continue
+		}
+
+		registered[route.Method+" "+openapi.PathTemplate(route.Path)] = true
+		assert.Truef(t, doc.Has(route.Method, route.Path), "route %s %s has no OpenAPI entry", route.Method, route.Path)
+	}
+
+	for _, operation := range doc.Operations() {
+		assert.Truef(t, registered[operation], "OpenAPI entry %s has no route", operation)
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var spec struct {
+		OpenAPI    string                     `json:"openapi"`
+		Paths      map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+	assert.Contains(t, spec.Paths, "/api/v1/news/{id}")
+	assert.Contains(t, string(spec.Paths["/news/{id}"]), `"deprecated":true`)
+	assert.NotContains(t, string(spec.Paths["/api/v1/news/{id}"]), `"deprecated"`)
+	assert.Contains(t, spec.Components.Schemas, "UserRegisterRequest")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/docs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `url: "/openapi.json"`)
+}
+
+func TestLegacyRoutes(t *testing.T) {
+	t.Setenv("API_LEGACY_SUNSET", "2027-01-01T00:00:00Z")
+	app, _ := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/abc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", resp.Header.Get("Sunset"))
+	assert.Equal(t, `</api/v1/users/abc>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/users/abc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Deprecation"))
+
+	// Requests no route matches are not legacy calls.
+	calls := middleware.DeprecatedRequests.String()
+	for _, path := range []string{"/nothing-here", "/api/v1/nothing-here"} {
+		resp, err = app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Deprecation"))
+	}
+	assert.Equal(t, calls, middleware.DeprecatedRequests.String())
+}
+
+func TestLegacyRoutesDisabled(t *testing.T) {
+	t.Setenv("API_LEGACY_ROUTES", "false")
+	app, doc := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/abc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.False(t, doc.Has("GET", "/users/:id"))
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	t.Setenv("GRAPHQL_MAX_DEPTH", "2")
+	app, _ := newTestApp(t)
+
+	req := httptest.NewRequest("POST", "/api/v1/graphql", strings.NewReader(`{"query": "{ newsList { author { name } } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "query depth 3 exceeds the limit of 2")
+}
+
+// Without the outbox, the attachments of deleted news are purged right away.
+func TestSQLitePurgesAttachmentsOfDeletedNews(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	ctx := context.Background()
+
+	db := databasetest.SQLite(t)
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/files", []byte("test-secret"))
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (email, name, password) VALUES ('author@mail.com', 'Author', 'x')`)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO news (title, content, user_id) VALUES ('Title', 'Content', 1)`)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO news_attachments (news_id, user_id, file_name, content_type, size, storage_key)
+		VALUES (1, 1, 'a.txt', 'text/plain', 1, 'news/1/a.txt')`)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Put(ctx, "news/1/a.txt", strings.NewReader("a"), 1, "text/plain"))
+
+	container := newContainer(database.SQLite, db, db, store)
+	registry, err := newRegistry(container, nil)
+	assert.NoError(t, err)
+	server := fiber.New()
+	registerRoutes(server, container, registry)
+
+	token, err := token.Issue("test-secret", 1, false, time.Minute)
+	assert.NoError(t, err)
+	req := httptest.NewRequest("DELETE", "/api/v1/news/1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := server.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news_attachments`).Scan(&count))
+	assert.Equal(t, 0, count)
+	_, err = store.Open(ctx, "news/1/a.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+)
+
+// seed loads the users and news of a fixture file into the database or, with
+// --users and --news, as many generated ones, e.g. for load testing.
+func seed(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	file := flags.String("file", "seeds/development.yaml", "the YAML or JSON file to load")
+	users := flags.Int("users", 0, "the number of users to generate instead")
+	news := flags.Int("news", 0, "the number of news to generate, spread over the generated users")
+	seed := flags.Uint64("seed", 1, "the seed of the generated values")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	generate := *users > 0 || *news > 0
+	switch {
+	case *users < 0 || *news < 0:
+		return errors.New("--users and --news must not be negative")
+	case *news > 0 && *users == 0:
+		return errors.New("--news needs --users to write them")
+	case generate && isFlagSet(flags, "file"):
+		return errors.New("--file cannot be combined with --users and --news")
+	}
+
+	var set *fixtures.Set
+	if !generate {
+		var err error
+		if set, err = fixtures.Read(*file); err != nil {
+			return err
+		}
+	}
+
+	_, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if generate {
+		// Skip the sequence past the existing users, so that seeding again
+		// builds new emails.
+		var existing int
+		if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM users`).Scan(&existing); err != nil {
+			return err
+		}
+
+		factory := fixtures.NewFactory(*seed)
+		factory.Skip(existing)
+		set = factory.Set(*users, *news)
+	}
+
+	if err := fixtures.Load(ctx, db, set); err != nil {
+		return err
+	}
+
+	if generate {
+		fmt.Fprintf(stdout, "Generated %d users and %d news; they sign in with %s, and %s is an admin\n",
+			len(set.Users), len(set.News), fixtures.DefaultPassword, set.Users[0].Email)
+	} else {
+		fmt.Fprintf(stdout, "Loaded %d users and %d news from %s\n", len(set.Users), len(set.News), *file)
+	}
+	return nil
+}
+
+// isFlagSet reports whether the flag name was passed.
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors" // Import middleware CORS
+)
+
+// serve serves the HTTP and gRPC APIs until ctx is done, then shuts the
+// server down and stops the modules.
+func serve(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	driver, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var reads database.Querier = db
+	if driver == database.Postgres {
+		if reads, err = config.PostgresReplicas(ctx, db); err != nil {
+			return fmt.Errorf("failed to connect to the read replicas: %w", err)
+		}
+	}
+
+	store, err := config.StorageConnect()
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	newsCache, err := config.CacheConnect()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	server := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		// Leave room for the multipart envelope around an attachment.
+		BodyLimit: int(config.AttachmentMaxSize()) + 1<<20,
+	})
+
+	server.Use(cors.New(cors.Config{
+		AllowOrigins:     os.Getenv("CORS_ALLOW_ORIGINS"),
+		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,Last-Event-ID,Idempotency-Key",
+		ExposeHeaders:    "Deprecation,Sunset,Link,ETag,X-Cache,Idempotent-Replayed,Retry-After",
+		AllowCredentials: true,
+		MaxAge:           86400,
+	}))
+
+	container := newContainer(driver, db, reads, store)
+	registry, err := newRegistry(container, newsCache)
+	if err != nil {
+		return err
+	}
+
+	registerRoutes(server, container, registry)
+
+	if err := registry.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start modules: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down server...")
+		if err := server.ShutdownWithTimeout(config.ShutdownTimeout()); err != nil {
+			log.Printf("Failed to shut down server: %v", err)
+		}
+	}()
+
+	port := config.GetEnv("PORT", "8080")
+
+	log.Printf("Server starting on port %s", port)
+	listenErr := server.Listen(":" + port)
+	if listenErr != nil {
+		listenErr = fmt.Errorf("failed to start server: %w", listenErr)
+	}
+
+	// The HTTP server is down: stop the modules, which finish the jobs in
+	// progress and the gRPC calls, then let the deferred calls close the
+	// database.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout())
+	defer cancel()
+
+	if err := registry.Stop(shutdownCtx); err != nil {
+		log.Printf("Modules did not stop in time: %v", err)
+	}
+
+	if listenErr == nil {
+		log.Println("Server stopped")
+	}
+	return listenErr
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/idempotency"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+)
+
+// registerTasks adds the periodic maintenance tasks to tasks.
+func registerTasks(tasks *scheduler.Scheduler, db *sql.DB, queue *jobs.Queue) error {
+	if err := tasks.Register("jobs.prune", "@daily", func(ctx context.Context) error {
+		deleted, err := queue.Prune(ctx, config.JobRetention())
+		log.Printf("Pruned %d finished jobs", deleted)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := tasks.Register("jobs.reap", "@every 5m", func(ctx context.Context) error {
+		reaped, err := queue.Reap(ctx)
+		if reaped > 0 {
+			log.Printf("%d jobs are dead, their lease ended on the last attempt", reaped)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := tasks.Register("outbox.prune", "@daily", func(ctx context.Context) error {
+		deleted, err := outbox.Prune(ctx, db, config.OutboxRetention())
+		log.Printf("Pruned %d dispatched outbox events", deleted)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return tasks.Register("idempotency.prune", "@hourly", func(ctx context.Context) error {
+		deleted, err := idempotency.Prune(ctx, db)
+		log.Printf("Pruned %d expired idempotency keys", deleted)
+		return err
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+)
+
+// issueToken prints a token signed with JWT_SECRET_KEY, to call the API as
+// any user while debugging. The user is not looked up.
+func issueToken(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("token issue", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	userID := flags.Int("user", 0, "the ID of the user")
+	admin := flags.Bool("admin", false, "let the token call the admin routes")
+	ttl := flags.Duration("ttl", time.Hour, "how long the token is valid")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *userID <= 0 {
+		return errors.New("--user is required")
+	}
+
+	signed, err := token.Issue(middleware.JWTSecret(), *userID, *admin, *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, signed)
+	return nil
+}
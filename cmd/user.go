@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// createUser creates a user, e.g. the first admin.
+func createUser(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	email := flags.String("email", "", "the email the user signs in with")
+	name := flags.String("name", "", "the name of the user")
+	password := flags.String("password", "", "the password, read from stdin when not set")
+	admin := flags.Bool("admin", false, "let the user call the admin routes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *password == "" {
+		var err error
+		if *password, err = readPassword(stdin); err != nil {
+			return err
+		}
+	}
+
+	container, userService, err := connectUsers()
+	if err != nil {
+		return err
+	}
+	defer container.DB.Close()
+
+	user := &userDTO.UserRegisterRequest{Email: *email, Name: *name, Password: *password}
+	if err := container.Validator.Struct(user); err != nil {
+		return err
+	}
+
+	if err := userService.CreateUser(user, *admin); err != nil {
+		return err
+	}
+
+	role := "user"
+	if *admin {
+		role = "admin"
+	}
+	fmt.Fprintf(stdout, "Created %s %d, %s\n", role, user.ID, user.Email)
+	return nil
+}
+
+// resetPassword replaces the password of a user who lost it.
+func resetPassword(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	email := flags.String("email", "", "the email the user signs in with")
+	password := flags.String("password", "", "the new password, read from stdin when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+	if *password == "" {
+		var err error
+		if *password, err = readPassword(stdin); err != nil {
+			return err
+		}
+	}
+
+	container, userService, err := connectUsers()
+	if err != nil {
+		return err
+	}
+	defer container.DB.Close()
+
+	// The rules of UserRegisterRequest.Password.
+	if err := container.Validator.Var(*password, "required,min=6"); err != nil {
+		return fmt.Errorf("invalid password: %w", err)
+	}
+
+	if err := userService.ResetPassword(*email, *password); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Password of %s reset\n", *email)
+	return nil
+}
+
+// connectUsers returns the user service on the database, with the hooks the
+// APIs change users with. Avatars are not available.
+func connectUsers() (*app.Container, service.UserService, error) {
+	driver, db, err := connect()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	container := newContainer(driver, db, db, nil)
+	return container, users.NewUserService(db, container.Reads, nil, userHooks(container)), nil
+}
+
+// readPassword reads a password from the first line of stdin, so that it is
+// not left in the shell history.
+func readPassword(stdin io.Reader) (string, error) {
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password: set --password or write it to stdin")
+	}
+	return password, nil
+}
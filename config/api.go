@@ -0,0 +1,46 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// LegacyRoutesEnabled reports whether the unversioned paths (/news,
+// /auth/login, ...) are still served as deprecated aliases of /api/v1. Set
+// API_LEGACY_ROUTES=false to drop them.
+func LegacyRoutesEnabled() bool {
+	enabled, err := strconv.ParseBool(GetEnv("API_LEGACY_ROUTES", "true"))
+	if err != nil {
+		log.Printf("Invalid API_LEGACY_ROUTES, keeping legacy routes: %v", err)
+		return true
+	}
+
+	return enabled
+}
+
+// LegacyRoutesDeprecatedAt and LegacyRoutesSunset read API_LEGACY_DEPRECATED_AT
+// and API_LEGACY_SUNSET as RFC 3339 times. They are zero when unset.
+func LegacyRoutesDeprecatedAt() time.Time {
+	return envTime("API_LEGACY_DEPRECATED_AT")
+}
+
+func LegacyRoutesSunset() time.Time {
+	return envTime("API_LEGACY_SUNSET")
+}
+
+func envTime(key string) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Printf("Invalid %s, ignoring it: %v", key, err)
+		return time.Time{}
+	}
+
+	return parsed
+}
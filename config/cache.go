@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+)
+
+// CacheConnect builds the cache selected by CACHE_DRIVER: "none" by default,
+// "memory" for an LRU of CACHE_MEMORY_SIZE entries in each instance, or
+// "redis" for a server shared by every instance at REDIS_URL. It returns nil
+// when caching is off.
+func CacheConnect() (cache.Cache, error) {
+	switch driver := GetEnv("CACHE_DRIVER", "none"); driver {
+	case "none":
+		return nil, nil
+	case "memory":
+		return cache.NewMemory(envInt("CACHE_MEMORY_SIZE", 10000)), nil
+	case "redis":
+		return cache.NewRedis(GetEnv("REDIS_URL", "redis://localhost:6379/0"), envInt("REDIS_POOL_SIZE", 10), envDuration("REDIS_TIMEOUT", time.Second))
+	default:
+		return nil, fmt.Errorf("unknown cache driver: %s", driver)
+	}
+}
+
+// NewsCacheTTL is how long news reads are cached, and so how long reaction
+// counts and author names can be stale.
+func NewsCacheTTL() time.Duration {
+	return envDuration("NEWS_CACHE_TTL", 30*time.Second)
+}
+
+// HTTPCacheMaxAge is how long anonymous responses of the public routes may
+// be reused, HTTP_CACHE_MAX_AGE (60s).
+func HTTPCacheMaxAge() time.Duration {
+	return envDuration("HTTP_CACHE_MAX_AGE", time.Minute)
+}
+
+// HTTPResponseCacheSize is how many responses of the public routes are kept
+// in memory to be served without querying the database,
+// HTTP_RESPONSE_CACHE_SIZE (1000); 0 turns that off.
+func HTTPResponseCacheSize() int {
+	return envInt("HTTP_RESPONSE_CACHE_SIZE", 1000)
+}
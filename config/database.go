@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/ahmadammarm/go-rest-api-template/migrations"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+var (
+	sqliteInstance *sql.DB
+	onceSQLite     sync.Once
+	errorSQLite    error
+)
+
+// DatabaseDriver is the DB_DRIVER setting: postgres, the default, or sqlite.
+func DatabaseDriver() (database.Dialect, error) {
+	return database.ParseDialect(GetEnv("DB_DRIVER", "postgres"))
+}
+
+// DatabaseConnect opens the database DB_DRIVER names.
+func DatabaseConnect() (*sql.DB, error) {
+	driver, err := DatabaseDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	if driver == database.SQLite {
+		return SQLiteConnect()
+	}
+	return PostgresConnect()
+}
+
+// SQLiteConnect opens the SQLite database at SQLITE_PATH
+// (go_rest_template.db), ":memory:" for one that is lost on exit, and applies
+// the migrations it lacks, so that local development needs no other service.
+func SQLiteConnect() (*sql.DB, error) {
+	onceSQLite.Do(func() {
+		path := GetEnv("SQLITE_PATH", "go_rest_template.db")
+
+		db, err := database.OpenSQLite(path)
+		if err != nil {
+			errorSQLite = fmt.Errorf("error opening SQLite database: %v", err)
+			return
+		}
+
+		applied, err := database.Migrate(context.Background(), db, migrations.SQLite())
+		if err != nil {
+			db.Close()
+			errorSQLite = err
+			return
+		}
+
+		fmt.Printf("Connected to SQLite at %s, %d migrations applied\n", path, len(applied))
+		sqliteInstance = db
+	})
+
+	return sqliteInstance, errorSQLite
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// IdempotencyTTL is how long Idempotency-Key responses are kept,
+// IDEMPOTENCY_TTL (24h).
+func IdempotencyTTL() time.Duration {
+	return envDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+}
+
+// IdempotencyLease is how long a request holds its Idempotency-Key at most,
+// IDEMPOTENCY_LEASE (1m).
+func IdempotencyLease() time.Duration {
+	return envDuration("IDEMPOTENCY_LEASE", time.Minute)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+)
+
+// JobMaxAttempts is how many times a job runs before it is dead, unless it
+// was enqueued with its own limit.
+func JobMaxAttempts() int {
+	return max(envInt("JOB_MAX_ATTEMPTS", 5), 1)
+}
+
+// JobWorker reads the JOB_* variables configuring the job worker.
+func JobWorker() jobs.Config {
+	return jobs.Config{
+		Concurrency:  max(envInt("JOB_CONCURRENCY", 4), 1),
+		PollInterval: envDuration("JOB_POLL_INTERVAL", time.Second),
+		Timeout:      envDuration("JOB_TIMEOUT", 5*time.Minute),
+		BaseBackoff:  envDuration("JOB_BASE_BACKOFF", 10*time.Second),
+		MaxBackoff:   envDuration("JOB_MAX_BACKOFF", time.Hour),
+	}
+}
+
+// ShutdownTimeout is how long the server waits for requests and jobs in
+// progress when it is stopped.
+func ShutdownTimeout() time.Duration {
+	return envDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
+}
+
+// JobRetention is how long succeeded and cancelled jobs are kept.
+func JobRetention() time.Duration {
+	return envDuration("JOB_RETENTION", 7*24*time.Hour)
+}
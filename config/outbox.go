@@ -0,0 +1,24 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+)
+
+// OutboxDispatcher reads the OUTBOX_* variables configuring the dispatch of
+// domain events.
+func OutboxDispatcher() outbox.Config {
+	return outbox.Config{
+		PollInterval: envDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:    max(envInt("OUTBOX_BATCH_SIZE", 100), 1),
+		MaxAttempts:  max(envInt("OUTBOX_MAX_ATTEMPTS", 10), 1),
+		BaseBackoff:  envDuration("OUTBOX_BASE_BACKOFF", 5*time.Second),
+		MaxBackoff:   envDuration("OUTBOX_MAX_BACKOFF", time.Hour),
+	}
+}
+
+// OutboxRetention is how long dispatched events are kept.
+func OutboxRetention() time.Duration {
+	return envDuration("OUTBOX_RETENTION", 7*24*time.Hour)
+}
@@ -1,42 +1,32 @@
 package config
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	_ "github.com/lib/pq"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 var (
 	databaseInstance *sql.DB
 	oncePostgres     sync.Once
-	errorInit          error
+	errorInit        error
 )
 
+// PostgresConnect opens the primary database.
 func PostgresConnect() (*sql.DB, error) {
 	oncePostgres.Do(func() {
-		host := os.Getenv("POSTGRES_HOST")
-		port := os.Getenv("POSTGRES_PORT")
-		user := os.Getenv("POSTGRES_USER")
-		password := os.Getenv("POSTGRES_PASSWORD")
-		dbname := os.Getenv("POSTGRES_DB")
-
-		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbname)
-
-		db, err := sql.Open("postgres", dsn)
+		db, err := openPostgres(postgresDSN())
 		if err != nil {
-			errorInit = fmt.Errorf("error opening database: %v", err)
-			return
-		}
-
-		db.SetMaxOpenConns(25)
-		db.SetMaxIdleConns(25)
-		db.SetConnMaxLifetime(5 * time.Minute)
-
-		if err = db.Ping(); err != nil {
-			errorInit = fmt.Errorf("error pinging database: %v", err)
+			errorInit = err
 			return
 		}
 
@@ -46,3 +36,106 @@ func PostgresConnect() (*sql.DB, error) {
 
 	return databaseInstance, errorInit
 }
+
+// PostgresReplicas opens the read replicas of primary listed in
+// POSTGRES_REPLICA_URLS, and checks their health every
+// POSTGRES_REPLICA_CHECK_INTERVAL (5s) until ctx is done. It returns where
+// repositories read lists from: the replicas, or primary when none is listed.
+func PostgresReplicas(ctx context.Context, primary *sql.DB) (database.Querier, error) {
+	var replicas []*sql.DB
+	for _, dsn := range strings.Split(os.Getenv("POSTGRES_REPLICA_URLS"), ",") {
+		if dsn = strings.TrimSpace(dsn); dsn == "" {
+			continue
+		}
+
+		replica, err := openPostgres(dsn)
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("replica %d: %v", len(replicas), err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	if len(replicas) == 0 {
+		return primary, nil
+	}
+
+	set := database.NewReplicas(primary, replicas...)
+	go set.Monitor(ctx, envDuration("POSTGRES_REPLICA_CHECK_INTERVAL", 5*time.Second))
+	fmt.Printf("Connected to %d Postgres read replicas\n", len(replicas))
+
+	return set, nil
+}
+
+// postgresDSN is POSTGRES_URL, or is built from the other POSTGRES_*
+// variables. POSTGRES_SSLMODE is "disable" by default; "verify-full" checks
+// the server certificate against POSTGRES_SSLROOTCERT and its host name.
+func postgresDSN() string {
+	if url := os.Getenv("POSTGRES_URL"); url != "" {
+		return url
+	}
+
+	settings := [][2]string{
+		{"host", os.Getenv("POSTGRES_HOST")},
+		{"port", os.Getenv("POSTGRES_PORT")},
+		{"user", os.Getenv("POSTGRES_USER")},
+		{"password", os.Getenv("POSTGRES_PASSWORD")},
+		{"dbname", os.Getenv("POSTGRES_DB")},
+		{"sslmode", GetEnv("POSTGRES_SSLMODE", "disable")},
+		{"sslrootcert", os.Getenv("POSTGRES_SSLROOTCERT")},
+		{"sslcert", os.Getenv("POSTGRES_SSLCERT")},
+		{"sslkey", os.Getenv("POSTGRES_SSLKEY")},
+	}
+
+	var dsn []string
+	for _, setting := range settings {
+		if setting[1] != "" {
+			value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(setting[1])
+			dsn = append(dsn, setting[0]+"='"+value+"'")
+		}
+	}
+
+	return strings.Join(dsn, " ")
+}
+
+// openPostgres opens a pool of pgx connections to dsn, sized by
+// POSTGRES_MAX_OPEN_CONNS and POSTGRES_MAX_IDLE_CONNS (25), and recycled after
+// POSTGRES_CONN_MAX_LIFETIME (5m) or POSTGRES_CONN_MAX_IDLE_TIME idle. Each
+// connection caches POSTGRES_STATEMENT_CACHE_SIZE (512) prepared statements,
+// and cancels statements running longer than POSTGRES_STATEMENT_TIMEOUT.
+func openPostgres(dsn string) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database settings: %v", err)
+	}
+
+	connConfig.StatementCacheCapacity = envInt("POSTGRES_STATEMENT_CACHE_SIZE", 512)
+	if connConfig.StatementCacheCapacity == 0 {
+		// E.g. behind PgBouncer in transaction mode, where prepared
+		// statements do not outlive a transaction.
+		connConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+	}
+
+	if timeout := envDuration("POSTGRES_STATEMENT_TIMEOUT", 0); timeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+
+	db.SetMaxOpenConns(envInt("POSTGRES_MAX_OPEN_CONNS", 25))
+	db.SetMaxIdleConns(envInt("POSTGRES_MAX_IDLE_CONNS", 25))
+	db.SetConnMaxLifetime(envDuration("POSTGRES_CONN_MAX_LIFETIME", 5*time.Minute))
+	db.SetConnMaxIdleTime(envDuration("POSTGRES_CONN_MAX_IDLE_TIME", 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), envDuration("POSTGRES_CONNECT_TIMEOUT", 10*time.Second))
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error pinging database: %v", err)
+	}
+
+	return db, nil
+}
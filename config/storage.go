@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+)
+
+// StorageConnect builds the file storage selected by STORAGE_DRIVER
+// ("local" by default, or "s3"). Local storage signs its download URLs with
+// STORAGE_SIGNING_KEY, which must not be the JWT key: anyone given a URL
+// holds a signature made with it.
+func StorageConnect() (storage.Storage, error) {
+	switch driver := GetEnv("STORAGE_DRIVER", "local"); driver {
+	case "local":
+		signingKey := os.Getenv("STORAGE_SIGNING_KEY")
+		if signingKey == "" {
+			return nil, errors.New("STORAGE_SIGNING_KEY is required for local storage")
+		}
+		return storage.NewLocalStorage(GetEnv("STORAGE_LOCAL_PATH", "./uploads"), GetEnv("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1"), []byte(signingKey))
+	case "s3":
+		usePathStyle, _ := strconv.ParseBool(GetEnv("S3_USE_PATH_STYLE", "true"))
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			UsePathStyle:    usePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", driver)
+	}
+}
+
+// AttachmentMaxSize is the upload limit in bytes, set in megabytes through
+// ATTACHMENT_MAX_SIZE_MB.
+func AttachmentMaxSize() int64 {
+	megabytes, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_SIZE_MB"), 10, 64)
+	if err != nil || megabytes <= 0 {
+		megabytes = 10
+	}
+
+	return megabytes << 20
+}
+
+func AttachmentURLExpiry() time.Duration {
+	expiry, err := time.ParseDuration(os.Getenv("ATTACHMENT_URL_EXPIRY"))
+	if err != nil || expiry <= 0 {
+		return 15 * time.Minute
+	}
+
+	return expiry
+}
+
+// GetEnv returns the environment variable key, or fallback when it is unset
+// or empty.
+func GetEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
+)
+
+// NewsEvents builds the hub news changes are pushed to stream clients on.
+// The last STREAM_REPLAY_SIZE events are kept for clients resuming with
+// Last-Event-ID, and a client more than STREAM_CLIENT_BUFFER events behind is
+// disconnected.
+func NewsEvents() *pubsub.Hub {
+	return pubsub.NewHub(pubsub.Config{
+		ReplaySize:   envInt("STREAM_REPLAY_SIZE", 100),
+		ClientBuffer: envInt("STREAM_CLIENT_BUFFER", 32),
+	})
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(GetEnv(key, strconv.Itoa(fallback)))
+	if err != nil || value < 0 {
+		return fallback
+	}
+
+	return value
+}
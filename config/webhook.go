@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	webhookService "github.com/ahmadammarm/go-rest-api-template/internal/webhook/service"
+)
+
+// WebhookWorker reads the WEBHOOK_* variables configuring delivery of
+// webhooks.
+func WebhookWorker() webhookService.WorkerConfig {
+	return webhookService.WorkerConfig{
+		PollInterval: envDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		BatchSize:    max(envInt("WEBHOOK_BATCH_SIZE", 20), 1),
+		MaxAttempts:  max(envInt("WEBHOOK_MAX_ATTEMPTS", 8), 1),
+		BaseBackoff:  envDuration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+		MaxBackoff:   envDuration("WEBHOOK_MAX_BACKOFF", 6*time.Hour),
+		Timeout:      envDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+	}
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
@@ -109,42 +109,6 @@ ALTER TABLE ONLY public.news ALTER COLUMN id SET DEFAULT nextval('public.news_id
 ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
 
 
---
--- Data for Name: news; Type: TABLE DATA; Schema: public; Owner: postgres
---
-
-COPY public.news (id, title, content, user_id, created_at, updated_at) FROM stdin;
-3	Oke	Oke adalah berita terkini	7	2025-04-10 15:13:28.934175	2025-04-10 15:13:28.934175
-4	Oke	Oke adalah berita terkini	7	2025-04-10 15:18:19.219176	2025-04-10 15:18:19.219176
-\.
-
-
---
--- Data for Name: users; Type: TABLE DATA; Schema: public; Owner: postgres
---
-
-COPY public.users (id, email, name, password) FROM stdin;
-7	admin@mail.com	Admin	$2a$10$z.Di5gmpQGofCzY/8Ahgn.pCOsucEbZstCNycRkQ9lbx9ej6TEWJC
-8	ammar@mail.com	Ammar	$2a$10$xJmKxpNGjKjW.i.b1JwpdeCtvQBSQEM5Ck968s.7EiOiTnUWArQjO
-21	ammarmusyaffa11@gmail.com	ammar	$2a$10$JCsCxmVOiYhA0TgN.47goO0n2fksrlFB3TFLK30wcjG2S6QKmvyvW
-23	absholum@mail.com	sholum	$2a$10$YqlBkKm6rIg69aCOJfhU3.iBrHul8Wvy1u6P5Vhr9gP2jmhWMwuEG
-\.
-
-
---
--- Name: news_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
---
-
-SELECT pg_catalog.setval('public.news_id_seq', 4, true);
-
-
---
--- Name: users_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
---
-
-SELECT pg_catalog.setval('public.users_id_seq', 23, true);
-
-
 --
 -- Name: news news_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
 --
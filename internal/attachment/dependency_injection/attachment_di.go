@@ -0,0 +1,56 @@
+package dependency_injection
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/handler"
+	attachmentRepository "github.com/ahmadammarm/go-rest-api-template/internal/attachment/repository"
+	attachmentService "github.com/ahmadammarm/go-rest-api-template/internal/attachment/service"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+)
+
+func newAttachmentService(db *sql.DB, store storage.Storage) attachmentService.AttachmentService {
+	attachmentRepo := attachmentRepository.NewAttachmentRepository(db)
+
+	return attachmentService.NewAttachmentService(attachmentRepo, store, attachmentService.Config{
+		MaxSize:        config.AttachmentMaxSize(),
+		URLExpiry:      config.AttachmentURLExpiry(),
+		ThumbnailWidth: 320,
+	})
+}
+
+// purgeOrphansJob removes the files of attachments left behind by deleted
+// news.
+const purgeOrphansJob = "attachments.purge_orphans"
+
+// InitializeAttachment also subscribes to deleted news on bus, queueing a
+// purge of the attachments left behind.
+func InitializeAttachment(db *sql.DB, store storage.Storage, bus *outbox.Bus, queue *jobs.Queue) *handler.AttachmentHandler {
+	service := newAttachmentService(db, store)
+
+	jobs.Register(queue, purgeOrphansJob, func(ctx context.Context, payload struct{}) error {
+		return service.PurgeOrphans()
+	})
+
+	bus.Subscribe(newsDTO.EventDeleted, "attachments.purge-orphans", func(ctx context.Context, event outbox.Event) error {
+		_, err := queue.Enqueue(ctx, purgeOrphansJob, struct{}{}, jobs.Options{})
+		return err
+	})
+
+	return handler.NewAttachmentHandler(service, config.AttachmentMaxSize())
+}
+
+// NewsDeleteHook returns a news delete hook that purges the attachments left
+// behind by the deleted news item, for databases without the outbox.
+func NewsDeleteHook(db *sql.DB, store storage.Storage) func(newsId int) error {
+	service := newAttachmentService(db, store)
+
+	return func(newsId int) error {
+		return service.PurgeOrphans()
+	}
+}
@@ -0,0 +1,29 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the attachments of news, and purges those of deleted news.
+type Module struct {
+	app.Base
+	handler *handler.AttachmentHandler
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "attachment"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	module.handler = InitializeAttachment(c.DB, c.Store, c.Bus, c.Queue)
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Root.Add(module.handler.AttachmentRouters, module.handler.AttachmentOperations())
+}
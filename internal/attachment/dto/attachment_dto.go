@@ -0,0 +1,18 @@
+package dto
+
+// Response body
+type AttachmentResponse struct {
+	ID           int    `json:"id"`
+	NewsId       int    `json:"news_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type AttachmentListResponse struct {
+	Attachments []AttachmentResponse `json:"attachments"`
+	Total       int                  `json:"total"`
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"path"
+	"strconv"
+
+	attachmentService "github.com/ahmadammarm/go-rest-api-template/internal/attachment/service"
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttachmentHandler struct {
+	attachmentService attachmentService.AttachmentService
+	maxSize           int64
+}
+
+func (handler *AttachmentHandler) UploadAttachment(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for attachment upload:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	fileHeader, err := context.FormFile("file")
+	if err != nil {
+		log.Println("Error reading multipart file:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	if fileHeader.Size > handler.maxSize {
+		return response.JSONResponse(context, 413, "File Too Large", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Println("Error opening multipart file:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+	defer file.Close()
+
+	attachment, err := handler.attachmentService.Upload(newsId, userId, fileHeader.Filename, file)
+	if err != nil {
+		log.Println("Error uploading attachment:", err)
+		switch {
+		case errors.Is(err, newsService.ErrNotFound):
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		case errors.Is(err, attachmentService.ErrFileTooLarge):
+			return response.JSONResponse(context, 413, "File Too Large", nil)
+		case errors.Is(err, attachmentService.ErrUnsupportedType):
+			return response.JSONResponse(context, 415, "Unsupported File Type", nil)
+		}
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 201, "Created", attachment)
+}
+
+// newsAttachments is the news item whose attachments are listed.
+var newsAttachments = crud.Resource[int]{
+	Name:    "attachment",
+	ParseID: crud.IntID,
+}
+
+func (handler *AttachmentHandler) GetAttachments(context *fiber.Ctx) error {
+	return crud.Get(newsAttachments, "Success", handler.attachmentService.GetAttachments)(context)
+}
+
+func (handler *AttachmentHandler) DeleteAttachment(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	attachmentId, err := strconv.Atoi(context.Params("attachmentId"))
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	if err := handler.attachmentService.DeleteAttachment(newsId, attachmentId, userId); err != nil {
+		log.Println("Error deleting attachment:", err)
+		switch {
+		case errors.Is(err, attachmentService.ErrNotFound):
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		case errors.Is(err, attachmentService.ErrForbidden):
+			return response.JSONResponse(context, 403, "Forbidden", nil)
+		}
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", nil)
+}
+
+// ServeFile streams a file from local storage when the signed URL is valid.
+func (handler *AttachmentHandler) ServeFile(context *fiber.Ctx) error {
+	key := context.Params("*")
+
+	file, err := handler.attachmentService.OpenFile(key, context.Query("expires"), context.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrInvalidSignature), errors.Is(err, storage.ErrURLExpired):
+			return response.JSONResponse(context, 403, "Forbidden", nil)
+		case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		}
+		log.Println("Error opening file:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		context.Set(fiber.HeaderContentType, contentType)
+	}
+	context.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	return context.SendStream(file)
+}
+
+func (handler *AttachmentHandler) AttachmentRouters(router fiber.Router) {
+	router.Get("/files/*", handler.ServeFile)
+	router.Get("/news/:id/attachments", middleware.JWTAuth(), handler.GetAttachments)
+	router.Post("/news/:id/attachments", middleware.JWTAuth(), handler.UploadAttachment)
+	router.Delete("/news/:id/attachments/:attachmentId", middleware.JWTAuth(), handler.DeleteAttachment)
+}
+
+func NewAttachmentHandler(attachmentService attachmentService.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		maxSize:           maxSize,
+	}
+}
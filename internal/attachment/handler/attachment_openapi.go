@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var attachmentTags = []string{"Attachments"}
+
+// AttachmentOperations documents the routes of AttachmentRouters.
+func (handler *AttachmentHandler) AttachmentOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/files/*", Summary: "Download a file through a signed URL", Tags: attachmentTags,
+			Query: []openapi.Param{
+				{Name: "expires", Type: "integer", Required: true},
+				{Name: "signature", Required: true},
+			},
+			ContentType: "application/octet-stream", Errors: []int{403, 404, 500}},
+		{Method: "GET", Path: "/news/:id/attachments", Summary: "List the attachments of a news", Tags: attachmentTags, Security: openapi.BearerAuth,
+			Response: dto.AttachmentListResponse{}, Errors: []int{400, 500}},
+		{Method: "POST", Path: "/news/:id/attachments", Summary: "Upload an attachment to your news", Tags: attachmentTags, Security: openapi.BearerAuth,
+			Files: []string{"file"}, Response: dto.AttachmentResponse{}, Status: 201, Errors: []int{400, 404, 413, 415, 500}},
+		{Method: "DELETE", Path: "/news/:id/attachments/:attachmentId", Summary: "Delete an attachment you uploaded or of your news", Tags: attachmentTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 403, 404, 500}},
+	}
+}
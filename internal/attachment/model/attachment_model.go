@@ -0,0 +1,13 @@
+package model
+
+type Attachment struct {
+	ID           int    `json:"id"`
+	NewsId       int    `json:"news_id"`
+	UserId       int    `json:"user_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	StorageKey   string `json:"storage_key"`
+	ThumbnailKey string `json:"thumbnail_key"`
+	CreatedAt    string `json:"created_at"`
+}
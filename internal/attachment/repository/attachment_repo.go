@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/model"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+)
+
+type AttachmentRepository interface {
+	// GetNewsAuthorID returns the author of a news item, or ErrNewsNotFound.
+	GetNewsAuthorID(newsId int) (int, error)
+	CreateAttachment(attachment *model.Attachment) error
+	GetAttachmentsByNews(newsId int) ([]model.Attachment, error)
+	GetAttachmentByID(id int) (*model.Attachment, error)
+	GetOrphanAttachments() ([]model.Attachment, error)
+	DeleteAttachment(id int) error
+}
+
+type attachmentRepository struct {
+	attachments *crud.Repository[model.Attachment, int]
+	db          *sql.DB
+}
+
+// ErrNotFound is returned for an attachment ID no row has.
+var ErrNotFound = errors.New("attachment not found")
+
+// ErrNewsNotFound is returned for a news ID no row has.
+var ErrNewsNotFound = errors.New("news not found")
+
+var attachmentTable = crud.Table[model.Attachment]{
+	Name:     "news_attachments",
+	Key:      "id",
+	Columns:  []string{"id", "COALESCE(news_id, 0)", "user_id", "file_name", "content_type", "size", "storage_key", "thumbnail_key", "created_at"},
+	OrderBy:  "id",
+	Scan:     scanAttachment,
+	NotFound: ErrNotFound,
+}
+
+func scanAttachment(scanner crud.Scanner, attachment *model.Attachment) error {
+	return scanner.Scan(&attachment.ID, &attachment.NewsId, &attachment.UserId, &attachment.FileName, &attachment.ContentType,
+		&attachment.Size, &attachment.StorageKey, &attachment.ThumbnailKey, &attachment.CreatedAt)
+}
+
+func (repo *attachmentRepository) GetNewsAuthorID(newsId int) (int, error) {
+	query := `SELECT user_id FROM news WHERE id = $1`
+
+	var authorId int
+	err := repo.db.QueryRow(query, newsId).Scan(&authorId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNewsNotFound
+	}
+
+	return authorId, err
+}
+
+func (repo *attachmentRepository) CreateAttachment(attachment *model.Attachment) error {
+	query := `INSERT INTO news_attachments (news_id, user_id, file_name, content_type, size, storage_key, thumbnail_key)
+              VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+
+	return repo.db.QueryRow(query, attachment.NewsId, attachment.UserId, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.StorageKey, attachment.ThumbnailKey).Scan(&attachment.ID, &attachment.CreatedAt)
+}
+
+func (repo *attachmentRepository) GetAttachmentsByNews(newsId int) ([]model.Attachment, error) {
+	return repo.attachments.Where(`news_id = $1`, "", newsId)
+}
+
+func (repo *attachmentRepository) GetAttachmentByID(id int) (*model.Attachment, error) {
+	return repo.attachments.Get(id)
+}
+
+// GetOrphanAttachments returns attachments whose news item has been deleted.
+// news_attachments.news_id is set to NULL by the foreign key when that happens.
+func (repo *attachmentRepository) GetOrphanAttachments() ([]model.Attachment, error) {
+	return repo.attachments.Where(`news_id IS NULL`, "")
+}
+
+func (repo *attachmentRepository) DeleteAttachment(id int) error {
+	return repo.attachments.Delete(id)
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{attachments: crud.New[model.Attachment, int](db, attachmentTable), db: db}
+}
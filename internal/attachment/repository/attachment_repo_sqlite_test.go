@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/repository"
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestAttachmentRepository_SQLite(t *testing.T) {
+	db := databasetest.SQLite(t)
+
+	factory := fixtures.NewFactory(1)
+	set := &fixtures.Set{News: []*fixtures.News{factory.News(factory.User(), fixtures.Draft)}}
+	assert.NoError(t, fixtures.Load(context.Background(), db, set))
+
+	repo := repository.NewAttachmentRepository(db)
+
+	authorId, err := repo.GetNewsAuthorID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, set.News[0].User.ID, authorId)
+	_, err = repo.GetNewsAuthorID(404)
+	assert.ErrorIs(t, err, repository.ErrNewsNotFound)
+
+	attachment := &model.Attachment{NewsId: 1, UserId: 1, FileName: "photo.jpg", ContentType: "image/jpeg", Size: 1024,
+		StorageKey: "news/1/photo.jpg", ThumbnailKey: "news/1/photo_thumb.jpg"}
+	assert.NoError(t, repo.CreateAttachment(attachment))
+	assert.Equal(t, 1, attachment.ID)
+
+	createdAt, err := time.Parse(time.RFC3339Nano, attachment.CreatedAt)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+
+	stored, err := repo.GetAttachmentByID(attachment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, attachment, stored)
+
+	t.Run("rejects a duplicate storage key", func(t *testing.T) {
+		duplicate := *attachment
+		assert.ErrorContains(t, repo.CreateAttachment(&duplicate), "UNIQUE constraint failed")
+	})
+
+	t.Run("orphans the attachments of deleted news", func(t *testing.T) {
+		orphans, err := repo.GetOrphanAttachments()
+		assert.NoError(t, err)
+		assert.Empty(t, orphans)
+
+		_, err = db.Exec(`DELETE FROM news WHERE id = $1`, 1)
+		assert.NoError(t, err)
+
+		orphans, err = repo.GetOrphanAttachments()
+		assert.NoError(t, err)
+		assert.Len(t, orphans, 1)
+		assert.Zero(t, orphans[0].NewsId)
+
+		assert.NoError(t, repo.DeleteAttachment(attachment.ID))
+		assert.EqualError(t, repo.DeleteAttachment(attachment.ID), "attachment not found")
+	})
+}
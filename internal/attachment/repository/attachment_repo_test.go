@@ -0,0 +1,133 @@
+package repository_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentColumns = []string{"id", "news_id", "user_id", "file_name", "content_type", "size", "storage_key", "thumbnail_key", "created_at"}
+
+func TestCreateAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAttachmentRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO news_attachments \\(news_id, user_id, file_name, content_type, size, storage_key, thumbnail_key\\)").
+			WithArgs(1, 7, "a.png", "image/png", int64(10), "news/1/a.png", "news/1/a_thumb.jpg").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
+
+		attachment := &model.Attachment{
+			NewsId:       1,
+			UserId:       7,
+			FileName:     "a.png",
+			ContentType:  "image/png",
+			Size:         10,
+			StorageKey:   "news/1/a.png",
+			ThumbnailKey: "news/1/a_thumb.jpg",
+		}
+		err := repo.CreateAttachment(attachment)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, attachment.ID)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO news_attachments").
+			WillReturnError(errors.New("query error"))
+
+		err := repo.CreateAttachment(&model.Attachment{})
+		assert.Error(t, err)
+	})
+}
+
+func TestGetAttachmentsByNews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAttachmentRepository(db)
+
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow(1, 1, 7, "a.png", "image/png", 10, "news/1/a.png", "", time.Now()).
+		AddRow(2, 1, 7, "b.pdf", "application/pdf", 20, "news/1/b.pdf", "", time.Now())
+
+	mock.ExpectQuery("SELECT id, COALESCE\\(news_id, 0\\), user_id, file_name, content_type, size, storage_key, thumbnail_key, created_at FROM news_attachments WHERE news_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	attachments, err := repo.GetAttachmentsByNews(1)
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, "application/pdf", attachments[1].ContentType)
+}
+
+func TestGetAttachmentByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAttachmentRepository(db)
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("FROM news_attachments WHERE id = \\$1").
+			WithArgs(999).
+			WillReturnError(sql.ErrNoRows)
+
+		attachment, err := repo.GetAttachmentByID(999)
+		assert.Error(t, err)
+		assert.Equal(t, "attachment not found", err.Error())
+		assert.Nil(t, attachment)
+	})
+}
+
+func TestGetOrphanAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAttachmentRepository(db)
+
+	mock.ExpectQuery("FROM news_attachments WHERE news_id IS NULL").
+		WillReturnRows(sqlmock.NewRows(attachmentColumns).
+			AddRow(3, 0, 7, "a.png", "image/png", 10, "news/1/a.png", "news/1/a_thumb.jpg", time.Now()))
+
+	attachments, err := repo.GetOrphanAttachments()
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 1)
+	assert.Equal(t, 0, attachments[0].NewsId)
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAttachmentRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM news_attachments WHERE id = \\$1").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeleteAttachment(1))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM news_attachments WHERE id = \\$1").
+			WithArgs(999).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.DeleteAttachment(999)
+		assert.Error(t, err)
+		assert.Equal(t, "attachment not found", err.Error())
+	})
+}
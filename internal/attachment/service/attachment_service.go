@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/model"
+	attachmentRepo "github.com/ahmadammarm/go-rest-api-template/internal/attachment/repository"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	imageresize "github.com/ahmadammarm/go-rest-api-template/pkg/image-resize"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// allowedTypes maps the sniffed media type of an upload to the extension used
+// for its storage key. Anything else is rejected.
+var allowedTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+// The errors of uploads and deletions the caller can act on. Missing news
+// items are newsService.ErrNotFound.
+var (
+	ErrNotFound        = attachmentRepo.ErrNotFound
+	ErrFileTooLarge    = errors.New("file too large")
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+type Config struct {
+	MaxSize        int64
+	URLExpiry      time.Duration
+	ThumbnailWidth int
+}
+
+type AttachmentService interface {
+	Upload(newsId int, userId int, fileName string, body io.Reader) (*dto.AttachmentResponse, error)
+	GetAttachments(newsId int) (*dto.AttachmentListResponse, error)
+	DeleteAttachment(newsId int, attachmentId int, userId int) error
+	PurgeOrphans() error
+	OpenFile(key string, expires string, signature string) (io.ReadCloser, error)
+}
+
+type attachmentServiceImpl struct {
+	attachmentRepo attachmentRepo.AttachmentRepository
+	storage        storage.Storage
+	config         Config
+}
+
+func (service *attachmentServiceImpl) Upload(newsId int, userId int, fileName string, body io.Reader) (*dto.AttachmentResponse, error) {
+	log.Printf("Uploading attachment %q to news %d...", fileName, newsId)
+	// Only the author attaches files to a news item. Others get the error of
+	// a missing one, which the news API answers with 404.
+	authorId, err := service.attachmentRepo.GetNewsAuthorID(newsId)
+	if errors.Is(err, attachmentRepo.ErrNewsNotFound) || (err == nil && authorId != userId) {
+		return nil, newsService.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking news: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, service.config.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading upload: %w", err)
+	}
+	if int64(len(data)) > service.config.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	extension, ok := allowedTypes[mediaType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	ctx := context.Background()
+	name := uuid.NewString()
+	attachment := &model.Attachment{
+		NewsId:      newsId,
+		UserId:      userId,
+		FileName:    filepath.Base(fileName),
+		ContentType: mediaType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("news/%d/%s%s", newsId, name, extension),
+	}
+
+	if err := service.storage.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, mediaType); err != nil {
+		return nil, fmt.Errorf("error storing attachment: %w", err)
+	}
+
+	if thumbnail, err := service.thumbnail(data, mediaType); err != nil {
+		log.Printf("Error generating thumbnail for %q: %v", fileName, err)
+	} else if thumbnail != nil {
+		thumbnailKey := fmt.Sprintf("news/%d/%s_thumb.jpg", newsId, name)
+		if err := service.storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			log.Printf("Error storing thumbnail for %q: %v", fileName, err)
+		} else {
+			attachment.ThumbnailKey = thumbnailKey
+		}
+	}
+
+	if err := service.attachmentRepo.CreateAttachment(attachment); err != nil {
+		service.removeFiles(*attachment)
+		return nil, fmt.Errorf("error saving attachment: %w", err)
+	}
+
+	log.Printf("Successfully uploaded attachment %d", attachment.ID)
+	return service.toResponse(*attachment)
+}
+
+func (service *attachmentServiceImpl) thumbnail(data []byte, mediaType string) ([]byte, error) {
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return nil, nil
+	}
+
+	img, err := imageresize.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	width := service.config.ThumbnailWidth
+	return imageresize.EncodeJPEG(imageresize.Fit(img, width, width))
+}
+
+func (service *attachmentServiceImpl) toResponse(attachment model.Attachment) (*dto.AttachmentResponse, error) {
+	ctx := context.Background()
+
+	url, err := service.storage.SignedURL(ctx, attachment.StorageKey, service.config.URLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("error signing attachment url: %w", err)
+	}
+
+	response := &dto.AttachmentResponse{
+		ID:          attachment.ID,
+		NewsId:      attachment.NewsId,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		URL:         url,
+		CreatedAt:   attachment.CreatedAt,
+	}
+
+	if attachment.ThumbnailKey != "" {
+		response.ThumbnailURL, err = service.storage.SignedURL(ctx, attachment.ThumbnailKey, service.config.URLExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("error signing thumbnail url: %w", err)
+		}
+	}
+
+	return response, nil
+}
+
+func (service *attachmentServiceImpl) GetAttachments(newsId int) (*dto.AttachmentListResponse, error) {
+	attachments, err := service.attachmentRepo.GetAttachmentsByNews(newsId)
+	if err != nil {
+		log.Printf("Error fetching attachments for news %d: %v", newsId, err)
+		return nil, fmt.Errorf("error getting attachments: %w", err)
+	}
+
+	responses := make([]dto.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		response, err := service.toResponse(attachment)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+
+	return &dto.AttachmentListResponse{
+		Attachments: responses,
+		Total:       len(responses),
+	}, nil
+}
+
+func (service *attachmentServiceImpl) DeleteAttachment(newsId int, attachmentId int, userId int) error {
+	log.Printf("Deleting attachment %d from news %d...", attachmentId, newsId)
+	attachment, err := service.attachmentRepo.GetAttachmentByID(attachmentId)
+	if err != nil {
+		return err
+	}
+
+	if attachment.NewsId != newsId {
+		return ErrNotFound
+	}
+
+	// The uploader and the author of the news item may delete it.
+	if attachment.UserId != userId {
+		authorId, err := service.attachmentRepo.GetNewsAuthorID(newsId)
+		if err != nil && !errors.Is(err, attachmentRepo.ErrNewsNotFound) {
+			return fmt.Errorf("error checking news: %w", err)
+		}
+		if authorId != userId {
+			return ErrForbidden
+		}
+	}
+
+	if err := service.attachmentRepo.DeleteAttachment(attachmentId); err != nil {
+		return fmt.Errorf("error deleting attachment: %w", err)
+	}
+
+	service.removeFiles(*attachment)
+
+	log.Printf("Successfully deleted attachment %d", attachmentId)
+	return nil
+}
+
+// PurgeOrphans removes the files and rows of attachments whose news item no
+// longer exists. It is safe to call repeatedly.
+func (service *attachmentServiceImpl) PurgeOrphans() error {
+	attachments, err := service.attachmentRepo.GetOrphanAttachments()
+	if err != nil {
+		return fmt.Errorf("error getting orphan attachments: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		service.removeFiles(attachment)
+		if err := service.attachmentRepo.DeleteAttachment(attachment.ID); err != nil {
+			return fmt.Errorf("error deleting orphan attachment %d: %w", attachment.ID, err)
+		}
+	}
+
+	if len(attachments) > 0 {
+		log.Printf("Purged %d orphan attachments", len(attachments))
+	}
+
+	return nil
+}
+
+func (service *attachmentServiceImpl) removeFiles(attachment model.Attachment) {
+	ctx := context.Background()
+
+	for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := service.storage.Delete(ctx, key); err != nil {
+			log.Printf("Error deleting stored file %q: %v", key, err)
+		}
+	}
+}
+
+func (service *attachmentServiceImpl) OpenFile(key string, expires string, signature string) (io.ReadCloser, error) {
+	verifier, ok := service.storage.(storage.SignatureVerifier)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, err
+	}
+
+	return service.storage.Open(context.Background(), key)
+}
+
+func NewAttachmentService(attachmentRepo attachmentRepo.AttachmentRepository, storage storage.Storage, config Config) AttachmentService {
+	log.Println("Initializing AttachmentService...")
+	return &attachmentServiceImpl{
+		attachmentRepo: attachmentRepo,
+		storage:        storage,
+		config:         config,
+	}
+}
@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/repository"
+	"github.com/ahmadammarm/go-rest-api-template/internal/attachment/service"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) GetNewsAuthorID(newsId int) (int, error) {
+	args := m.Called(newsId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(attachment *model.Attachment) error {
+	args := m.Called(attachment)
+	attachment.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetAttachmentsByNews(newsId int) ([]model.Attachment, error) {
+	args := m.Called(newsId)
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetAttachmentByID(id int) (*model.Attachment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetOrphanAttachments() ([]model.Attachment, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) DeleteAttachment(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func newTestService(t *testing.T, repo *MockAttachmentRepository) (service.AttachmentService, *storage.LocalStorage) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	assert.NoError(t, err)
+
+	return service.NewAttachmentService(repo, store, service.Config{
+		MaxSize:        1 << 20,
+		URLExpiry:      time.Minute,
+		ThumbnailWidth: 32,
+	}), store
+}
+
+func pngImage(t *testing.T, width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x%height, color.RGBA{R: 255, A: 255})
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("image gets a thumbnail", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, store := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 1).Return(7, nil).Once()
+		mockRepo.On("CreateAttachment", mock.MatchedBy(func(attachment *model.Attachment) bool {
+			return attachment.ContentType == "image/png" && strings.HasSuffix(attachment.ThumbnailKey, "_thumb.jpg")
+		})).Return(nil).Once()
+
+		result, err := attachmentService.Upload(1, 7, "../photo.png", bytes.NewReader(pngImage(t, 200, 100)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "photo.png", result.FileName)
+		assert.NotEmpty(t, result.ThumbnailURL)
+
+		thumbnailURL, _ := url.Parse(result.ThumbnailURL)
+		thumbnailKey := strings.TrimPrefix(thumbnailURL.Path, "/files/")
+		file, err := store.Open(context.Background(), thumbnailKey)
+		assert.NoError(t, err)
+		thumbnail, _, err := image.Decode(file)
+		file.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, 32, thumbnail.Bounds().Dx())
+		assert.Equal(t, 16, thumbnail.Bounds().Dy())
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("plain text has no thumbnail", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, _ := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 1).Return(7, nil).Once()
+		mockRepo.On("CreateAttachment", mock.Anything).Return(nil).Once()
+
+		result, err := attachmentService.Upload(1, 7, "notes.txt", strings.NewReader("hello world"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain", result.ContentType)
+		assert.Empty(t, result.ThumbnailURL)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, _ := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 1).Return(7, nil).Once()
+
+		result, err := attachmentService.Upload(1, 7, "run.png", bytes.NewReader([]byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0, 0}))
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, service.ErrUnsupportedType)
+		assert.Nil(t, result)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, _ := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 1).Return(7, nil).Once()
+
+		result, err := attachmentService.Upload(1, 7, "big.txt", strings.NewReader(strings.Repeat("a", 1<<20+1)))
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, service.ErrFileTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("news not found", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, _ := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 999).Return(0, repository.ErrNewsNotFound).Once()
+
+		_, err := attachmentService.Upload(999, 7, "notes.txt", strings.NewReader("hello"))
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, newsService.ErrNotFound)
+	})
+
+	t.Run("news of another author", func(t *testing.T) {
+		mockRepo := new(MockAttachmentRepository)
+		attachmentService, _ := newTestService(t, mockRepo)
+
+		mockRepo.On("GetNewsAuthorID", 1).Return(8, nil).Once()
+
+		_, err := attachmentService.Upload(1, 7, "notes.txt", strings.NewReader("hello"))
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, newsService.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "CreateAttachment", mock.Anything)
+	})
+}
+
+func TestDeleteAttachmentOwnership(t *testing.T) {
+	mockRepo := new(MockAttachmentRepository)
+	attachmentService, _ := newTestService(t, mockRepo)
+
+	mockRepo.On("GetAttachmentByID", 5).Return(&model.Attachment{ID: 5, NewsId: 1, UserId: 7}, nil)
+	mockRepo.On("GetNewsAuthorID", 1).Return(9, nil)
+
+	err := attachmentService.DeleteAttachment(2, 5, 7)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	err = attachmentService.DeleteAttachment(1, 5, 8)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	t.Run("by the uploader or the author of the news", func(t *testing.T) {
+		mockRepo.On("DeleteAttachment", 5).Return(nil).Twice()
+
+		assert.NoError(t, attachmentService.DeleteAttachment(1, 5, 7))
+		assert.NoError(t, attachmentService.DeleteAttachment(1, 5, 9))
+		mockRepo.AssertNumberOfCalls(t, "DeleteAttachment", 2)
+	})
+}
+
+func TestPurgeOrphans(t *testing.T) {
+	mockRepo := new(MockAttachmentRepository)
+	attachmentService, store := newTestService(t, mockRepo)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "news/1/a.txt", strings.NewReader("a"), 1, "text/plain"))
+
+	mockRepo.On("GetOrphanAttachments").Return([]model.Attachment{{ID: 3, StorageKey: "news/1/a.txt"}}, nil).Once()
+	mockRepo.On("DeleteAttachment", 3).Return(nil).Once()
+
+	err := attachmentService.PurgeOrphans()
+
+	assert.NoError(t, err)
+	_, err = store.Open(ctx, "news/1/a.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOpenFile(t *testing.T) {
+	mockRepo := new(MockAttachmentRepository)
+	attachmentService, store := newTestService(t, mockRepo)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "news/1/a.txt", strings.NewReader("a"), 1, "text/plain"))
+	signed, _ := store.SignedURL(ctx, "news/1/a.txt", time.Minute)
+	parsed, _ := url.Parse(signed)
+
+	file, err := attachmentService.OpenFile("news/1/a.txt", parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	assert.NoError(t, err)
+	file.Close()
+
+	_, err = attachmentService.OpenFile("news/1/a.txt", parsed.Query().Get("expires"), "bad")
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature)
+}
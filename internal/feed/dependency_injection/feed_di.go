@@ -0,0 +1,30 @@
+package dependency_injection
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/handler"
+	feedService "github.com/ahmadammarm/go-rest-api-template/internal/feed/service"
+	newsRepository "github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+func InitializeFeed(db *sql.DB, reads database.Querier) *handler.FeedHandler {
+	itemLimit, err := strconv.Atoi(config.GetEnv("FEED_ITEM_LIMIT", "20"))
+	if err != nil || itemLimit < 1 {
+		itemLimit = 20
+	}
+
+	newsRepo := newsRepository.NewNewsRepository(db, reads)
+	feedService := feedService.NewFeedService(newsRepo, feedService.Config{
+		Title:       config.GetEnv("FEED_TITLE", "Go REST API Template News"),
+		Description: config.GetEnv("FEED_DESCRIPTION", "Latest news"),
+		SiteURL:     config.GetEnv("FEED_SITE_URL", "http://localhost:8080"),
+		Language:    config.GetEnv("FEED_LANGUAGE", "en"),
+		ItemLimit:   itemLimit,
+	})
+
+	return handler.NewFeedHandler(feedService)
+}
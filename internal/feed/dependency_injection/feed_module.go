@@ -0,0 +1,29 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the RSS and Atom feeds of the published news.
+type Module struct {
+	app.Base
+	handler *handler.FeedHandler
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "feed"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	module.handler = InitializeFeed(c.DB, c.Reads)
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Root.Add(module.handler.FeedRouters, module.handler.FeedOperations())
+}
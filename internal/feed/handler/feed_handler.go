@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	feedService "github.com/ahmadammarm/go-rest-api-template/internal/feed/service"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedHandler struct {
+	feedService feedService.FeedService
+}
+
+func (handler *FeedHandler) sendFeed(context *fiber.Ctx, format string, authorId int, tag string) error {
+	feed, err := handler.feedService.NewsFeed(format, authorId, tag)
+	if err != nil {
+		log.Println("Error generating feed:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	context.Set(fiber.HeaderETag, feed.ETag)
+	context.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if !feed.LastModified.IsZero() {
+		context.Set(fiber.HeaderLastModified, feed.LastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(context, feed) {
+		return context.SendStatus(fiber.StatusNotModified)
+	}
+
+	context.Set(fiber.HeaderContentType, feed.ContentType)
+	return context.Send(feed.Body)
+}
+
+// notModified follows RFC 9110: If-None-Match wins over If-Modified-Since
+// when both are sent.
+func notModified(context *fiber.Ctx, feed *feedService.Feed) bool {
+	if ifNoneMatch := context.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == feed.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := context.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" && !feed.LastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !feed.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+func (handler *FeedHandler) NewsRSS(context *fiber.Ctx) error {
+	return handler.sendFeed(context, feedService.FormatRSS, 0, "")
+}
+
+func (handler *FeedHandler) NewsAtom(context *fiber.Ctx) error {
+	return handler.sendFeed(context, feedService.FormatAtom, 0, "")
+}
+
+func (handler *FeedHandler) AuthorNewsRSS(context *fiber.Ctx) error {
+	authorId, err := strconv.Atoi(context.Params("id"))
+	if err != nil || authorId < 1 {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+	return handler.sendFeed(context, feedService.FormatRSS, authorId, "")
+}
+
+func (handler *FeedHandler) AuthorNewsAtom(context *fiber.Ctx) error {
+	authorId, err := strconv.Atoi(context.Params("id"))
+	if err != nil || authorId < 1 {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+	return handler.sendFeed(context, feedService.FormatAtom, authorId, "")
+}
+
+// tagParam returns the tag of the request, normalized, or false when it is
+// not one news could have.
+func tagParam(context *fiber.Ctx) (string, bool) {
+	tag, err := url.PathUnescape(context.Params("tag"))
+	tag = newsDTO.NormalizeTag(tag)
+	if err != nil || tag == "" || len(tag) > 50 || strings.ContainsAny(tag, ",/") {
+		return "", false
+	}
+	return tag, true
+}
+
+func (handler *FeedHandler) TagNewsRSS(context *fiber.Ctx) error {
+	tag, ok := tagParam(context)
+	if !ok {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+	return handler.sendFeed(context, feedService.FormatRSS, 0, tag)
+}
+
+func (handler *FeedHandler) TagNewsAtom(context *fiber.Ctx) error {
+	tag, ok := tagParam(context)
+	if !ok {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+	return handler.sendFeed(context, feedService.FormatAtom, 0, tag)
+}
+
+func (handler *FeedHandler) FeedRouters(router fiber.Router) {
+	router.Get("/feeds/news.rss", handler.NewsRSS)
+	router.Get("/feeds/news.atom", handler.NewsAtom)
+	router.Get("/feeds/authors/:id/news.rss", handler.AuthorNewsRSS)
+	router.Get("/feeds/authors/:id/news.atom", handler.AuthorNewsAtom)
+	router.Get("/feeds/tags/:tag/news.rss", handler.TagNewsRSS)
+	router.Get("/feeds/tags/:tag/news.atom", handler.TagNewsAtom)
+}
+
+func NewFeedHandler(feedService feedService.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+	}
+}
@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/handler"
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/service"
+)
+
+type stubFeedService struct {
+	feed *service.Feed
+	tag  string
+}
+
+func (stub *stubFeedService) NewsFeed(format string, authorId int, tag string) (*service.Feed, error) {
+	stub.tag = tag
+	return stub.feed, nil
+}
+
+func newStubFeedService() *stubFeedService {
+	return &stubFeedService{feed: &service.Feed{
+		Body:         []byte("<rss></rss>"),
+		ContentType:  "application/rss+xml; charset=utf-8",
+		ETag:         `"abc"`,
+		LastModified: time.Date(2025, 4, 11, 8, 0, 0, 0, time.UTC),
+	}}
+}
+
+func newTestApp() *fiber.App {
+	app := fiber.New()
+	handler.NewFeedHandler(newStubFeedService()).FeedRouters(app)
+	return app
+}
+
+func TestFeedConditionalGet(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{name: "no validators", wantStatus: 200},
+		{name: "matching etag", headers: map[string]string{"If-None-Match": `"xyz", "abc"`}, wantStatus: 304},
+		{name: "weak matching etag", headers: map[string]string{"If-None-Match": `W/"abc"`}, wantStatus: 304},
+		{name: "different etag", headers: map[string]string{"If-None-Match": `"xyz"`}, wantStatus: 200},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Fri, 11 Apr 2025 08:00:00 GMT"}, wantStatus: 304},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Thu, 10 Apr 2025 08:00:00 GMT"}, wantStatus: 200},
+		{
+			name:       "etag wins over date",
+			headers:    map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": "Fri, 11 Apr 2025 08:00:00 GMT"},
+			wantStatus: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/feeds/news.rss", nil)
+			for key, value := range tt.headers {
+				request.Header.Set(key, value)
+			}
+
+			response, err := app.Test(request)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+			assert.Equal(t, `"abc"`, response.Header.Get("ETag"))
+			assert.Equal(t, "Fri, 11 Apr 2025 08:00:00 GMT", response.Header.Get("Last-Modified"))
+		})
+	}
+}
+
+func TestAuthorFeedRejectsInvalidID(t *testing.T) {
+	app := newTestApp()
+
+	response, err := app.Test(httptest.NewRequest("GET", "/feeds/authors/abc/news.atom", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestTagFeed(t *testing.T) {
+	stub := newStubFeedService()
+	app := fiber.New()
+	handler.NewFeedHandler(stub).FeedRouters(app)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTag    string
+	}{
+		{name: "rss", path: "/feeds/tags/go/news.rss", wantStatus: 200, wantTag: "go"},
+		{name: "atom normalizes the tag", path: "/feeds/tags/Open%20Source/news.atom", wantStatus: 200, wantTag: "open source"},
+		{name: "blank tag", path: "/feeds/tags/%20/news.rss", wantStatus: 400},
+		{name: "tag with a comma", path: "/feeds/tags/a,b/news.rss", wantStatus: 400},
+		{name: "tag with a slash", path: "/feeds/tags/a%2Fb/news.atom", wantStatus: 400},
+		{name: "tag too long", path: "/feeds/tags/" + strings.Repeat("a", 51) + "/news.rss", wantStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub.tag = ""
+
+			response, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+			assert.Equal(t, tt.wantTag, stub.tag)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import "github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+
+var feedTags = []string{"Feeds"}
+
+// FeedOperations documents the routes of FeedRouters.
+func (handler *FeedHandler) FeedOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/feeds/news.rss", Summary: "RSS 2.0 feed of all published news", Tags: feedTags,
+			ContentType: "application/rss+xml", Errors: []int{500}},
+		{Method: "GET", Path: "/feeds/news.atom", Summary: "Atom feed of all published news", Tags: feedTags,
+			ContentType: "application/atom+xml", Errors: []int{500}},
+		{Method: "GET", Path: "/feeds/authors/:id/news.rss", Summary: "RSS 2.0 feed of one author", Tags: feedTags,
+			ContentType: "application/rss+xml", Errors: []int{400, 500}},
+		{Method: "GET", Path: "/feeds/authors/:id/news.atom", Summary: "Atom feed of one author", Tags: feedTags,
+			ContentType: "application/atom+xml", Errors: []int{400, 500}},
+		{Method: "GET", Path: "/feeds/tags/:tag/news.rss", Summary: "RSS 2.0 feed of one tag", Tags: feedTags,
+			ContentType: "application/rss+xml", Errors: []int{400, 500}},
+		{Method: "GET", Path: "/feeds/tags/:tag/news.atom", Summary: "Atom feed of one tag", Tags: feedTags,
+			ContentType: "application/atom+xml", Errors: []int{400, 500}},
+	}
+}
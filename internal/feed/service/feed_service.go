@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/model"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	newsRepo "github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+)
+
+// timeLayouts are the layouts news timestamps may be scanned as, depending on
+// the database driver.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"}
+
+type Config struct {
+	Title       string
+	Description string
+	SiteURL     string
+	Language    string
+	ItemLimit   int
+}
+
+type Feed struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+type FeedService interface {
+	// NewsFeed returns the latest news, of one author when authorId is not
+	// 0, or of one tag when tag is not empty.
+	NewsFeed(format string, authorId int, tag string) (*Feed, error)
+}
+
+type feedServiceImpl struct {
+	newsRepo newsRepo.NewsRepository
+	config   Config
+}
+
+func parseTime(value string) time.Time {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// publishedTime falls back to the creation time for rows read without a
+// publication time.
+func publishedTime(n newsDTO.NewsResponse) time.Time {
+	if n.PublishedAt != "" {
+		return parseTime(n.PublishedAt)
+	}
+	return parseTime(n.CreatedAt)
+}
+
+func (service *feedServiceImpl) NewsFeed(format string, authorId int, tag string) (*Feed, error) {
+	news, err := service.newsRepo.GetLatestNews(service.config.ItemLimit, authorId, tag)
+	if err != nil {
+		log.Printf("Error fetching news for feed: %v", err)
+		return nil, fmt.Errorf("error getting news for feed: %w", err)
+	}
+
+	title := service.config.Title
+	selfPath := "/api/v1/feeds/news." + format
+	switch {
+	case authorId != 0:
+		selfPath = fmt.Sprintf("/api/v1/feeds/authors/%d/news.%s", authorId, format)
+		if len(news) > 0 {
+			title = fmt.Sprintf("%s - %s", service.config.Title, news[0].AuthorName)
+		}
+	case tag != "":
+		selfPath = fmt.Sprintf("/api/v1/feeds/tags/%s/news.%s", url.PathEscape(tag), format)
+		title = fmt.Sprintf("%s - #%s", service.config.Title, tag)
+	}
+
+	var lastModified time.Time
+	for _, n := range news {
+		if updated := parseTime(n.UpdatedAt); updated.After(lastModified) {
+			lastModified = updated
+		}
+	}
+
+	var document any
+	var contentType string
+	switch format {
+	case FormatRSS:
+		document = service.rss(news, title, selfPath, lastModified)
+		contentType = "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		document = service.atom(news, title, selfPath, lastModified)
+		contentType = "application/atom+xml; charset=utf-8"
+	default:
+		return nil, errors.New("unknown feed format")
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding feed: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	hash := sha256.Sum256(body)
+
+	return &Feed{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         `"` + hex.EncodeToString(hash[:16]) + `"`,
+		LastModified: lastModified,
+	}, nil
+}
+
+func (service *feedServiceImpl) newsLink(id int) string {
+	return fmt.Sprintf("%s/news/%d", service.config.SiteURL, id)
+}
+
+func (service *feedServiceImpl) rss(news []newsDTO.NewsResponse, title string, selfPath string, lastModified time.Time) model.RSS {
+	channel := model.RSSChannel{
+		Title:       title,
+		Link:        service.config.SiteURL,
+		Description: service.config.Description,
+		Language:    service.config.Language,
+		SelfLink: model.AtomLink{
+			Href: service.config.SiteURL + selfPath,
+			Rel:  "self",
+			Type: "application/rss+xml",
+		},
+		Items: []model.RSSItem{},
+	}
+
+	if !lastModified.IsZero() {
+		channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+	}
+
+	for _, n := range news {
+		link := service.newsLink(n.ID)
+		channel.Items = append(channel.Items, model.RSSItem{
+			Title:       n.Title,
+			Link:        link,
+			GUID:        model.RSSGUID{IsPermaLink: "true", Value: link},
+			Description: n.Content,
+			Creator:     n.AuthorName,
+			PubDate:     publishedTime(n).Format(time.RFC1123Z),
+		})
+	}
+
+	return model.RSS{
+		Version:  "2.0",
+		AtomNS:   "http://www.w3.org/2005/Atom",
+		DublinNS: "http://purl.org/dc/elements/1.1/",
+		Channel:  channel,
+	}
+}
+
+func (service *feedServiceImpl) atom(news []newsDTO.NewsResponse, title string, selfPath string, lastModified time.Time) model.AtomFeed {
+	feed := model.AtomFeed{
+		Title:    title,
+		Subtitle: service.config.Description,
+		ID:       service.config.SiteURL + selfPath,
+		Updated:  lastModified.Format(time.RFC3339),
+		Links: []model.AtomLink{
+			{Href: service.config.SiteURL + selfPath, Rel: "self", Type: "application/atom+xml"},
+			{Href: service.config.SiteURL, Rel: "alternate"},
+		},
+		Entries: []model.AtomEntry{},
+	}
+
+	for _, n := range news {
+		link := service.newsLink(n.ID)
+		feed.Entries = append(feed.Entries, model.AtomEntry{
+			Title:     n.Title,
+			ID:        link,
+			Link:      model.AtomLink{Href: link, Rel: "alternate"},
+			Published: publishedTime(n).Format(time.RFC3339),
+			Updated:   parseTime(n.UpdatedAt).Format(time.RFC3339),
+			Author:    model.AtomAuthor{Name: n.AuthorName},
+			Content:   model.AtomContent{Type: "text", Value: n.Content},
+		})
+	}
+
+	return feed
+}
+
+func NewFeedService(newsRepo newsRepo.NewsRepository, config Config) FeedService {
+	log.Println("Initializing FeedService...")
+	return &feedServiceImpl{
+		newsRepo: newsRepo,
+		config:   config,
+	}
+}
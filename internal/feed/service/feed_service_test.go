@@ -0,0 +1,197 @@
+package service_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/feed/service"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+type MockNewsRepository struct {
+	mock.Mock
+}
+
+// WithTx returns the mock itself, which expects the calls made in tx.
+func (m *MockNewsRepository) WithTx(tx *database.Tx) repository.NewsRepository {
+	return m
+}
+
+func (m *MockNewsRepository) GetAllNews() (*dto.NewsListResponse, error) {
+	return nil, nil
+}
+
+func (m *MockNewsRepository) GetNewsById(id int) (*dto.NewsResponse, error) {
+	return nil, nil
+}
+
+func (m *MockNewsRepository) CreateNews(news *dto.NewsCreateRequest) error {
+	return nil
+}
+
+func (m *MockNewsRepository) UpdateNews(id int, news dto.NewsUpdateRequest) error {
+	return nil
+}
+
+func (m *MockNewsRepository) DeleteNews(id int) error {
+	return nil
+}
+
+func (m *MockNewsRepository) GetPublishedNews() (*dto.NewsListResponse, error) {
+	return nil, nil
+}
+
+func (m *MockNewsRepository) GetPublishedNewsById(id int) (*dto.NewsResponse, error) {
+	return nil, nil
+}
+
+func (m *MockNewsRepository) PublishNews(id int, publishedAt *time.Time) error {
+	return nil
+}
+
+func (m *MockNewsRepository) AnnounceDueNews() ([]int, error) {
+	args := m.Called()
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockNewsRepository) GetLatestNews(limit int, authorId int, tag string) ([]dto.NewsResponse, error) {
+	args := m.Called(limit, authorId, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.NewsResponse), args.Error(1)
+}
+
+var testConfig = service.Config{
+	Title:       "Test News",
+	Description: "Latest test news",
+	SiteURL:     "https://example.com",
+	Language:    "en",
+	ItemLimit:   10,
+}
+
+var testNews = []dto.NewsResponse{
+	{
+		ID:         2,
+		Title:      "Fish & Chips",
+		Content:    "<p>Tom said \"hi\" & left</p>",
+		AuthorId:   7,
+		AuthorName: "Admin",
+		CreatedAt:  "2025-04-10T15:18:19.219176Z",
+		UpdatedAt:  "2025-04-11T08:00:00Z",
+	},
+	{
+		ID:         1,
+		Title:      "Oke",
+		Content:    "Oke adalah berita terkini",
+		AuthorId:   7,
+		AuthorName: "Admin",
+		CreatedAt:  "2025-04-10T15:13:28.934175Z",
+		UpdatedAt:  "2025-04-10T15:13:28.934175Z",
+	},
+}
+
+func TestNewsFeedRSS(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	feedService := service.NewFeedService(mockRepo, testConfig)
+
+	mockRepo.On("GetLatestNews", 10, 0, "").Return(testNews, nil).Once()
+
+	feed, err := feedService.NewsFeed(service.FormatRSS, 0, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", feed.ContentType)
+	assert.Equal(t, time.Date(2025, 4, 11, 8, 0, 0, 0, time.UTC), feed.LastModified)
+	assert.NotContains(t, string(feed.Body), "<p>")
+
+	var rss model.RSS
+	assert.NoError(t, xml.Unmarshal(feed.Body, &rss))
+	assert.Len(t, rss.Channel.Items, 2)
+	assert.Equal(t, "Fish & Chips", rss.Channel.Items[0].Title)
+	assert.Equal(t, testNews[0].Content, rss.Channel.Items[0].Description)
+	assert.Equal(t, "https://example.com/news/2", rss.Channel.Items[0].Link)
+	assert.Equal(t, "Thu, 10 Apr 2025 15:18:19 +0000", rss.Channel.Items[0].PubDate)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewsFeedAtomForAuthor(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	feedService := service.NewFeedService(mockRepo, testConfig)
+
+	mockRepo.On("GetLatestNews", 10, 7, "").Return(testNews, nil).Once()
+
+	feed, err := feedService.NewsFeed(service.FormatAtom, 7, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", feed.ContentType)
+
+	var atom model.AtomFeed
+	assert.NoError(t, xml.Unmarshal(feed.Body, &atom))
+	assert.Equal(t, "Test News - Admin", atom.Title)
+	assert.Equal(t, "https://example.com/api/v1/feeds/authors/7/news.atom", atom.ID)
+	assert.Equal(t, "2025-04-11T08:00:00Z", atom.Updated)
+	assert.Len(t, atom.Entries, 2)
+	assert.Equal(t, testNews[0].Content, atom.Entries[0].Content.Value)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewsFeedRSSForTag(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	feedService := service.NewFeedService(mockRepo, testConfig)
+
+	mockRepo.On("GetLatestNews", 10, 0, "open source").Return(testNews, nil).Once()
+
+	feed, err := feedService.NewsFeed(service.FormatRSS, 0, "open source")
+
+	assert.NoError(t, err)
+
+	var rss model.RSS
+	assert.NoError(t, xml.Unmarshal(feed.Body, &rss))
+	assert.Equal(t, "Test News - #open source", rss.Channel.Title)
+	assert.Contains(t, string(feed.Body), "https://example.com/api/v1/feeds/tags/open%20source/news.rss")
+	assert.Len(t, rss.Channel.Items, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewsFeedETagIsStable(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	feedService := service.NewFeedService(mockRepo, testConfig)
+
+	mockRepo.On("GetLatestNews", 10, 0, "").Return(testNews, nil).Twice()
+
+	first, _ := feedService.NewsFeed(service.FormatRSS, 0, "")
+	second, _ := feedService.NewsFeed(service.FormatRSS, 0, "")
+
+	assert.Equal(t, first.ETag, second.ETag)
+	assert.True(t, strings.HasPrefix(first.ETag, `"`))
+}
+
+func TestNewsFeedErrors(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	feedService := service.NewFeedService(mockRepo, testConfig)
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.On("GetLatestNews", 10, 0, "").Return(nil, errors.New("database error")).Once()
+
+		feed, err := feedService.NewsFeed(service.FormatRSS, 0, "")
+		assert.Error(t, err)
+		assert.Nil(t, feed)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		mockRepo.On("GetLatestNews", 10, 0, "").Return([]dto.NewsResponse{}, nil).Once()
+
+		feed, err := feedService.NewsFeed("json", 0, "")
+		assert.Error(t, err)
+		assert.Nil(t, feed)
+	})
+}
@@ -0,0 +1,136 @@
+package fixtures
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// DefaultPassword is the password of the users a Factory builds.
+const DefaultPassword = "password123"
+
+var (
+	firstNames = []string{"Ahmad", "Ammar", "Ayu", "Budi", "Citra", "Dewi", "Eko", "Fajar", "Gita", "Hana",
+		"Indra", "Joko", "Kartika", "Lestari", "Maya", "Nur", "Putri", "Rizki", "Sari", "Taufik", "Wulan", "Yusuf"}
+	lastNames = []string{"Pratama", "Saputra", "Wijaya", "Santoso", "Hidayat", "Kusuma", "Nugroho", "Lestari",
+		"Permata", "Siregar", "Nasution", "Hutapea", "Wibowo", "Utami", "Setiawan", "Rahmawati"}
+
+	subjects = []string{"The city council", "Local farmers", "A new study", "The national team", "Startups",
+		"Researchers", "The central bank", "Commuters", "Teachers", "The health ministry", "Volunteers", "Fishermen"}
+	verbs = []string{"announce", "debate", "launch", "delay", "celebrate", "review", "expand", "question",
+		"support", "prepare", "welcome", "reject"}
+	objects = []string{"a new railway line", "the rice harvest", "flood defences", "the budget for next year",
+		"a digital payment scheme", "the school calendar", "cleaner public transport", "a coastal clean-up",
+		"higher fuel prices", "the regional elections", "a vaccination drive", "the night market"}
+	details = []string{"after months of talks", "despite heavy rain", "ahead of the holidays", "in the capital",
+		"across the islands", "for the first time", "amid rising costs", "with mixed reactions"}
+)
+
+// Factory builds users and news with realistic values, unique thanks to a
+// sequence. The values depend on its seed only, so that a seed reproduces
+// the same set.
+type Factory struct {
+	rand     *rand.Rand
+	sequence int
+}
+
+// NewFactory returns a factory whose values are drawn from seed.
+func NewFactory(seed uint64) *Factory {
+	return &Factory{rand: rand.New(rand.NewPCG(seed, 0))}
+}
+
+// Skip advances the sequence by n, e.g. past the users already in the
+// database, whose emails are then not built again.
+func (factory *Factory) Skip(n int) {
+	factory.sequence += n
+}
+
+func (factory *Factory) next() int {
+	factory.sequence++
+	return factory.sequence
+}
+
+func (factory *Factory) pick(words []string) string {
+	return words[factory.rand.IntN(len(words))]
+}
+
+// User builds a user signing in with DefaultPassword, changed by overrides.
+func (factory *Factory) User(overrides ...func(user *User)) *User {
+	first, last := factory.pick(firstNames), factory.pick(lastNames)
+	user := &User{
+		Email:    fmt.Sprintf("%s.%s.%d@example.com", strings.ToLower(first), strings.ToLower(last), factory.next()),
+		Name:     first + " " + last,
+		Password: DefaultPassword,
+	}
+
+	for _, override := range overrides {
+		override(user)
+	}
+	return user
+}
+
+// News builds a news item by author, published within the last 30 days,
+// changed by overrides.
+func (factory *Factory) News(author *User, overrides ...func(news *News)) *News {
+	publishedAt := time.Now().UTC().Truncate(time.Second).
+		Add(-time.Duration(factory.rand.Int64N(int64(30 * 24 * time.Hour))))
+
+	paragraphs := make([]string, 2+factory.rand.IntN(3))
+	for i := range paragraphs {
+		sentences := make([]string, 3+factory.rand.IntN(4))
+		for j := range sentences {
+			sentences[j] = factory.sentence() + "."
+		}
+		paragraphs[i] = strings.Join(sentences, " ")
+	}
+
+	news := &News{
+		Title:       fmt.Sprintf("%s (%d)", factory.sentence(), factory.next()),
+		Content:     strings.Join(paragraphs, "\n\n"),
+		User:        author,
+		PublishedAt: &publishedAt,
+	}
+
+	for _, override := range overrides {
+		override(news)
+	}
+	return news
+}
+
+// sentence returns a headline-like sentence of at most 90 characters, so
+// that it fits a title with its sequence.
+func (factory *Factory) sentence() string {
+	sentence := fmt.Sprintf("%s %s %s", factory.pick(subjects), factory.pick(verbs), factory.pick(objects))
+	if detail := factory.pick(details); len(sentence)+len(detail) < 90 {
+		sentence += " " + detail
+	}
+	return sentence
+}
+
+// Set builds users and news spread over them at random. The first user is an
+// admin.
+func (factory *Factory) Set(users int, news int) *Set {
+	set := &Set{}
+	for i := 0; i < users; i++ {
+		set.Users = append(set.Users, factory.User(func(user *User) { user.Admin = i == 0 }))
+	}
+	if users == 0 {
+		return set
+	}
+
+	for i := 0; i < news; i++ {
+		set.News = append(set.News, factory.News(set.Users[factory.rand.IntN(users)]))
+	}
+	return set
+}
+
+// Admin makes a user an admin.
+func Admin(user *User) {
+	user.Admin = true
+}
+
+// Draft makes a news item a draft.
+func Draft(news *News) {
+	news.PublishedAt = nil
+}
@@ -0,0 +1,87 @@
+package fixtures_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestFactory(t *testing.T) {
+	factory := fixtures.NewFactory(1)
+
+	user := factory.User()
+	assert.Regexp(t, `^[a-z]+\.[a-z]+\.1@example\.com$`, user.Email)
+	assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+$`, user.Name)
+	assert.Equal(t, fixtures.DefaultPassword, user.Password)
+	assert.False(t, user.Admin)
+	assert.Zero(t, user.ID)
+
+	news := factory.News(user)
+	assert.True(t, strings.HasSuffix(news.Title, " (2)"))
+	assert.LessOrEqual(t, len(news.Title), 100)
+	assert.Contains(t, news.Content, "\n\n")
+	assert.Same(t, user, news.User)
+	assert.WithinDuration(t, time.Now(), *news.PublishedAt, 30*24*time.Hour)
+
+	t.Run("overrides", func(t *testing.T) {
+		admin := factory.User(fixtures.Admin, func(user *fixtures.User) { user.Name = "Ammar" })
+		assert.True(t, admin.Admin)
+		assert.Equal(t, "Ammar", admin.Name)
+
+		draft := factory.News(admin, fixtures.Draft)
+		assert.Nil(t, draft.PublishedAt)
+	})
+
+	t.Run("same seed, same values", func(t *testing.T) {
+		assert.Equal(t, fixtures.NewFactory(7).Set(3, 5), fixtures.NewFactory(7).Set(3, 5))
+		assert.NotEqual(t, fixtures.NewFactory(7).User().Email, fixtures.NewFactory(8).User().Email)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		skipping := fixtures.NewFactory(1)
+		skipping.Skip(100)
+		assert.True(t, strings.HasSuffix(skipping.User().Email, ".101@example.com"))
+	})
+}
+
+func TestFactorySet(t *testing.T) {
+	set := fixtures.NewFactory(1).Set(3, 10)
+	assert.Len(t, set.Users, 3)
+	assert.Len(t, set.News, 10)
+	assert.True(t, set.Users[0].Admin)
+	assert.False(t, set.Users[1].Admin)
+
+	emails := map[string]bool{}
+	for _, user := range set.Users {
+		emails[user.Email] = true
+	}
+	assert.Len(t, emails, 3)
+	for _, news := range set.News {
+		assert.Contains(t, set.Users, news.User)
+	}
+
+	assert.Empty(t, fixtures.NewFactory(1).Set(0, 10).News)
+}
+
+func TestLoadFactoryGraph(t *testing.T) {
+	db := databasetest.SQLite(t)
+	factory := fixtures.NewFactory(1)
+
+	// The author is in no list of the set: it is loaded with its news.
+	author := factory.User()
+	set := &fixtures.Set{News: []*fixtures.News{factory.News(author), factory.News(author, fixtures.Draft)}}
+	assert.NoError(t, fixtures.Load(context.Background(), db, set))
+	assert.NotZero(t, author.ID)
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news WHERE user_id = $1`, author.ID).Scan(&count))
+	assert.Equal(t, 2, count)
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM users`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,200 @@
+// Package fixtures loads users and news into a database, for development,
+// load testing and tests.
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Set is a set of users and their news.
+type Set struct {
+	Users []*User `json:"users" yaml:"users"`
+	News  []*News `json:"news" yaml:"news"`
+}
+
+// User is an account. Its password is hashed when it is loaded.
+type User struct {
+	ID       int    `json:"-" yaml:"-"`
+	Email    string `json:"email" yaml:"email"`
+	Name     string `json:"name" yaml:"name"`
+	Password string `json:"password" yaml:"password"`
+	Admin    bool   `json:"admin" yaml:"admin"`
+}
+
+// News is a news item by User or, in files, by Author, the email of a user of
+// the set or of the database. It is a draft without PublishedAt.
+type News struct {
+	ID          int        `json:"-" yaml:"-"`
+	Title       string     `json:"title" yaml:"title"`
+	Content     string     `json:"content" yaml:"content"`
+	Author      string     `json:"author" yaml:"author"`
+	User        *User      `json:"-" yaml:"-"`
+	PublishedAt *time.Time `json:"published_at" yaml:"published_at"`
+}
+
+// Read reads a set from a YAML (.yaml, .yml) or JSON (.json) file.
+func Read(path string) (*Set, error) {
+	var decode func(content []byte, set *Set) error
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		decode = func(content []byte, set *Set) error {
+			decoder := yaml.NewDecoder(bytes.NewReader(content))
+			decoder.KnownFields(true)
+			return decoder.Decode(set)
+		}
+	case ".json":
+		decode = func(content []byte, set *Set) error {
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.DisallowUnknownFields()
+			return decoder.Decode(set)
+		}
+	default:
+		return nil, fmt.Errorf("unknown fixture format %q: use .yaml, .yml or .json", ext)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &Set{}
+	if err := decode(content, set); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return set, nil
+}
+
+// Load inserts the users and news of set in one transaction, and sets their
+// IDs. The User of a news item is inserted with it when it has no ID yet, so
+// that a graph built by a Factory loads whole. Users whose email is taken,
+// and news their author already has under the same title, are left as they
+// are, so that loading a set again inserts nothing. It writes the tables
+// directly: no domain events are emitted and no cache is invalidated.
+func Load(ctx context.Context, db *sql.DB, set *Set) error {
+	return database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+		loader := &loader{ctx: ctx, tx: tx, authors: map[string]int{}, hashes: map[string][]byte{}}
+
+		for _, user := range set.Users {
+			if err := loader.user(user); err != nil {
+				return err
+			}
+		}
+
+		for _, news := range set.News {
+			if err := loader.news(news); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+type loader struct {
+	ctx context.Context
+	tx  *database.Tx
+	// authors are the IDs of the users by email.
+	authors map[string]int
+	// hashes are the hashed passwords: fixtures mostly share theirs, which
+	// are slow to hash.
+	hashes map[string][]byte
+}
+
+func (loader *loader) user(user *User) error {
+	err := loader.tx.QueryRowContext(loader.ctx, `SELECT id FROM users WHERE email = $1`, user.Email).Scan(&user.ID)
+	if err == nil {
+		loader.authors[user.Email] = user.ID
+		return nil
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("user %s: %w", user.Email, err)
+	}
+
+	hash, ok := loader.hashes[user.Password]
+	if !ok {
+		var err error
+		if hash, err = bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost); err != nil {
+			return err
+		}
+		loader.hashes[user.Password] = hash
+	}
+
+	err = loader.tx.QueryRowContext(loader.ctx, `INSERT INTO users (email, name, password, is_admin) VALUES ($1, $2, $3, $4) RETURNING id`,
+		user.Email, user.Name, string(hash), user.Admin).Scan(&user.ID)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", user.Email, err)
+	}
+
+	loader.authors[user.Email] = user.ID
+	return nil
+}
+
+func (loader *loader) news(news *News) error {
+	authorID, err := loader.author(news)
+	if err != nil {
+		return err
+	}
+
+	err = loader.tx.QueryRowContext(loader.ctx, `SELECT id FROM news WHERE user_id = $1 AND title = $2 ORDER BY id LIMIT 1`,
+		authorID, news.Title).Scan(&news.ID)
+	if err == nil {
+		return nil
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("news %q: %w", news.Title, err)
+	}
+
+	// News already public is marked as announced, so that no news.published
+	// event is sent for it later either. News scheduled for later is
+	// announced when its time comes.
+	var publishedEventAt *time.Time
+	if news.PublishedAt != nil && !news.PublishedAt.After(time.Now()) {
+		publishedEventAt = news.PublishedAt
+	}
+
+	err = loader.tx.QueryRowContext(loader.ctx, `INSERT INTO news (title, content, user_id, published_at, published_event_at)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		news.Title, news.Content, authorID, news.PublishedAt, publishedEventAt).Scan(&news.ID)
+	if err != nil {
+		return fmt.Errorf("news %q: %w", news.Title, err)
+	}
+	return nil
+}
+
+// author returns the ID of the author of news, inserting its User first when
+// needed.
+func (loader *loader) author(news *News) (int, error) {
+	if news.User != nil {
+		if news.User.ID == 0 {
+			if err := loader.user(news.User); err != nil {
+				return 0, err
+			}
+		}
+		return news.User.ID, nil
+	}
+
+	if authorID, ok := loader.authors[news.Author]; ok {
+		return authorID, nil
+	}
+
+	var authorID int
+	err := loader.tx.QueryRowContext(loader.ctx, `SELECT id FROM users WHERE email = $1`, news.Author).Scan(&authorID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("news %q: author %s not found", news.Title, news.Author)
+	} else if err != nil {
+		return 0, err
+	}
+
+	loader.authors[news.Author] = authorID
+	return authorID, nil
+}
@@ -0,0 +1,113 @@
+package fixtures_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestRead(t *testing.T) {
+	yamlSet, err := fixtures.Read("../../seeds/development.yaml")
+	assert.NoError(t, err)
+	assert.Len(t, yamlSet.Users, 2)
+	assert.True(t, yamlSet.Users[0].Admin)
+	assert.Equal(t, time.Date(2025, 4, 10, 15, 13, 28, 0, time.UTC), *yamlSet.News[0].PublishedAt)
+	assert.Nil(t, yamlSet.News[1].PublishedAt)
+
+	jsonSet, err := fixtures.Read("testdata/set.json")
+	assert.NoError(t, err)
+	assert.Equal(t, yamlSet.Users[0], jsonSet.Users[0])
+	assert.Equal(t, yamlSet.News[0], jsonSet.News[0])
+
+	t.Run("unknown field", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "set.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte("users:\n  - email: a@mail.com\n    role: admin\n"), 0o644))
+
+		_, err := fixtures.Read(path)
+		assert.ErrorContains(t, err, "field role not found")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := fixtures.Read("set.csv")
+		assert.ErrorContains(t, err, `unknown fixture format ".csv"`)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.SQLite(t)
+
+	set, err := fixtures.Read("../../seeds/development.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, fixtures.Load(ctx, db, set))
+	assert.NotZero(t, set.Users[1].ID)
+	assert.NotZero(t, set.News[1].ID)
+
+	var password string
+	var admin bool
+	assert.NoError(t, db.QueryRow(`SELECT password, is_admin FROM users WHERE id = $1`, set.Users[0].ID).Scan(&password, &admin))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(password), []byte("password123")))
+	assert.True(t, admin)
+
+	var authorID int
+	assert.NoError(t, db.QueryRow(`SELECT user_id FROM news WHERE id = $1`, set.News[1].ID).Scan(&authorID))
+	assert.Equal(t, set.Users[1].ID, authorID)
+
+	t.Run("authors in the database", func(t *testing.T) {
+		more := &fixtures.Set{News: []*fixtures.News{{Title: "Lagi", Content: "Berita lagi", Author: "ammar@mail.com"}}}
+		assert.NoError(t, fixtures.Load(ctx, db, more))
+		assert.NotZero(t, more.News[0].ID)
+	})
+
+	t.Run("announces only public news", func(t *testing.T) {
+		past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		scheduled := &fixtures.Set{News: []*fixtures.News{
+			{Title: "Sudah", Content: "Sudah terbit", Author: "ammar@mail.com", PublishedAt: &past},
+			{Title: "Nanti", Content: "Terbit nanti", Author: "ammar@mail.com", PublishedAt: &future},
+		}}
+		assert.NoError(t, fixtures.Load(ctx, db, scheduled))
+
+		var announced []bool
+		for _, news := range scheduled.News {
+			var publishedEventAt sql.NullTime
+			assert.NoError(t, db.QueryRow(`SELECT published_event_at FROM news WHERE id = $1`, news.ID).Scan(&publishedEventAt))
+			announced = append(announced, publishedEventAt.Valid)
+		}
+		assert.Equal(t, []bool{true, false}, announced)
+	})
+
+	t.Run("loaded again", func(t *testing.T) {
+		again, err := fixtures.Read("../../seeds/development.yaml")
+		assert.NoError(t, err)
+		assert.NoError(t, fixtures.Load(ctx, db, again))
+		assert.Equal(t, set.Users[1].ID, again.Users[1].ID)
+		assert.Equal(t, set.News[1].ID, again.News[1].ID)
+
+		var count int
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM users`).Scan(&count))
+		assert.Equal(t, 2, count)
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news WHERE title = $1`, set.News[0].Title).Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		failing := &fixtures.Set{
+			Users: []*fixtures.User{{Email: "new@mail.com", Name: "New", Password: "password123"}},
+			News:  []*fixtures.News{{Title: "Orphan", Content: "No author", Author: "missing@mail.com"}},
+		}
+		assert.EqualError(t, fixtures.Load(ctx, db, failing), `news "Orphan": author missing@mail.com not found`)
+
+		var count int
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM users WHERE email = $1`, "new@mail.com").Scan(&count))
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,40 @@
+package dependency_injection
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/internal/graphql/handler"
+	"github.com/ahmadammarm/go-rest-api-template/internal/graphql/schema"
+	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+)
+
+func InitializeGraphQL(db *sql.DB, reads database.Querier, avatarStorage storage.Storage, userHooks users.Hooks, newsHooks news.Hooks) (*handler.GraphQLHandler, error) {
+	userService := users.NewUserService(db, reads, avatarStorage, userHooks)
+	newsService := news.NewNewsService(db, reads, newsHooks)
+
+	graphQLSchema, err := schema.New(userService, newsService)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := schema.Limits{
+		MaxDepth:      envInt("GRAPHQL_MAX_DEPTH", 8),
+		MaxComplexity: envInt("GRAPHQL_MAX_COMPLEXITY", 500),
+		ListFactor:    envInt("GRAPHQL_LIST_FACTOR", 10),
+	}
+
+	return handler.NewGraphQLHandler(graphQLSchema, userService, limits), nil
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(config.GetEnv(key, strconv.Itoa(fallback)))
+	if err != nil || value < 1 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/graphql/handler"
+	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the users and news over GraphQL, with the hooks of the user
+// and news modules.
+type Module struct {
+	app.Base
+	handler *handler.GraphQLHandler
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "graphql"
+}
+
+func (module *Module) DependsOn() []string {
+	return []string{"user", "news"}
+}
+
+func (module *Module) Init(c *app.Container) error {
+	userModule, err := app.Lookup[*users.Module](c, "user")
+	if err != nil {
+		return err
+	}
+	newsModule, err := app.Lookup[*news.Module](c, "news")
+	if err != nil {
+		return err
+	}
+
+	module.handler, err = InitializeGraphQL(c.DB, c.Reads, c.Store, userModule.Hooks, newsModule.Hooks)
+	return err
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Versioned.Add(module.handler.GraphQLRouters, module.handler.GraphQLOperations())
+}
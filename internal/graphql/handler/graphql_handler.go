@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"log"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/graphql/schema"
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL request sent as JSON.
+type Request struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type GraphQLHandler struct {
+	schema      graphql.Schema
+	userService userService.UserService
+	limits      schema.Limits
+}
+
+// Serve answers with a GraphQL response rather than the response.Response
+// envelope, so that GraphQL clients can be used as they are.
+func (handler *GraphQLHandler) Serve(context *fiber.Ctx) error {
+	var request Request
+	if err := context.BodyParser(&request); err != nil || request.Query == "" {
+		return badRequest(context, "a JSON body with a query is required")
+	}
+
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return context.Status(400).JSON(graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+
+	if err := schema.Check(handler.schema, document, request.OperationName, handler.limits); err != nil {
+		log.Println("Rejected GraphQL request:", err)
+		return badRequest(context, err.Error())
+	}
+
+	// Set by OptionalJWTAuth when the caller sent a token.
+	viewerId, _ := context.Locals("user_id").(int)
+
+	result := graphql.Do(graphql.Params{
+		Schema:         handler.schema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        schema.NewRequestContext(context.UserContext(), viewerId, handler.userService),
+	})
+
+	return context.JSON(result)
+}
+
+func badRequest(context *fiber.Ctx, message string) error {
+	return context.Status(400).JSON(graphql.Result{
+		Errors: []gqlerrors.FormattedError{{Message: message}},
+	})
+}
+
+func (handler *GraphQLHandler) GraphQLRouters(router fiber.Router) {
+	router.Post("/graphql", middleware.OptionalJWTAuth(), handler.Serve)
+}
+
+func NewGraphQLHandler(schema graphql.Schema, userService userService.UserService, limits schema.Limits) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema:      schema,
+		userService: userService,
+		limits:      limits,
+	}
+}
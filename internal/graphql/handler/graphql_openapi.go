@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+
+// GraphQLOperations documents the routes of GraphQLRouters.
+func (handler *GraphQLHandler) GraphQLOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "POST", Path: "/graphql", Summary: "Run a GraphQL query or mutation", Tags: []string{"GraphQL"}, Security: openapi.OptionalBearerAuth,
+			Request: Request{}, ContentType: "application/json", Errors: []int{400}},
+	}
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound how expensive a single request may be.
+type Limits struct {
+	// MaxDepth is the deepest field nesting allowed, top-level fields being
+	// at depth 1.
+	MaxDepth int
+	// MaxComplexity caps the cost of a request. Every field costs 1, and the
+	// cost below a list field is multiplied by ListFactor.
+	MaxComplexity int
+	ListFactor    int
+}
+
+// Check measures the operation of document that would be executed and
+// rejects it when it goes over limits. Introspection fields are not counted.
+func Check(schema graphql.Schema, document *ast.Document, operationName string, limits Limits) error {
+	measure := &measurer{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		factor:    limits.ListFactor,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			measure.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		// Left to the executor, which reports a missing operation.
+		return nil
+	}
+
+	var root graphql.Type = schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	depth, cost := measure.selectionSet(operation.SelectionSet, root, 1, map[string]bool{})
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", cost, limits.MaxComplexity)
+	}
+
+	return nil
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	factor    int
+}
+
+// selectionSet returns the deepest level reached below set and its cost.
+// Fragments being expanded are tracked in visiting, so cycles (which the
+// validator rejects anyway) cannot recurse forever.
+func (measure *measurer) selectionSet(set *ast.SelectionSet, parent graphql.Type, depth int, visiting map[string]bool) (int, int) {
+	if set == nil {
+		return depth - 1, 0
+	}
+
+	maxDepth, cost := depth, 0
+	for _, selection := range set.Selections {
+		var childDepth, childCost int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+
+			fieldType, multiplier := measure.fieldType(parent, selection.Name.Value)
+			childDepth, childCost = depth, 1
+			if selection.SelectionSet != nil {
+				subDepth, subCost := measure.selectionSet(selection.SelectionSet, fieldType, depth+1, visiting)
+				childDepth, childCost = subDepth, 1+multiplier*subCost
+			}
+		case *ast.InlineFragment:
+			childDepth, childCost = measure.selectionSet(selection.SelectionSet, measure.typeCondition(selection.TypeCondition, parent), depth, visiting)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := measure.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			childDepth, childCost = measure.selectionSet(fragment.SelectionSet, measure.typeCondition(fragment.TypeCondition, parent), depth, visiting)
+			delete(visiting, name)
+		}
+
+		maxDepth = max(maxDepth, childDepth)
+		cost += childCost
+	}
+
+	return maxDepth, cost
+}
+
+// fieldType returns the named type of a field and the cost multiplier of its
+// selections: ListFactor for lists, 1 otherwise.
+func (measure *measurer) fieldType(parent graphql.Type, name string) (graphql.Type, int) {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil, 1
+	}
+	field, ok := object.Fields()[name]
+	if !ok {
+		return nil, 1
+	}
+
+	fieldType, multiplier := field.Type, 1
+	for {
+		switch wrapped := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = wrapped.OfType
+		case *graphql.List:
+			fieldType = wrapped.OfType
+			if measure.factor > 0 {
+				multiplier *= measure.factor
+			}
+		default:
+			return fieldType, multiplier
+		}
+	}
+}
+
+func (measure *measurer) typeCondition(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	return measure.schema.Type(condition.Name.Value)
+}
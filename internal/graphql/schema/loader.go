@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"context"
+	"time"
+
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait is how long a loader collects keys before running a batch. The
+// executor resolves every field of a level before waiting on any of them, so
+// a short wait is enough.
+const loaderWait = 2 * time.Millisecond
+
+type loaders struct {
+	users *dataloader.Loader[int, *userDTO.UserResponse]
+}
+
+func newLoaders(userService userService.UserService) *loaders {
+	return &loaders{
+		users: dataloader.NewBatchedLoader(usersBatch(userService), dataloader.WithWait[int, *userDTO.UserResponse](loaderWait)),
+	}
+}
+
+// usersBatch fetches the users of one batch in a single query.
+func usersBatch(service userService.UserService) dataloader.BatchFunc[int, *userDTO.UserResponse] {
+	return func(ctx context.Context, ids []int) []*dataloader.Result[*userDTO.UserResponse] {
+		results := make([]*dataloader.Result[*userDTO.UserResponse], len(ids))
+
+		users, err := service.GetUsersByIDs(ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*userDTO.UserResponse]{Error: err}
+			}
+			return results
+		}
+
+		byId := make(map[int]*userDTO.UserResponse, len(users))
+		for i := range users {
+			byId[users[i].ID] = &users[i]
+		}
+
+		for i, id := range ids {
+			if user, ok := byId[id]; ok {
+				results[i] = &dataloader.Result[*userDTO.UserResponse]{Data: user}
+			} else {
+				results[i] = &dataloader.Result[*userDTO.UserResponse]{Error: userService.ErrNotFound}
+			}
+		}
+		return results
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
@@ -0,0 +1,328 @@
+// Package schema defines the GraphQL schema served at /graphql. Resolvers go
+// through the user and news services, like the REST handlers do.
+package schema
+
+import (
+	"context"
+	"errors"
+
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/graphql-go/graphql"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+type resolver struct {
+	userService userService.UserService
+	newsService newsService.NewsService
+}
+
+// New builds the schema. Requests must carry a context made with
+// NewRequestContext.
+func New(userService userService.UserService, newsService newsService.NewsService) (graphql.Schema, error) {
+	r := &resolver{userService: userService, newsService: newsService}
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"bio":       &graphql.Field{Type: graphql.String},
+			"avatarUrl": &graphql.Field{Type: graphql.String, Resolve: userField(func(u *userDTO.UserResponse) any { return u.AvatarURL })},
+			"website":   &graphql.Field{Type: graphql.String},
+			"locale":    &graphql.Field{Type: graphql.String},
+			"timezone":  &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	reactionsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Reactions",
+		Fields: graphql.Fields{
+			"like":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"love":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"laugh": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"wow":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"sad":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"angry": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	newsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "News",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"content": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"authorId": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Int),
+				Resolve: newsField(func(n *newsDTO.NewsResponse) any { return n.AuthorId }),
+			},
+			"author": &graphql.Field{
+				Type:    userType,
+				Resolve: r.newsAuthor,
+			},
+			"reactions": &graphql.Field{
+				Type:    graphql.NewNonNull(reactionsType),
+				Resolve: newsField(func(n *newsDTO.NewsResponse) any { return n.Reactions }),
+			},
+			"publishedAt": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: newsField(func(n *newsDTO.NewsResponse) any { return nullable(n.PublishedAt) }),
+			},
+			"createdAt": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.String),
+				Resolve: newsField(func(n *newsDTO.NewsResponse) any { return n.CreatedAt }),
+			},
+			"updatedAt": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.String),
+				Resolve: newsField(func(n *newsDTO.NewsResponse) any { return n.UpdatedAt }),
+			},
+		},
+	})
+
+	newsInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "NewsInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"content": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"news": &graphql.Field{
+				Type:        newsType,
+				Description: "A published news, or a draft of the signed-in user.",
+				Args:        idArgs,
+				Resolve:     r.news,
+			},
+			"newsList": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(newsType))),
+				Description: "Published news.",
+				Resolve:     r.newsList,
+			},
+			"user": &graphql.Field{
+				Type:    userType,
+				Args:    idArgs,
+				Resolve: r.user,
+			},
+			"users": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Resolve: r.users,
+			},
+			"me": &graphql.Field{
+				Type:    userType,
+				Resolve: r.me,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createNews": &graphql.Field{
+				Type: graphql.NewNonNull(newsType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(newsInput)},
+				},
+				Resolve: r.createNews,
+			},
+			"updateNews": &graphql.Field{
+				Type: graphql.NewNonNull(newsType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(newsInput)},
+				},
+				Resolve: r.updateNews,
+			},
+			"deleteNews": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArgs,
+				Resolve: r.deleteNews,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// newsField and userField resolve a field that is not named like the JSON
+// field of the DTO, which the default resolver relies on.
+func newsField(get func(*newsDTO.NewsResponse) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		switch n := p.Source.(type) {
+		case *newsDTO.NewsResponse:
+			return get(n), nil
+		case newsDTO.NewsResponse:
+			return get(&n), nil
+		}
+		return nil, nil
+	}
+}
+
+func userField(get func(*userDTO.UserResponse) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		switch u := p.Source.(type) {
+		case *userDTO.UserResponse:
+			return get(u), nil
+		case userDTO.UserResponse:
+			return get(&u), nil
+		}
+		return nil, nil
+	}
+}
+
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (r *resolver) news(p graphql.ResolveParams) (any, error) {
+	news, err := r.newsService.GetPublishedNewsByID(p.Args["id"].(int), ViewerID(p.Context))
+	if err != nil {
+		if errors.Is(err, newsService.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return news, nil
+}
+
+func (r *resolver) newsList(p graphql.ResolveParams) (any, error) {
+	news, err := r.newsService.GetPublishedNews()
+	if err != nil {
+		return nil, err
+	}
+	return news.News, nil
+}
+
+// newsAuthor batches the author lookups of a request through its loader.
+func (r *resolver) newsAuthor(p graphql.ResolveParams) (any, error) {
+	authorId, _ := newsField(func(n *newsDTO.NewsResponse) any { return n.AuthorId })(p)
+	if authorId == nil {
+		return nil, nil
+	}
+
+	thunk := loadersFrom(p.Context).users.Load(p.Context, authorId.(int))
+	return func() (any, error) {
+		user, err := thunk()
+		if err != nil {
+			if errors.Is(err, userService.ErrNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return user, nil
+	}, nil
+}
+
+func (r *resolver) user(p graphql.ResolveParams) (any, error) {
+	user, err := r.userService.GetUserByID(p.Args["id"].(int))
+	if err != nil {
+		if errors.Is(err, userService.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *resolver) users(p graphql.ResolveParams) (any, error) {
+	users, err := r.userService.UserList()
+	if err != nil {
+		return nil, err
+	}
+	if users.Users == nil {
+		return []userDTO.UserResponse{}, nil
+	}
+	return users.Users, nil
+}
+
+func (r *resolver) me(p graphql.ResolveParams) (any, error) {
+	viewerId := ViewerID(p.Context)
+	if viewerId == 0 {
+		return nil, nil
+	}
+	return r.userService.GetUserByID(viewerId)
+}
+
+func (r *resolver) createNews(p graphql.ResolveParams) (any, error) {
+	viewerId := ViewerID(p.Context)
+	if viewerId == 0 {
+		return nil, errUnauthorized
+	}
+
+	input := p.Args["input"].(map[string]any)
+	news := &newsDTO.NewsCreateRequest{
+		Title:    input["title"].(string),
+		Content:  input["content"].(string),
+		AuthorId: viewerId,
+	}
+	if err := r.newsService.CreateNews(news); err != nil {
+		return nil, err
+	}
+
+	return r.newsService.GetNewsByID(news.ID)
+}
+
+func (r *resolver) updateNews(p graphql.ResolveParams) (any, error) {
+	viewerId := ViewerID(p.Context)
+	if viewerId == 0 {
+		return nil, errUnauthorized
+	}
+
+	id := p.Args["id"].(int)
+	input := p.Args["input"].(map[string]any)
+	news := newsDTO.NewsUpdateRequest{
+		ID:       id,
+		Title:    input["title"].(string),
+		Content:  input["content"].(string),
+		AuthorId: viewerId,
+	}
+	if err := r.newsService.UpdateNews(id, news); err != nil {
+		return nil, err
+	}
+
+	return r.newsService.GetNewsByID(id)
+}
+
+func (r *resolver) deleteNews(p graphql.ResolveParams) (any, error) {
+	if ViewerID(p.Context) == 0 {
+		return nil, errUnauthorized
+	}
+
+	if err := r.newsService.DeleteNews(p.Args["id"].(int)); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+type contextKey int
+
+const (
+	viewerKey contextKey = iota
+	loadersKey
+)
+
+// NewRequestContext carries the signed-in user, 0 for anonymous requests,
+// and fresh loaders so batching and caching never outlive the request.
+func NewRequestContext(parent context.Context, viewerId int, userService userService.UserService) context.Context {
+	ctx := context.WithValue(parent, viewerKey, viewerId)
+	return context.WithValue(ctx, loadersKey, newLoaders(userService))
+}
+
+func ViewerID(ctx context.Context) int {
+	viewerId, _ := ctx.Value(viewerKey).(int)
+	return viewerId
+}
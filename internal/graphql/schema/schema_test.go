@@ -0,0 +1,178 @@
+package schema_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/graphql/schema"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+)
+
+// The stubs embed the service interfaces; calling a method they do not
+// override panics.
+type stubUserService struct {
+	userService.UserService
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (stub *stubUserService) GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	stub.batches = append(stub.batches, userIds)
+
+	var users []userDTO.UserResponse
+	for _, id := range userIds {
+		if id != 404 {
+			users = append(users, userDTO.UserResponse{ID: id, Name: "Author"})
+		}
+	}
+	return users, nil
+}
+
+type stubNewsService struct {
+	newsService.NewsService
+	created *newsDTO.NewsCreateRequest
+}
+
+func (stub *stubNewsService) GetPublishedNews() (*newsDTO.NewsListResponse, error) {
+	return &newsDTO.NewsListResponse{News: []newsDTO.NewsResponse{
+		{ID: 1, Title: "One", AuthorId: 7},
+		{ID: 2, Title: "Two", AuthorId: 8},
+		{ID: 3, Title: "Three", AuthorId: 7},
+		{ID: 4, Title: "Orphan", AuthorId: 404},
+	}}, nil
+}
+
+func (stub *stubNewsService) GetPublishedNewsByID(id int, viewerId int) (*newsDTO.NewsResponse, error) {
+	return nil, newsService.ErrNotFound
+}
+
+func (stub *stubNewsService) CreateNews(news *newsDTO.NewsCreateRequest) error {
+	news.ID = 10
+	stub.created = news
+	return nil
+}
+
+func (stub *stubNewsService) GetNewsByID(id int) (*newsDTO.NewsResponse, error) {
+	return &newsDTO.NewsResponse{ID: id, Title: stub.created.Title, AuthorId: stub.created.AuthorId}, nil
+}
+
+func run(t *testing.T, query string, viewerId int) (*graphql.Result, *stubUserService, *stubNewsService) {
+	t.Helper()
+
+	users := &stubUserService{}
+	news := &stubNewsService{}
+	graphQLSchema, err := schema.New(users, news)
+	assert.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        graphQLSchema,
+		RequestString: query,
+		Context:       schema.NewRequestContext(context.Background(), viewerId, users),
+	})
+	return result, users, news
+}
+
+func TestAuthorsAreBatched(t *testing.T) {
+	result, users, _ := run(t, `{ newsList { id title author { id name } } }`, 0)
+
+	assert.Empty(t, result.Errors)
+	assert.Len(t, users.batches, 1)
+	assert.ElementsMatch(t, []int{7, 8, 404}, users.batches[0])
+
+	list := result.Data.(map[string]any)["newsList"].([]any)
+	assert.Equal(t, map[string]any{"id": 7, "name": "Author"}, list[0].(map[string]any)["author"])
+	assert.Nil(t, list[3].(map[string]any)["author"])
+}
+
+func TestMissingNewsIsNull(t *testing.T) {
+	result, _, _ := run(t, `{ news(id: 5) { id } }`, 0)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]any{"news": nil}, result.Data)
+}
+
+func TestCreateNews(t *testing.T) {
+	mutation := `mutation { createNews(input: {title: "Hello", content: "World"}) { id title authorId } }`
+
+	t.Run("anonymous", func(t *testing.T) {
+		result, _, news := run(t, mutation, 0)
+
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "unauthorized", result.Errors[0].Message)
+		assert.Nil(t, news.created)
+	})
+
+	t.Run("signed in", func(t *testing.T) {
+		result, _, news := run(t, mutation, 3)
+
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, 3, news.created.AuthorId)
+		assert.Equal(t, map[string]any{"id": 10, "title": "Hello", "authorId": 3},
+			result.Data.(map[string]any)["createNews"])
+	})
+}
+
+func TestCheckLimits(t *testing.T) {
+	graphQLSchema, err := schema.New(&stubUserService{}, &stubNewsService{})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		limits  schema.Limits
+		wantErr string
+	}{
+		{
+			name:   "within limits",
+			query:  `{ newsList { id author { name } } }`,
+			limits: schema.Limits{MaxDepth: 3, MaxComplexity: 31, ListFactor: 10},
+		},
+		{
+			name:    "too deep",
+			query:   `{ newsList { author { name } } }`,
+			limits:  schema.Limits{MaxDepth: 2},
+			wantErr: "query depth 3 exceeds the limit of 2",
+		},
+		{
+			name:    "too deep through a fragment",
+			query:   `query { newsList { ...withAuthor } } fragment withAuthor on News { author { name } }`,
+			limits:  schema.Limits{MaxDepth: 2},
+			wantErr: "query depth 3 exceeds the limit of 2",
+		},
+		{
+			name:    "too complex",
+			query:   `{ newsList { id author { name } } }`,
+			limits:  schema.Limits{MaxComplexity: 30, ListFactor: 10},
+			wantErr: "query complexity 31 exceeds the limit of 30",
+		},
+		{
+			name:   "introspection is not counted",
+			query:  `{ __schema { types { fields { type { name } } } } }`,
+			limits: schema.Limits{MaxDepth: 1, MaxComplexity: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			assert.NoError(t, err)
+
+			err = schema.Check(graphQLSchema, document, "", tt.limits)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
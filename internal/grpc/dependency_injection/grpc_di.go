@@ -0,0 +1,21 @@
+package dependency_injection
+
+import (
+	"database/sql"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/server"
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+)
+
+func InitializeGRPC(db *sql.DB, reads database.Querier, avatarStorage storage.Storage, userHooks users.Hooks, newsHooks news.Hooks, validator *validator.Validate) *grpc.Server {
+	userService := users.NewUserService(db, reads, avatarStorage, userHooks)
+	newsService := news.NewNewsService(db, reads, newsHooks)
+
+	return server.New(userService, newsService, validator, middleware.JWTSecret())
+}
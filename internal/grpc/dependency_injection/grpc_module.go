@@ -0,0 +1,82 @@
+package dependency_injection
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	news "github.com/ahmadammarm/go-rest-api-template/internal/news/dependency_injection"
+	users "github.com/ahmadammarm/go-rest-api-template/internal/user/dependency_injection"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"google.golang.org/grpc"
+)
+
+// Module serves the gRPC API on its own port, with the hooks of the user and
+// news modules, so that it shares the services of the HTTP one.
+type Module struct {
+	app.Base
+	server *grpc.Server
+	port   string
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "grpc"
+}
+
+func (module *Module) DependsOn() []string {
+	return []string{"user", "news"}
+}
+
+func (module *Module) Init(c *app.Container) error {
+	userModule, err := app.Lookup[*users.Module](c, "user")
+	if err != nil {
+		return err
+	}
+	newsModule, err := app.Lookup[*news.Module](c, "news")
+	if err != nil {
+		return err
+	}
+
+	module.server = InitializeGRPC(c.DB, c.Reads, c.Store, userModule.Hooks, newsModule.Hooks, c.Validator)
+	module.port = config.GetEnv("GRPC_PORT", "9090")
+	return nil
+}
+
+func (module *Module) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", ":"+module.port)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		log.Printf("gRPC server starting on port %s", module.port)
+		if err := module.server.Serve(listener); err != nil {
+			log.Printf("Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop waits for the calls in progress, and cancels them when ctx is done
+// first.
+func (module *Module) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		module.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		module.server.Stop()
+		return ctx.Err()
+	}
+}
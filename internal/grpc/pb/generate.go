@@ -0,0 +1,5 @@
+// Package pb holds the protobuf definitions of the gRPC API and the code
+// generated from them. Run go generate after changing a .proto file.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative user.proto news.proto
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/pb"
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// access is who may call a method. Methods not listed in methodAccess
+// require a token.
+type access int
+
+const (
+	authenticated access = iota
+	// public methods accept anonymous calls and still read a token when one
+	// is sent, like OptionalJWTAuth.
+	public
+	admin
+)
+
+var methodAccess = map[string]access{
+	pb.UserService_Register_FullMethodName:  public,
+	pb.UserService_Login_FullMethodName:     public,
+	pb.UserService_GetUser_FullMethodName:   public,
+	pb.UserService_ListUsers_FullMethodName: public,
+	pb.NewsService_ListNews_FullMethodName:  public,
+	pb.NewsService_GetNews_FullMethodName:   public,
+
+	pb.NewsService_PublishNews_FullMethodName:   admin,
+	pb.NewsService_UnpublishNews_FullMethodName: admin,
+}
+
+func accessOf(fullMethod string) access {
+	// The grpc.* services, health and reflection, are open to probes and
+	// tools.
+	if strings.HasPrefix(fullMethod, "/grpc.") {
+		return public
+	}
+	return methodAccess[fullMethod]
+}
+
+type authenticator struct {
+	secret string
+}
+
+// authorize verifies the "authorization: Bearer <token>" metadata, in the
+// same format as the HTTP header, and stores the claims in the context.
+func (auth *authenticator) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	level := accessOf(fullMethod)
+
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 || values[0] == "" {
+		if level == public {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized: No Token Provided")
+	}
+
+	claims, message := middleware.ParseAuthorization(values[0], auth.secret)
+	if message != "" {
+		return nil, status.Error(codes.Unauthenticated, message)
+	}
+
+	if level == admin && !claims.Admin {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden: Admin Only")
+	}
+
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+func (auth *authenticator) unary(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := auth.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+func (auth *authenticator) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := auth.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedStream) Context() context.Context {
+	return stream.ctx
+}
+
+type claimsKey struct{}
+
+// userID returns the signed-in user, 0 for anonymous calls.
+func userID(ctx context.Context) int {
+	claims, ok := ctx.Value(claimsKey{}).(*token.Claims)
+	if !ok {
+		return 0
+	}
+	return claims.UserID
+}
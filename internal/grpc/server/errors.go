@@ -0,0 +1,71 @@
+package server
+
+import (
+	"errors"
+	"log"
+
+	attachmentService "github.com/ahmadammarm/go-rest-api-template/internal/attachment/service"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusCodes maps the errors returned by the services to status codes. The
+// HTTP status the handlers answer with is noted next to each.
+var statusCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{newsService.ErrNotFound, codes.NotFound},                     // 404
+	{userService.ErrNotFound, codes.NotFound},                     // 404
+	{attachmentService.ErrNotFound, codes.NotFound},               // 404
+	{userService.ErrEmailExists, codes.AlreadyExists},             // 409
+	{userService.ErrInvalidPassword, codes.Unauthenticated},       // 401
+	{attachmentService.ErrForbidden, codes.PermissionDenied},      // 403
+	{attachmentService.ErrFileTooLarge, codes.InvalidArgument},    // 413
+	{attachmentService.ErrUnsupportedType, codes.InvalidArgument}, // 415
+}
+
+// toStatus converts a service error. The services wrap repository errors
+// with context, so the sentinels are matched with errors.Is. Unknown errors
+// are logged and reported as Internal, without details, like a 500.
+func toStatus(err error) error {
+	for _, mapping := range statusCodes {
+		if errors.Is(err, mapping.err) {
+			return status.Error(mapping.code, mapping.err.Error())
+		}
+	}
+
+	log.Println("gRPC call failed:", err)
+	return status.Error(codes.Internal, "Internal Server Error")
+}
+
+// invalidArgument reports the fields that failed validation, like the
+// FormValidationError body of a 400.
+func invalidArgument(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return status.Error(codes.InvalidArgument, "Invalid Request")
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldError := range validationErrors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldError.StructField(),
+			Description: fieldError.Tag(),
+		})
+	}
+
+	withDetails, detailsErr := status.New(codes.InvalidArgument, "Validation Error").WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, "Validation Error")
+	}
+	return withDetails.Err()
+}
+
+func invalidID() error {
+	return status.Error(codes.InvalidArgument, "Invalid Request")
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	attachmentService "github.com/ahmadammarm/go-rest-api-template/internal/attachment/service"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "wrapped sentinel",
+			err:     fmt.Errorf("error updating news: %w", fmt.Errorf("in transaction: %w", newsService.ErrNotFound)),
+			code:    codes.NotFound,
+			message: "news not found",
+		},
+		{name: "user sentinel", err: userService.ErrEmailExists, code: codes.AlreadyExists, message: "email already exists"},
+		{name: "attachment sentinel", err: attachmentService.ErrForbidden, code: codes.PermissionDenied, message: "forbidden"},
+		{
+			// Only the sentinels are matched, not errors with the same text.
+			name:    "same text",
+			err:     errors.New("news not found"),
+			code:    codes.Internal,
+			message: "Internal Server Error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converted, ok := status.FromError(toStatus(test.err))
+			assert.True(t, ok)
+			assert.Equal(t, test.code, converted.Code())
+			assert.Equal(t, test.message, converted.Message())
+		})
+	}
+}
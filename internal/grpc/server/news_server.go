@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/pb"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type newsServer struct {
+	pb.UnimplementedNewsServiceServer
+	newsService newsService.NewsService
+	validation  *validator.Validate
+}
+
+func (server *newsServer) ListNews(ctx context.Context, request *pb.ListNewsRequest) (*pb.ListNewsResponse, error) {
+	news, err := server.newsService.GetPublishedNews()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &pb.ListNewsResponse{Total: int64(news.Total)}
+	for i := range news.News {
+		response.News = append(response.News, toNews(&news.News[i]))
+	}
+	return response, nil
+}
+
+func (server *newsServer) GetNews(ctx context.Context, request *pb.GetNewsRequest) (*pb.News, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	news, err := server.newsService.GetPublishedNewsByID(int(request.GetId()), userID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toNews(news), nil
+}
+
+func (server *newsServer) CreateNews(ctx context.Context, request *pb.CreateNewsRequest) (*pb.News, error) {
+	news := &newsDTO.NewsCreateRequest{
+		Title:       request.GetTitle(),
+		Content:     request.GetContent(),
+		AuthorId:    userID(ctx),
+		PublishedAt: toTime(request.GetPublishedAt()),
+	}
+	if err := server.validation.Struct(news); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	if err := server.newsService.CreateNews(news); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return server.getNews(news.ID)
+}
+
+func (server *newsServer) UpdateNews(ctx context.Context, request *pb.UpdateNewsRequest) (*pb.News, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	id := int(request.GetId())
+	news := newsDTO.NewsUpdateRequest{
+		ID:       id,
+		Title:    request.GetTitle(),
+		Content:  request.GetContent(),
+		AuthorId: userID(ctx),
+	}
+	if err := server.validation.Struct(news); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	if err := server.newsService.UpdateNews(id, news); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return server.getNews(id)
+}
+
+func (server *newsServer) DeleteNews(ctx context.Context, request *pb.DeleteNewsRequest) (*pb.DeleteNewsResponse, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	if err := server.newsService.DeleteNews(int(request.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.DeleteNewsResponse{}, nil
+}
+
+func (server *newsServer) PublishNews(ctx context.Context, request *pb.PublishNewsRequest) (*pb.PublishNewsResponse, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	if err := server.newsService.PublishNews(int(request.GetId()), toTime(request.GetPublishedAt())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.PublishNewsResponse{}, nil
+}
+
+func (server *newsServer) UnpublishNews(ctx context.Context, request *pb.UnpublishNewsRequest) (*pb.UnpublishNewsResponse, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	if err := server.newsService.UnpublishNews(int(request.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.UnpublishNewsResponse{}, nil
+}
+
+func (server *newsServer) getNews(id int) (*pb.News, error) {
+	news, err := server.newsService.GetNewsByID(id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toNews(news), nil
+}
+
+func toNews(news *newsDTO.NewsResponse) *pb.News {
+	return &pb.News{
+		Id:              int64(news.ID),
+		Title:           news.Title,
+		Content:         news.Content,
+		AuthorId:        int64(news.AuthorId),
+		AuthorName:      news.AuthorName,
+		AuthorAvatarUrl: news.AuthorAvatarURL,
+		Reactions: &pb.Reactions{
+			Like:  int64(news.Reactions.Like),
+			Love:  int64(news.Reactions.Love),
+			Laugh: int64(news.Reactions.Laugh),
+			Wow:   int64(news.Reactions.Wow),
+			Sad:   int64(news.Reactions.Sad),
+			Angry: int64(news.Reactions.Angry),
+		},
+		PublishedAt: news.PublishedAt,
+		CreatedAt:   news.CreatedAt,
+		UpdatedAt:   news.UpdatedAt,
+	}
+}
+
+// toTime returns nil for an unset timestamp.
+func toTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	value := timestamp.AsTime()
+	return &value
+}
@@ -0,0 +1,39 @@
+// Package server serves the gRPC API next to the HTTP one. It goes through
+// the same services as the HTTP handlers, and maps their errors to status
+// codes the way the handlers map them to HTTP statuses.
+package server
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/pb"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// New returns a server with the user and news services registered, along
+// with the standard health and reflection services. secret verifies the
+// tokens sent as authorization metadata.
+func New(userService userService.UserService, newsService newsService.NewsService, validation *validator.Validate, secret string) *grpc.Server {
+	auth := &authenticator{secret: secret}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.unary),
+		grpc.ChainStreamInterceptor(auth.stream),
+	)
+
+	pb.RegisterUserServiceServer(server, &userServer{userService: userService, validation: validation})
+	pb.RegisterNewsServiceServer(server, &newsServer{newsService: newsService, validation: validation})
+
+	healthServer := health.NewServer()
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
@@ -0,0 +1,264 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/pb"
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/server"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+)
+
+const secret = "test-secret"
+
+// The stubs embed the service interfaces; calling a method they do not
+// override panics.
+type stubUserService struct {
+	userService.UserService
+}
+
+func (stub *stubUserService) RegisterUser(user *userDTO.UserRegisterRequest) error {
+	if user.Email == "taken@mail.com" {
+		return userService.ErrEmailExists
+	}
+	user.ID = 3
+	return nil
+}
+
+func (stub *stubUserService) LoginUser(user *userDTO.UserLoginRequest) (any, error) {
+	return "", userService.ErrInvalidPassword
+}
+
+type stubNewsService struct {
+	newsService.NewsService
+	created   *newsDTO.NewsCreateRequest
+	published int
+}
+
+func (stub *stubNewsService) GetPublishedNews() (*newsDTO.NewsListResponse, error) {
+	return &newsDTO.NewsListResponse{News: []newsDTO.NewsResponse{{ID: 1, Title: "One"}}, Total: 1}, nil
+}
+
+func (stub *stubNewsService) GetPublishedNewsByID(id int, viewerId int) (*newsDTO.NewsResponse, error) {
+	return nil, newsService.ErrNotFound
+}
+
+func (stub *stubNewsService) CreateNews(news *newsDTO.NewsCreateRequest) error {
+	news.ID = 10
+	stub.created = news
+	return nil
+}
+
+func (stub *stubNewsService) GetNewsByID(id int) (*newsDTO.NewsResponse, error) {
+	return &newsDTO.NewsResponse{ID: id, Title: stub.created.Title, AuthorId: stub.created.AuthorId}, nil
+}
+
+func (stub *stubNewsService) UpdateNews(newsId int, news newsDTO.NewsUpdateRequest) error {
+	// Wrapped like the service does with repository errors.
+	return fmt.Errorf("error updating news: %w", newsService.ErrNotFound)
+}
+
+func (stub *stubNewsService) DeleteNews(id int) error {
+	return errors.New("connection refused")
+}
+
+func (stub *stubNewsService) PublishNews(id int, publishedAt *time.Time) error {
+	stub.published = id
+	return nil
+}
+
+func dial(t *testing.T) (*grpc.ClientConn, *stubNewsService) {
+	t.Helper()
+
+	news := &stubNewsService{}
+	grpcServer := server.New(&stubUserService{}, news, validator.New(), secret)
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, news
+}
+
+func withToken(t *testing.T, userId int, admin bool) context.Context {
+	t.Helper()
+
+	signed, err := token.Issue(secret, userId, admin, time.Hour)
+	assert.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signed)
+}
+
+func TestPublicMethods(t *testing.T) {
+	conn, _ := dial(t)
+	client := pb.NewNewsServiceClient(conn)
+
+	list, err := client.ListNews(context.Background(), &pb.ListNewsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), list.GetTotal())
+	assert.Equal(t, "One", list.GetNews()[0].GetTitle())
+
+	_, err = client.GetNews(context.Background(), &pb.GetNewsRequest{Id: 5})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetNews(context.Background(), &pb.GetNewsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthentication(t *testing.T) {
+	conn, news := dial(t)
+	client := pb.NewNewsServiceClient(conn)
+	request := &pb.CreateNewsRequest{Title: "Title", Content: "Content"}
+
+	_, err := client.CreateNews(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	_, err = client.CreateNews(invalid, request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	created, err := client.CreateNews(withToken(t, 7, false), request)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), created.GetId())
+	assert.Equal(t, 7, news.created.AuthorId)
+}
+
+func TestAdminMethods(t *testing.T) {
+	conn, news := dial(t)
+	client := pb.NewNewsServiceClient(conn)
+
+	_, err := client.PublishNews(withToken(t, 7, false), &pb.PublishNewsRequest{Id: 4})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Zero(t, news.published)
+
+	_, err = client.PublishNews(withToken(t, 1, true), &pb.PublishNewsRequest{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, news.published)
+}
+
+func TestErrorMapping(t *testing.T) {
+	conn, _ := dial(t)
+	users := pb.NewUserServiceClient(conn)
+	news := pb.NewNewsServiceClient(conn)
+	ctx := withToken(t, 7, false)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		code    codes.Code
+		message string
+	}{
+		{
+			name: "Conflict",
+			call: func() error {
+				_, err := users.Register(ctx, &pb.RegisterRequest{Email: "taken@mail.com", Name: "Taken", Password: "secret123"})
+				return err
+			},
+			code:    codes.AlreadyExists,
+			message: "email already exists",
+		},
+		{
+			name: "Wrapped not found",
+			call: func() error {
+				_, err := news.UpdateNews(ctx, &pb.UpdateNewsRequest{Id: 5, Title: "Title", Content: "Content"})
+				return err
+			},
+			code:    codes.NotFound,
+			message: "news not found",
+		},
+		{
+			name: "Failed login",
+			call: func() error {
+				_, err := users.Login(ctx, &pb.LoginRequest{Email: "user@mail.com", Password: "wrong"})
+				return err
+			},
+			code:    codes.Unauthenticated,
+			message: "Login User Failed",
+		},
+		{
+			name: "Unknown error",
+			call: func() error {
+				_, err := news.DeleteNews(ctx, &pb.DeleteNewsRequest{Id: 5})
+				return err
+			},
+			code:    codes.Internal,
+			message: "Internal Server Error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.call()
+			assert.Equal(t, test.code, status.Code(err))
+			assert.Equal(t, test.message, status.Convert(err).Message())
+		})
+	}
+}
+
+func TestValidationDetails(t *testing.T) {
+	conn, _ := dial(t)
+	client := pb.NewUserServiceClient(conn)
+
+	_, err := client.Register(context.Background(), &pb.RegisterRequest{Email: "not an email", Name: "Name", Password: "secret123"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	details := status.Convert(err).Details()
+	if assert.Len(t, details, 1) {
+		badRequest := details[0].(*errdetails.BadRequest)
+		assert.Equal(t, "Email", badRequest.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "email", badRequest.GetFieldViolations()[0].GetDescription())
+	}
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn, _ := dial(t)
+
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "gorestapi.v1.NewsService"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, "gorestapi.v1.UserService")
+	assert.Contains(t, services, "gorestapi.v1.NewsService")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+}
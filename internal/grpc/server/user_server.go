@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/grpc/pb"
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	userService userService.UserService
+	validation  *validator.Validate
+}
+
+func (server *userServer) Register(ctx context.Context, request *pb.RegisterRequest) (*pb.User, error) {
+	user := &userDTO.UserRegisterRequest{
+		Email:    request.GetEmail(),
+		Name:     request.GetName(),
+		Password: request.GetPassword(),
+	}
+	if err := server.validation.Struct(user); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	if err := server.userService.RegisterUser(user); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.User{Id: int64(user.ID), Name: user.Name, Email: user.Email}, nil
+}
+
+func (server *userServer) Login(ctx context.Context, request *pb.LoginRequest) (*pb.LoginResponse, error) {
+	result, err := server.userService.LoginUser(&userDTO.UserLoginRequest{
+		Email:    request.GetEmail(),
+		Password: request.GetPassword(),
+	})
+	if err != nil {
+		// Like the HTTP handler, do not tell an unknown email from a wrong
+		// password.
+		if status.Code(toStatus(err)) != codes.Internal {
+			return nil, status.Error(codes.Unauthenticated, "Login User Failed")
+		}
+		return nil, toStatus(err)
+	}
+
+	login, ok := result.(userDTO.UserJWTResponse)
+	if !ok {
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return &pb.LoginResponse{
+		Id:      int64(login.ID),
+		Name:    login.Name,
+		Email:   login.Email,
+		IsAdmin: login.IsAdmin,
+		Token:   login.Token,
+	}, nil
+}
+
+func (server *userServer) GetUser(ctx context.Context, request *pb.GetUserRequest) (*pb.User, error) {
+	if request.GetId() < 1 {
+		return nil, invalidID()
+	}
+
+	user, err := server.userService.GetUserByID(int(request.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUser(user), nil
+}
+
+func (server *userServer) ListUsers(ctx context.Context, request *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	users, err := server.userService.UserList()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &pb.ListUsersResponse{Total: int64(users.Total)}
+	for i := range users.Users {
+		response.Users = append(response.Users, toUser(&users.Users[i]))
+	}
+	return response, nil
+}
+
+func (server *userServer) UpdateMe(ctx context.Context, request *pb.UpdateMeRequest) (*pb.UpdateMeResponse, error) {
+	user := &userDTO.UserUpdateRequest{
+		Name:     request.GetName(),
+		Email:    request.GetEmail(),
+		Password: request.GetPassword(),
+	}
+	if err := server.validation.Struct(user); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	if err := server.userService.UpdateUser(user, userID(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.UpdateMeResponse{}, nil
+}
+
+func toUser(user *userDTO.UserResponse) *pb.User {
+	return &pb.User{
+		Id:        int64(user.ID),
+		Name:      user.Name,
+		Email:     user.Email,
+		Bio:       user.Bio,
+		AvatarUrl: user.AvatarURL,
+		Website:   user.Website,
+		Locale:    user.Locale,
+		Timezone:  user.Timezone,
+	}
+}
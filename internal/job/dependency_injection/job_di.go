@@ -0,0 +1,10 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/job/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+)
+
+func InitializeJob(queue *jobs.Queue) *handler.JobHandler {
+	return handler.NewJobHandler(queue)
+}
@@ -0,0 +1,60 @@
+package dependency_injection
+
+import (
+	"context"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	"github.com/ahmadammarm/go-rest-api-template/internal/job/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+)
+
+// Module manages the background jobs and runs them with the handlers the
+// other modules registered on the queue. Its table is Postgres only: on
+// SQLite, it does nothing.
+type Module struct {
+	app.Base
+	handler *handler.JobHandler
+	worker  *jobs.Worker
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "job"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	if !c.Config.Postgres() {
+		return nil
+	}
+
+	module.handler = InitializeJob(c.Queue)
+	module.worker = jobs.NewWorker(c.Queue, config.JobWorker())
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	if module.handler == nil {
+		return
+	}
+	routes.Admin.Add(module.handler.JobRouters, module.handler.JobOperations())
+}
+
+func (module *Module) Start(ctx context.Context) error {
+	if module.worker != nil {
+		module.worker.Start()
+	}
+	return nil
+}
+
+// Stop waits for the jobs in progress, which are cancelled and retried later
+// when ctx is done first.
+func (module *Module) Stop(ctx context.Context) error {
+	if module.worker == nil {
+		return nil
+	}
+	return module.worker.Shutdown(ctx)
+}
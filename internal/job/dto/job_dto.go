@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+
+type JobListResponse struct {
+	Jobs  []jobs.Job `json:"jobs"`
+	Total int        `json:"total"`
+}
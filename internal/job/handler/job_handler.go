@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/job/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// listLimit bounds how many jobs GetJobs returns.
+const listLimit = 100
+
+var statuses = map[string]bool{
+	"":                   true,
+	jobs.StatusPending:   true,
+	jobs.StatusRunning:   true,
+	jobs.StatusSucceeded: true,
+	jobs.StatusDead:      true,
+	jobs.StatusCancelled: true,
+}
+
+type JobHandler struct {
+	queue *jobs.Queue
+}
+
+// jobError answers the errors of Get, Retry and Cancel.
+func jobError(context *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "job not found":
+		return response.JSONResponse(context, 404, "Not Found", nil)
+	case "job cannot be retried", "job cannot be cancelled":
+		return response.JSONResponse(context, 409, "Conflict", nil)
+	}
+	log.Println("Error handling job request:", err)
+	return response.JSONResponse(context, 500, "Internal Server Error", nil)
+}
+
+func (handler *JobHandler) GetJobs(context *fiber.Ctx) error {
+	status := context.Query("status")
+	if !statuses[status] {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	jobList, err := handler.queue.List(context.Context(), status, context.Query("type"), listLimit)
+	if err != nil {
+		log.Println("Error fetching jobs:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", dto.JobListResponse{Jobs: jobList, Total: len(jobList)})
+}
+
+func (handler *JobHandler) GetJobByID(context *fiber.Ctx) error {
+	id, err := strconv.ParseInt(context.Params("id"), 10, 64)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	job, err := handler.queue.Get(context.Context(), id)
+	if err != nil {
+		return jobError(context, err)
+	}
+
+	return response.JSONResponse(context, 200, "Success", job)
+}
+
+func (handler *JobHandler) RetryJob(context *fiber.Ctx) error {
+	id, err := strconv.ParseInt(context.Params("id"), 10, 64)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	job, err := handler.queue.Retry(context.Context(), id)
+	if err != nil {
+		return jobError(context, err)
+	}
+
+	log.Printf("Job %d queued for retry", id)
+	return response.JSONResponse(context, 200, "Success", job)
+}
+
+func (handler *JobHandler) CancelJob(context *fiber.Ctx) error {
+	id, err := strconv.ParseInt(context.Params("id"), 10, 64)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	job, err := handler.queue.Cancel(context.Context(), id)
+	if err != nil {
+		return jobError(context, err)
+	}
+
+	log.Printf("Job %d cancelled", id)
+	return response.JSONResponse(context, 200, "Success", job)
+}
+
+// JobRouters registers the job admin routes. They are meant for a group using
+// JWTAuth and RequireAdmin.
+func (handler *JobHandler) JobRouters(router fiber.Router) {
+	router.Get("/jobs", handler.GetJobs)
+	router.Get("/jobs/:id", handler.GetJobByID)
+	router.Post("/jobs/:id/retry", handler.RetryJob)
+	router.Post("/jobs/:id/cancel", handler.CancelJob)
+}
+
+func NewJobHandler(queue *jobs.Queue) *JobHandler {
+	return &JobHandler{queue: queue}
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/job/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var jobTags = []string{"Jobs"}
+
+// JobOperations documents the routes of JobRouters.
+func (handler *JobHandler) JobOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/jobs", Summary: "Get the latest jobs", Tags: jobTags, Security: openapi.BearerAuth,
+			Query: []openapi.Param{
+				{Name: "status", Description: "pending, running, succeeded, dead or cancelled"},
+				{Name: "type"},
+			},
+			Response: dto.JobListResponse{}, Errors: []int{400, 403, 500}},
+		{Method: "GET", Path: "/jobs/:id", Summary: "Get a job by id", Tags: jobTags, Security: openapi.BearerAuth,
+			Response: jobs.Job{}, Errors: []int{400, 403, 404, 500}},
+		{Method: "POST", Path: "/jobs/:id/retry", Summary: "Run a dead or cancelled job again", Tags: jobTags, Security: openapi.BearerAuth,
+			Response: jobs.Job{}, Errors: []int{400, 403, 404, 409, 500}},
+		{Method: "POST", Path: "/jobs/:id/cancel", Summary: "Cancel a pending job", Tags: jobTags, Security: openapi.BearerAuth,
+			Response: jobs.Job{}, Errors: []int{400, 403, 404, 409, 500}},
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeprecatedRequests counts calls to deprecated API versions, keyed by
+// "<version> <method> <route>". It is published through expvar.
+var DeprecatedRequests = expvar.NewMap("deprecated_api_requests")
+
+type DeprecationConfig struct {
+	// Version names the deprecated API in logs and metrics.
+	Version string
+	// Prefix is the path prefix of the deprecated routes and Successor the
+	// prefix replacing it; both may be empty.
+	Prefix    string
+	Successor string
+	// DeprecatedAt and Sunset are sent when set.
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+// Deprecated marks the responses of a deprecated API version with the
+// Deprecation, Sunset and successor Link headers, and logs and counts every
+// call.
+func Deprecated(config DeprecationConfig) fiber.Handler {
+	deprecation := "true"
+	if !config.DeprecatedAt.IsZero() {
+		deprecation = fmt.Sprintf("@%d", config.DeprecatedAt.Unix())
+	}
+
+	return func(context *fiber.Ctx) error {
+		context.Set("Deprecation", deprecation)
+		if !config.Sunset.IsZero() {
+			context.Set("Sunset", config.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if config.Successor != "" {
+			successor := config.Successor + strings.TrimPrefix(context.Path(), config.Prefix)
+			context.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+
+		err := context.Next()
+
+		route := context.Route().Path
+		log.Printf("Deprecated API %s called: %s %s", config.Version, context.Method(), route)
+		DeprecatedRequests.Add(config.Version+" "+context.Method()+" "+route, 1)
+
+		return err
+	}
+}
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"expvar"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+)
+
+func TestDeprecated(t *testing.T) {
+	app := fiber.New()
+
+	v0 := app.Group("/api/v0", middleware.Deprecated(middleware.DeprecationConfig{
+		Version:      "v0",
+		Prefix:       "/api/v0",
+		Successor:    "/api/v1",
+		DeprecatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}))
+	v0.Get("/news/:id", func(context *fiber.Ctx) error {
+		return context.SendStatus(204)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v0/news/3", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+	assert.Equal(t, "@1735689600", resp.Header.Get("Deprecation"))
+	assert.Empty(t, resp.Header.Get("Sunset"))
+	assert.Equal(t, `</api/v1/news/3>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	calls, ok := middleware.DeprecatedRequests.Get("v0 GET /api/v0/news/:id").(*expvar.Int)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), calls.Value())
+}
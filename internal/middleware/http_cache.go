@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResponseCacheName is the key of the shared response cache counters in
+// cache.Hits and cache.Misses.
+const ResponseCacheName = "http_responses"
+
+type HTTPCacheConfig struct {
+	// MaxAge is how long clients and shared caches may reuse an anonymous
+	// response without revalidating it. With 0 they revalidate every time.
+	// Authenticated responses are private and always revalidated.
+	MaxAge time.Duration
+	// Vary lists the request headers, besides Authorization, the responses
+	// depend on.
+	Vary []string
+	// Store, when set, keeps the responses to anonymous GETs for MaxAge and
+	// serves them without calling the handler, marked with X-Cache: HIT.
+	Store cache.Cache
+}
+
+// storedResponse is a response kept in HTTPCacheConfig.Store.
+type storedResponse struct {
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// HTTPCache makes successful GET responses cacheable: it sends a weak ETag
+// computed from the body, Cache-Control and Vary, and answers 304 Not Modified
+// to If-None-Match, or If-Modified-Since when the handler set Last-Modified.
+func HTTPCache(config HTTPCacheConfig) fiber.Handler {
+	vary := strings.Join(append([]string{fiber.HeaderAuthorization}, config.Vary...), ", ")
+	public := "public, no-cache"
+	if config.MaxAge > 0 {
+		public = fmt.Sprintf("public, max-age=%d", int(config.MaxAge.Seconds()))
+	}
+
+	return func(context *fiber.Ctx) error {
+		method := context.Method()
+		if method != fiber.MethodGet && method != fiber.MethodHead {
+			return context.Next()
+		}
+
+		anonymous := context.Get(fiber.HeaderAuthorization) == ""
+		storeKey := ""
+		if config.Store != nil && anonymous && method == fiber.MethodGet {
+			storeKey = responseKey(context, config.Vary)
+			if stored := loadResponse(context.UserContext(), config.Store, storeKey); stored != nil {
+				context.Set("X-Cache", "HIT")
+				context.Set(fiber.HeaderContentType, stored.ContentType)
+				context.Set(fiber.HeaderLastModified, stored.StoredAt.Format(http.TimeFormat))
+				context.Status(fiber.StatusOK).Response().SetBodyRaw(stored.Body)
+				return validate(context, public, vary)
+			}
+		}
+
+		if err := context.Next(); err != nil {
+			return err
+		}
+
+		if context.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		// JWTAuth and OptionalJWTAuth set user_id for authenticated callers.
+		if _, ok := context.Locals("user_id").(int); ok || !anonymous {
+			return validate(context, "private, no-cache", vary)
+		}
+
+		if storeKey != "" {
+			stored := storedResponse{
+				ContentType: string(context.Response().Header.ContentType()),
+				Body:        context.Response().Body(),
+				StoredAt:    time.Now().UTC().Truncate(time.Second),
+			}
+			if data, err := json.Marshal(stored); err == nil {
+				if err := config.Store.Set(context.UserContext(), storeKey, data, config.MaxAge); err != nil {
+					log.Printf("Error storing response %q: %v", storeKey, err)
+				}
+			}
+			context.Set(fiber.HeaderLastModified, stored.StoredAt.Format(http.TimeFormat))
+			context.Set("X-Cache", "MISS")
+		}
+
+		return validate(context, public, vary)
+	}
+}
+
+// validate sets the caching headers of a successful response, then replaces
+// it with 304 Not Modified when the client already has it. If-None-Match wins
+// over If-Modified-Since when both are sent, as RFC 9110 requires.
+func validate(context *fiber.Ctx, cacheControl string, vary string) error {
+	hash := sha256.Sum256(context.Response().Body())
+	etag := `W/"` + hex.EncodeToString(hash[:16]) + `"`
+
+	context.Set(fiber.HeaderETag, etag)
+	context.Set(fiber.HeaderCacheControl, cacheControl)
+	context.Vary(vary)
+
+	if notModified(context, etag) {
+		context.Response().ResetBody()
+		context.Status(fiber.StatusNotModified)
+	}
+
+	return nil
+}
+
+func notModified(context *fiber.Ctx, etag string) bool {
+	if ifNoneMatch := context.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		// Weak comparison: W/"x" matches "x".
+		opaque := strings.TrimPrefix(etag, "W/")
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == opaque {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := context.Get(fiber.HeaderIfModifiedSince)
+	lastModified := string(context.Response().Header.Peek(fiber.HeaderLastModified))
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.After(since)
+}
+
+// responseKey identifies a response by its URL and the request headers it
+// varies on.
+func responseKey(context *fiber.Ctx, vary []string) string {
+	key := "http:" + context.OriginalURL()
+	for _, header := range vary {
+		key += "\n" + header + ": " + context.Get(header)
+	}
+	return key
+}
+
+func loadResponse(ctx context.Context, store cache.Cache, key string) *storedResponse {
+	data, ok, err := store.Get(ctx, key)
+	if err != nil {
+		log.Printf("Error reading stored response %q: %v", key, err)
+	}
+
+	var stored storedResponse
+	if !ok || json.Unmarshal(data, &stored) != nil {
+		cache.Misses.Add(ResponseCacheName, 1)
+		return nil
+	}
+
+	cache.Hits.Add(ResponseCacheName, 1)
+	return &stored
+}
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+)
+
+func newCachedApp(config middleware.HTTPCacheConfig) (*fiber.App, *int) {
+	calls := 0
+	app := fiber.New()
+
+	// Stands in for OptionalJWTAuth.
+	authenticate := func(context *fiber.Ctx) error {
+		if context.Get("Authorization") != "" {
+			context.Locals("user_id", 1)
+		}
+		return context.Next()
+	}
+
+	app.Get("/news", authenticate, middleware.HTTPCache(config), func(context *fiber.Ctx) error {
+		calls++
+		return response.JSONResponse(context, 200, "Success", []string{"news"})
+	})
+	app.Get("/missing", middleware.HTTPCache(config), func(context *fiber.Ctx) error {
+		return response.JSONResponse(context, 404, "Not Found", nil)
+	})
+
+	return app, &calls
+}
+
+func get(t *testing.T, app *fiber.App, path string, headers map[string]string) *http.Response {
+	t.Helper()
+
+	request := httptest.NewRequest("GET", path, nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	resp, err := app.Test(request)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHTTPCacheConditionalGet(t *testing.T) {
+	app, _ := newCachedApp(middleware.HTTPCacheConfig{MaxAge: time.Minute, Vary: []string{"Accept-Language"}})
+
+	resp := get(t, app, "/news", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "Authorization, Accept-Language", resp.Header.Get("Vary"))
+	etag := resp.Header.Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{name: "matching etag", headers: map[string]string{"If-None-Match": `"xyz", ` + etag}, wantStatus: 304},
+		{name: "strong form of the etag", headers: map[string]string{"If-None-Match": etag[2:]}, wantStatus: 304},
+		{name: "any etag", headers: map[string]string{"If-None-Match": "*"}, wantStatus: 304},
+		{name: "different etag", headers: map[string]string{"If-None-Match": `W/"xyz"`}, wantStatus: 200},
+		{name: "no last modified", headers: map[string]string{"If-Modified-Since": time.Now().UTC().Format(http.TimeFormat)}, wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, app, "/news", tt.headers)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get("ETag"))
+
+			body, _ := io.ReadAll(resp.Body)
+			if tt.wantStatus == 304 {
+				assert.Empty(t, body)
+			} else {
+				assert.JSONEq(t, `{"message": "Success", "data": ["news"]}`, string(body))
+			}
+		})
+	}
+}
+
+func TestHTTPCacheAuthenticatedIsPrivate(t *testing.T) {
+	app, _ := newCachedApp(middleware.HTTPCacheConfig{MaxAge: time.Minute})
+
+	resp := get(t, app, "/news", map[string]string{"Authorization": "Bearer token"})
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "private, no-cache", resp.Header.Get("Cache-Control"))
+	assert.NotEmpty(t, resp.Header.Get("ETag"))
+}
+
+func TestHTTPCacheSkipsErrors(t *testing.T) {
+	app, _ := newCachedApp(middleware.HTTPCacheConfig{MaxAge: time.Minute, Store: cache.NewMemory(10)})
+
+	resp := get(t, app, "/missing", nil)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("ETag"))
+	assert.Empty(t, resp.Header.Get("Cache-Control"))
+}
+
+func TestHTTPCacheStore(t *testing.T) {
+	app, calls := newCachedApp(middleware.HTTPCacheConfig{MaxAge: time.Minute, Store: cache.NewMemory(10)})
+
+	first := get(t, app, "/news", nil)
+	assert.Equal(t, "MISS", first.Header.Get("X-Cache"))
+	lastModified := first.Header.Get("Last-Modified")
+	assert.NotEmpty(t, lastModified)
+
+	second := get(t, app, "/news", nil)
+	assert.Equal(t, 200, second.StatusCode)
+	assert.Equal(t, "HIT", second.Header.Get("X-Cache"))
+	assert.Equal(t, first.Header.Get("ETag"), second.Header.Get("ETag"))
+	assert.Equal(t, "application/json", second.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(second.Body)
+	assert.JSONEq(t, `{"message": "Success", "data": ["news"]}`, string(body))
+
+	notModified := get(t, app, "/news", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, 304, notModified.StatusCode)
+
+	// Authenticated callers and other URLs are not served from the store.
+	get(t, app, "/news", map[string]string{"Authorization": "Bearer token"})
+	get(t, app, "/news?page=2", nil)
+	assert.Equal(t, 3, *calls)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/idempotency"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IdempotencyKeyHeader names the header clients send a unique key in, e.g. a
+// UUID, to make a POST safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type IdempotencyConfig struct {
+	Store *idempotency.Store
+	// TTL is how long a key is kept, and so how late a retry may come.
+	TTL time.Duration
+	// Lease is how long a request may hold its key. Repeats during it get
+	// 409 Conflict; after it the key can be claimed again.
+	Lease time.Duration
+}
+
+// Idempotency replays the response to a POST when it is repeated with the
+// same Idempotency-Key, instead of running it again. A key sent with another
+// method, path or body gets 409 Conflict. Requests that failed with a 5xx are
+// not recorded, so that they can be retried. Requests without the header are
+// not affected.
+func Idempotency(config IdempotencyConfig) fiber.Handler {
+	return func(context *fiber.Ctx) error {
+		key := context.Get(IdempotencyKeyHeader)
+		if context.Method() != fiber.MethodPost || key == "" {
+			return context.Next()
+		}
+		if len(key) > 255 {
+			return response.JSONResponse(context, 400, "Idempotency-Key must be at most 255 characters", nil)
+		}
+
+		// Keys are scoped to the caller, identified by their credentials, and
+		// to the endpoint.
+		credentials := sha256.Sum256([]byte(context.Get(fiber.HeaderAuthorization)))
+		scope := hex.EncodeToString(credentials[:8]) + " " + context.Path()
+
+		request := sha256.New()
+		request.Write([]byte(context.Method() + " " + context.OriginalURL() + "\n"))
+		request.Write(context.Body())
+		fingerprint := hex.EncodeToString(request.Sum(nil))
+
+		ctx := context.UserContext()
+		stored, err := config.Store.Begin(ctx, scope, key, fingerprint, config.TTL, config.Lease)
+		switch {
+		case err == idempotency.ErrMismatch:
+			return response.JSONResponse(context, 409, "Idempotency-Key was used for a different request", nil)
+		case err == idempotency.ErrInProgress:
+			context.Set(fiber.HeaderRetryAfter, "1")
+			return response.JSONResponse(context, 409, "A request with this Idempotency-Key is in progress", nil)
+		case err != nil:
+			log.Printf("Error claiming idempotency key: %v", err)
+			return response.JSONResponse(context, 500, "Internal Server Error", nil)
+		case stored != nil:
+			context.Set("Idempotent-Replayed", "true")
+			context.Set(fiber.HeaderContentType, stored.ContentType)
+			return context.Status(stored.StatusCode).Send(stored.Body)
+		}
+
+		err = context.Next()
+
+		statusCode := context.Response().StatusCode()
+		if err != nil || statusCode >= 500 {
+			if err := config.Store.Release(ctx, scope, key); err != nil {
+				log.Printf("Error releasing idempotency key: %v", err)
+			}
+			return err
+		}
+
+		if err := config.Store.Complete(ctx, scope, key, idempotency.Response{
+			StatusCode:  statusCode,
+			ContentType: string(context.Response().Header.ContentType()),
+			Body:        context.Response().Body(),
+		}); err != nil {
+			log.Printf("Error recording idempotent response: %v", err)
+		}
+
+		return nil
+	}
+}
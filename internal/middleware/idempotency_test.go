@@ -0,0 +1,146 @@
+package middleware_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/idempotency"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+)
+
+func newIdempotentApp(t *testing.T, status int) (*fiber.App, sqlmock.Sqlmock, *int) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	calls := 0
+	app := fiber.New()
+	app.Use(middleware.Idempotency(middleware.IdempotencyConfig{
+		Store: idempotency.NewStore(db),
+		TTL:   time.Hour,
+		Lease: time.Minute,
+	}))
+	app.Post("/news", func(context *fiber.Ctx) error {
+		calls++
+		return response.JSONResponse(context, status, "Created", nil)
+	})
+
+	return app, mock, &calls
+}
+
+func postNews(t *testing.T, app *fiber.App, key string) (*http.Response, string) {
+	t.Helper()
+
+	request := httptest.NewRequest("POST", "/news", strings.NewReader(`{"title": "Hello"}`))
+	request.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		request.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := app.Test(request)
+	assert.NoError(t, err)
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestIdempotencyRecordsTheResponse(t *testing.T) {
+	app, mock, calls := newIdempotentApp(t, 201)
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").
+		WithArgs(sqlmock.AnyArg(), "key-1", sqlmock.AnyArg(), int64(60000), int64(3600000)).
+		WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+	mock.ExpectExec("UPDATE idempotency_keys").
+		WithArgs(201, "application/json", []byte(`{"message":"Created","data":null}`), sqlmock.AnyArg(), "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	resp, _ := postNews(t, app, "key-1")
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyReplays(t *testing.T) {
+	app, mock, calls := newIdempotentApp(t, 201)
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT fingerprint").
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "status_code", "content_type", "body"}))
+
+	// Repeated while the first request runs.
+	resp, _ := postNews(t, app, "key-1")
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	var fingerprint string
+	mock.ExpectQuery("INSERT INTO idempotency_keys").
+		WithArgs(sqlmock.AnyArg(), "key-2", fingerprintArg{&fingerprint}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT fingerprint").
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "status_code", "content_type", "body"}).
+			AddRow("0000", 201, "application/json", []byte(`{"message":"Created","data":{"id":1}}`)))
+
+	// The key was first used for another request.
+	resp, body := postNews(t, app, "key-2")
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.Contains(t, body, "different request")
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT fingerprint").
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "status_code", "content_type", "body"}).
+			AddRow(fingerprint, 201, "application/json", []byte(`{"message":"Created","data":{"id":1}}`)))
+
+	resp, body = postNews(t, app, "key-2")
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	assert.JSONEq(t, `{"message":"Created","data":{"id":1}}`, body)
+
+	assert.Equal(t, 0, *calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyReleasesFailures(t *testing.T) {
+	app, mock, calls := newIdempotentApp(t, 500)
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").
+		WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+	mock.ExpectExec("DELETE FROM idempotency_keys").
+		WithArgs(sqlmock.AnyArg(), "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	resp, _ := postNews(t, app, "key-1")
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	app, mock, calls := newIdempotentApp(t, 201)
+
+	resp, _ := postNews(t, app, "")
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// fingerprintArg matches any fingerprint and keeps it.
+type fingerprintArg struct {
+	value *string
+}
+
+func (arg fingerprintArg) Match(value driver.Value) bool {
+	*arg.value, _ = value.(string)
+	return len(*arg.value) == 64
+}
@@ -1,65 +1,130 @@
 package middleware
 
 import (
-	"fmt"
 	"os"
 	"strings"
 	"log"
 	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v4"
 )
 
-type JWTClaims struct {
-	UserID int `json:"user_id"`
-	jwt.RegisteredClaims
-}
+const noTokenMessage = "Unauthorized: No Token Provided"
 
-func JWTAuth() fiber.Handler {
+// JWTSecret returns the key tokens are signed with. It exits when the key is
+// not configured.
+func JWTSecret() string {
 	secret := os.Getenv("JWT_SECRET_KEY")
 	if secret == "" {
 		log.Fatal("JWT_SECRET_KEY is missing in environment variables")
 	}
 
+	return secret
+}
+
+// parseToken reads the bearer token of the request. On failure it returns the
+// message to send back to the client.
+func parseToken(context *fiber.Ctx, secret string) (*token.Claims, string) {
+	return ParseAuthorization(context.Get("Authorization"), secret)
+}
+
+// ParseAuthorization verifies an Authorization value of the form
+// "Bearer <token>", for transports other than HTTP. On failure it returns
+// the message to send back to the client.
+func ParseAuthorization(authHeader string, secret string) (*token.Claims, string) {
+	if authHeader == "" {
+		return nil, noTokenMessage
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, "Unauthorized: Invalid Token Format"
+	}
+
+	stringToken := strings.TrimPrefix(authHeader, "Bearer ")
+
+	if stringToken == "" {
+		return nil, "Unauthorized: Empty Token"
+	}
+
+	claims, err := token.Parse(stringToken, secret)
+	if err != nil {
+		log.Printf("Token parse error: %v", err)
+		return nil, "Unauthorized: Token Invalid"
+	}
+
+	return claims, ""
+}
+
+func setClaims(context *fiber.Ctx, claims *token.Claims) {
+	context.Locals("user_id", claims.UserID)
+	context.Locals("is_admin", claims.Admin)
+}
+
+func JWTAuth() fiber.Handler {
+	secret := JWTSecret()
+
 	return func(context *fiber.Ctx) error {
-		authHeader := context.Get("Authorization")
-		if authHeader == "" {
-			return response.JSONResponse(context, 401, "Unauthorized: No Token Provided", nil)
+		claims, message := parseToken(context, secret)
+		if message != "" {
+			return response.JSONResponse(context, 401, message, nil)
 		}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			return response.JSONResponse(context, 401, "Unauthorized: Invalid Token Format", nil)
-		}
+		setClaims(context, claims)
+
+		return context.Next()
+	}
+}
 
-		stringToken := strings.TrimPrefix(authHeader, "Bearer ")
+// OptionalJWTAuth lets anonymous requests through and sets user_id when a
+// token is sent. A token that is sent but invalid is still rejected.
+func OptionalJWTAuth() fiber.Handler {
+	secret := JWTSecret()
 
-		if stringToken == "" {
-			return response.JSONResponse(context, 401, "Unauthorized: Empty Token", nil)
+	return func(context *fiber.Ctx) error {
+		claims, message := parseToken(context, secret)
+		if message == noTokenMessage {
+			return context.Next()
+		}
+		if message != "" {
+			return response.JSONResponse(context, 401, message, nil)
 		}
 
-		token, err := jwt.ParseWithClaims(stringToken, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(secret), nil
-		})
+		setClaims(context, claims)
 
-		if err != nil {
-			log.Printf("Token parse error: %v", err)
-			return response.JSONResponse(context, 401, "Unauthorized: Token Invalid", nil)
-		}
+		return context.Next()
+	}
+}
 
-		if !token.Valid {
-			return response.JSONResponse(context, 401, "Unauthorized: Token Not Valid", nil)
+// StreamJWTAuth is JWTAuth for streaming routes. EventSource and browser
+// WebSockets cannot set headers, so the token may also be sent in the
+// access_token query parameter.
+func StreamJWTAuth() fiber.Handler {
+	secret := JWTSecret()
+
+	return func(context *fiber.Ctx) error {
+		authHeader := context.Get("Authorization")
+		if token := context.Query("access_token"); authHeader == "" && token != "" {
+			authHeader = "Bearer " + token
 		}
 
-		claims, ok := token.Claims.(*JWTClaims)
-		if !ok {
-			return response.JSONResponse(context, 401, "Unauthorized: Failed to Parse Token Claims", nil)
+		claims, message := ParseAuthorization(authHeader, secret)
+		if message != "" {
+			return response.JSONResponse(context, 401, message, nil)
 		}
 
-		context.Locals("user_id", claims.UserID)
+		setClaims(context, claims)
 
 		return context.Next()
 	}
-}
\ No newline at end of file
+}
+
+// RequireAdmin must run after JWTAuth.
+func RequireAdmin() fiber.Handler {
+	return func(context *fiber.Ctx) error {
+		if isAdmin, _ := context.Locals("is_admin").(bool); !isAdmin {
+			return response.JSONResponse(context, 403, "Forbidden: Admin Only", nil)
+		}
+
+		return context.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+)
+
+const testSecret = "test-secret"
+
+func signToken(t *testing.T, userId int, admin bool) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userId,
+		"admin":   admin,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	assert.NoError(t, err)
+
+	return "Bearer " + signed
+}
+
+func newTestApp() *fiber.App {
+	app := fiber.New()
+
+	whoAmI := func(context *fiber.Ctx) error {
+		userId, _ := context.Locals("user_id").(int)
+		return context.JSON(fiber.Map{"user_id": userId})
+	}
+
+	public := app.Group("/public", middleware.OptionalJWTAuth())
+	public.Get("/me", whoAmI)
+
+	admin := app.Group("/admin", middleware.JWTAuth(), middleware.RequireAdmin())
+	admin.Get("/me", whoAmI)
+
+	app.Get("/stream/me", middleware.StreamJWTAuth(), whoAmI)
+
+	app.Get("/open", whoAmI)
+
+	return app
+}
+
+func TestRouteGroups(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+	app := newTestApp()
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "public anonymous", path: "/public/me", wantStatus: 200},
+		{name: "public with token", path: "/public/me", authorization: signToken(t, 7, false), wantStatus: 200},
+		{name: "public with invalid token", path: "/public/me", authorization: "Bearer invalid", wantStatus: 401},
+		{name: "admin anonymous", path: "/admin/me", wantStatus: 401},
+		{name: "admin as user", path: "/admin/me", authorization: signToken(t, 7, false), wantStatus: 403},
+		{name: "admin as admin", path: "/admin/me", authorization: signToken(t, 1, true), wantStatus: 200},
+		{name: "stream anonymous", path: "/stream/me", wantStatus: 401},
+		{name: "stream with header", path: "/stream/me", authorization: signToken(t, 7, false), wantStatus: 200},
+		{name: "stream with query token", path: "/stream/me?access_token=" + strings.TrimPrefix(signToken(t, 7, false), "Bearer "), wantStatus: 200},
+		{name: "stream with invalid query token", path: "/stream/me?access_token=invalid", wantStatus: 401},
+		{name: "group middleware does not leak", path: "/open", wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
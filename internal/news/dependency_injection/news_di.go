@@ -1,19 +1,67 @@
 package dependency_injection
 
 import (
+	"context"
 	"database/sql"
+	"log"
+	"time"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/handler"
     newsRepository "github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
     newsService "github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
 	"github.com/go-playground/validator/v10"
 )
 
-func InitializeNews(db *sql.DB, validator *validator.Validate) *handler.NewsHandler {
-    newsRepo := newsRepository.NewNewsRepository(db)
-    newsService := newsService.NewNewsService(newsRepo)
+// Hooks connect other modules to news changes, whichever API they are made
+// through.
+type Hooks struct {
+	// Events receives every change, for stream clients.
+	Events *pubsub.Hub
+	// Change hooks run in the transaction of each change.
+	Change []newsRepository.ChangeHook
+	// Delete hooks run after a news item has been deleted.
+	Delete []newsService.DeleteHook
+	// Cache, when set, holds news reads for CacheTTL.
+	Cache    cache.Cache
+	CacheTTL time.Duration
+}
 
-    newsHandler := handler.NewNewsHandler(newsService, validator)
+// NewNewsService builds the news service used by every API.
+func NewNewsService(db *sql.DB, reads database.Querier, hooks Hooks) newsService.NewsService {
+    newsRepo := newsRepository.NewNewsRepository(db, reads, hooks.Change...)
+    if hooks.Cache != nil {
+        newsRepo = newsRepository.NewCachedNewsRepository(newsRepo, hooks.Cache, hooks.CacheTTL)
+    }
+
+    return newsService.WithEvents(newsService.NewNewsService(newsRepo, hooks.Delete...), hooks.Events)
+}
+
+func InitializeNews(db *sql.DB, reads database.Querier, validator *validator.Validate, hooks Hooks) *handler.NewsHandler {
+    newsService := NewNewsService(db, reads, hooks)
+
+    newsHandler := handler.NewNewsHandler(newsService, validator, hooks.Events)
 
     return newsHandler
-}
\ No newline at end of file
+}
+
+// announceDueNewsTask emits news.published for the news scheduled for later,
+// once they are public.
+const announceDueNewsTask = "news.announce_due"
+
+// RegisterTasks registers the scheduled tasks of news on tasks. Their changes
+// run the change hooks, as those made through the APIs.
+func RegisterTasks(tasks *scheduler.Scheduler, db *sql.DB, hooks Hooks) error {
+	newsRepo := newsRepository.NewNewsRepository(db, db, hooks.Change...)
+
+	return tasks.Register(announceDueNewsTask, "@every 1m", func(ctx context.Context) error {
+		announced, err := newsRepo.AnnounceDueNews()
+		if len(announced) > 0 {
+			log.Printf("Announced %d scheduled news", len(announced))
+		}
+		return err
+	})
+}
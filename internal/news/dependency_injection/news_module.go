@@ -0,0 +1,33 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the news to their authors, the public and the admins.
+type Module struct {
+	app.Base
+	// Hooks are shared with the other APIs serving news.
+	Hooks   Hooks
+	handler *handler.NewsHandler
+}
+
+func NewModule(hooks Hooks) *Module {
+	return &Module{Hooks: hooks}
+}
+
+func (module *Module) Name() string {
+	return "news"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	module.handler = InitializeNews(c.DB, c.Reads, c.Validator, module.Hooks)
+	return RegisterTasks(c.Scheduler, c.DB, module.Hooks)
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Root.Add(module.handler.NewsRouters, module.handler.NewsOperations())
+	routes.Public.Add(module.handler.PublicNewsRouters, module.handler.PublicNewsOperations())
+	routes.Admin.Add(module.handler.AdminNewsRouters, module.handler.AdminNewsOperations())
+}
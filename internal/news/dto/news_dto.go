@@ -1,35 +1,85 @@
 package dto
 
+import (
+	"strings"
+	"time"
+)
+
 // Request body
 type NewsCreateRequest struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title" validate:"required"`
-	Content   string `json:"content" validate:"required"`
-	AuthorId  int    `json:"user_id" validate:"required"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID       int    `json:"id"`
+	Title    string `json:"title" validate:"required"`
+	Content  string `json:"content" validate:"required"`
+	AuthorId int    `json:"user_id" validate:"required"`
+	// PublishedAt makes the news public from that time on. Omit it to
+	// create a draft.
+	PublishedAt *time.Time `json:"published_at"`
+	Tags        []string   `json:"tags" validate:"max=10,dive,required,max=50,excludesall=0x2C/"`
+	CreatedAt   string     `json:"created_at"`
+	UpdatedAt   string     `json:"updated_at"`
 }
 
 type NewsUpdateRequest struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title" validate:"required"`
-	Content   string `json:"content" validate:"required"`
-	AuthorId  int    `json:"user_id" validate:"required"`
-	UpdatedAt string `json:"updated_at"`
+	ID       int    `json:"id"`
+	Title    string `json:"title" validate:"required"`
+	Content  string `json:"content" validate:"required"`
+	AuthorId int    `json:"user_id" validate:"required"`
+	// Tags replace those of the news item. Omit them to keep its tags.
+	Tags      []string `json:"tags" validate:"max=10,dive,required,max=50,excludesall=0x2C/"`
+	UpdatedAt string   `json:"updated_at"`
 }
 
 // Response body
 type NewsResponse struct {
-	ID         int    `json:"id"`
-	Title      string `json:"title"`
-	Content    string `json:"content"`
-	AuthorId   int    `json:"user_id"`
-	AuthorName string `json:"author_name"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID              int            `json:"id"`
+	Title           string         `json:"title"`
+	Content         string         `json:"content"`
+	AuthorId        int            `json:"user_id"`
+	AuthorName      string         `json:"author_name"`
+	AuthorAvatarURL string         `json:"author_avatar_url"`
+	Reactions       ReactionCounts `json:"reactions"`
+	Tags            []string       `json:"tags"`
+	PublishedAt     string         `json:"published_at"`
+	CreatedAt       string         `json:"created_at"`
+	UpdatedAt       string         `json:"updated_at"`
+}
+
+type ReactionCounts struct {
+	Like  int `json:"like"`
+	Love  int `json:"love"`
+	Laugh int `json:"laugh"`
+	Wow   int `json:"wow"`
+	Sad   int `json:"sad"`
+	Angry int `json:"angry"`
+}
+
+// NormalizeTag returns tag as it is stored: trimmed and lowercased, so that
+// "Go" and "go " are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+type NewsPublishRequest struct {
+	// PublishedAt defaults to now when omitted.
+	PublishedAt *time.Time `json:"published_at"`
 }
 
 type NewsListResponse struct {
 	News  []NewsResponse `json:"news"`
 	Total int            `json:"total"`
 }
+
+// Events of news changes, sent to stream clients, webhooks and the outbox.
+// They carry a NewsResponse, except EventDeleted which carries a
+// DeletedEvent.
+const (
+	EventCreated     = "news.created"
+	EventUpdated     = "news.updated"
+	EventPublished   = "news.published"
+	EventUnpublished = "news.unpublished"
+	EventDeleted     = "news.deleted"
+)
+
+type DeletedEvent struct {
+	ID int `json:"id"`
+}
@@ -1,6 +1,7 @@
 package dto_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
@@ -15,7 +16,7 @@ func compareNewsListResponse(a, b dto.NewsListResponse) bool {
 		return false
 	}
 	for i := range a.News {
-		if a.News[i] != b.News[i] {
+		if !reflect.DeepEqual(a.News[i], b.News[i]) {
 			return false
 		}
 	}
@@ -58,6 +59,28 @@ func TestNewsCreateRequestValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid tags",
+			request: dto.NewsCreateRequest{
+				ID:       1,
+				Title:    "Test Title",
+				Content:  "Test Content",
+				AuthorId: 123,
+				Tags:     []string{"go", "open source"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tag with a comma",
+			request: dto.NewsCreateRequest{
+				ID:       1,
+				Title:    "Test Title",
+				Content:  "Test Content",
+				AuthorId: 123,
+				Tags:     []string{"go,rust"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Missing author ID",
 			request: dto.NewsCreateRequest{
@@ -161,7 +184,7 @@ func TestNewsResponse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.response != tt.want {
+			if !reflect.DeepEqual(tt.response, tt.want) {
 				t.Errorf("NewsResponse = %v, want %v", tt.response, tt.want)
 			}
 		})
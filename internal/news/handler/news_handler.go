@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"strconv"
 
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
 	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
 	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
@@ -15,37 +18,48 @@ import (
 type NewsHandler struct {
 	newsService newsService.NewsService
 	validation  *validator.Validate
+	events      *pubsub.Hub
 }
 
-func (handler *NewsHandler) GetAllNews(context *fiber.Ctx) error {
-	news, err := handler.newsService.GetAllNews()
-	if err != nil {
-		log.Println("Error fetching all news:", err)
-		return response.JSONResponse(context, 500, "Internal Server Error", nil)
-	}
+// newsResource answers news IDs no row has with 404, even when a service
+// wraps the error.
+var newsResource = crud.Resource[int]{
+	Name:     "news",
+	ParseID:  crud.IntID,
+	NotFound: newsService.ErrNotFound,
+}
 
-	log.Println("Successfully fetched all news")
-	return response.JSONResponse(context, 200, "Success", news)
+func (handler *NewsHandler) GetAllNews(context *fiber.Ctx) error {
+	return crud.List(newsResource, "Success", handler.newsService.GetAllNews)(context)
 }
 
 func (handler *NewsHandler) GetNewsByID(context *fiber.Ctx) error {
+	return crud.Get(newsResource, "Success", handler.newsService.GetNewsByID)(context)
+}
+
+func (handler *NewsHandler) GetPublishedNews(context *fiber.Ctx) error {
+	return crud.List(newsResource, "Success", handler.newsService.GetPublishedNews)(context)
+}
+
+func (handler *NewsHandler) GetPublishedNewsByID(context *fiber.Ctx) error {
 	newsId, err := strconv.Atoi(context.Params("id"))
 	if err != nil {
 		log.Println("Error parsing news ID:", err)
 		return response.JSONResponse(context, 400, "Bad Request", nil)
 	}
 
-	news, err := handler.newsService.GetNewsByID(newsId)
+	// Set by OptionalJWTAuth when the caller sent a token.
+	viewerId, _ := context.Locals("user_id").(int)
+
+	news, err := handler.newsService.GetPublishedNewsByID(newsId, viewerId)
 	if err != nil {
-		if err.Error() == "news not found" {
-			log.Println("News not found with ID:", newsId)
+		if errors.Is(err, newsService.ErrNotFound) {
 			return response.JSONResponse(context, 404, "Not Found", nil)
 		}
-		log.Println("Error fetching news by ID:", err)
+		log.Println("Error fetching published news by ID:", err)
 		return response.JSONResponse(context, 500, "Internal Server Error", nil)
 	}
 
-	log.Println("Successfully fetched news with ID:", newsId)
 	return response.JSONResponse(context, 200, "Success", news)
 }
 
@@ -96,7 +110,7 @@ func (handler *NewsHandler) UpdateNews(context *fiber.Ctx) error {
 
 	if err := handler.newsService.UpdateNews(id, news); err != nil {
 		log.Println("Error updating news with ID:", id, "Error:", err)
-		if err.Error() == "news not found" {
+		if errors.Is(err, newsService.ErrNotFound) {
 			return response.JSONResponse(context, 404, "Not Found", nil)
 		}
 		return response.JSONResponse(context, 500, "Internal Server Error", nil)
@@ -107,32 +121,92 @@ func (handler *NewsHandler) UpdateNews(context *fiber.Ctx) error {
 }
 
 func (handler *NewsHandler) DeleteNews(context *fiber.Ctx) error {
+	return crud.Delete(newsResource, "Success", handler.newsService.DeleteNews)(context)
+}
+
+func (handler *NewsHandler) PublishNews(context *fiber.Ctx) error {
 	id, err := strconv.Atoi(context.Params("id"))
 	if err != nil {
-		log.Println("Error parsing news ID for deletion:", err)
+		log.Println("Error parsing news ID for publishing:", err)
 		return response.JSONResponse(context, 400, "Bad Request", nil)
 	}
-	if err := handler.newsService.DeleteNews(id); err != nil {
-		log.Println("Error deleting news with ID:", id, "Error:", err)
+
+	var request dto.NewsPublishRequest
+	if len(context.Body()) > 0 {
+		if err := context.BodyParser(&request); err != nil {
+			log.Println("Error parsing request body for publishing news:", err)
+			return response.JSONResponse(context, 400, "Bad Request", nil)
+		}
+	}
+
+	if err := handler.newsService.PublishNews(id, request.PublishedAt); err != nil {
+		if errors.Is(err, newsService.ErrNotFound) {
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		}
+		log.Println("Error publishing news with ID:", id, "Error:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	log.Println("Successfully published news with ID:", id)
+	return response.JSONResponse(context, 200, "Success", nil)
+}
+
+func (handler *NewsHandler) UnpublishNews(context *fiber.Ctx) error {
+	id, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for unpublishing:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	if err := handler.newsService.UnpublishNews(id); err != nil {
+		if errors.Is(err, newsService.ErrNotFound) {
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		}
+		log.Println("Error unpublishing news with ID:", id, "Error:", err)
 		return response.JSONResponse(context, 500, "Internal Server Error", nil)
 	}
 
-	log.Println("Successfully deleted news with ID:", id)
+	log.Println("Successfully unpublished news with ID:", id)
 	return response.JSONResponse(context, 200, "Success", nil)
 }
 
+// NewsRouters registers the routes for signed-in users. JWTAuth is attached
+// per route so it does not leak onto routes registered later on the same
+// router.
 func (handler *NewsHandler) NewsRouters(router fiber.Router) {
-	router.Use(middleware.JWTAuth())
+	// Responses are private, but clients can revalidate them with their ETag.
+	revalidate := middleware.HTTPCache(middleware.HTTPCacheConfig{})
+
+	router.Get("/news", middleware.JWTAuth(), revalidate, handler.GetAllNews)
+	// Before /news/:id, which would match them too.
+	router.Get("/news/stream", middleware.StreamJWTAuth(), handler.StreamNews)
+	router.Get("/news/ws", middleware.StreamJWTAuth(), handler.StreamNewsSocket)
+	router.Get("/news/:id", middleware.JWTAuth(), revalidate, handler.GetNewsByID)
+	router.Post("/news", middleware.JWTAuth(), handler.CreateNews)
+	router.Put("/news/:id", middleware.JWTAuth(), handler.UpdateNews)
+	router.Delete("/news/:id", middleware.JWTAuth(), handler.DeleteNews)
+}
+
+// PublicNewsRouters registers read-only routes serving published news. They
+// are meant for a group using OptionalJWTAuth and HTTPCache.
+func (handler *NewsHandler) PublicNewsRouters(router fiber.Router) {
+	router.Get("/news", handler.GetPublishedNews)
+	router.Get("/news/:id", handler.GetPublishedNewsByID)
+}
+
+// AdminNewsRouters registers editorial routes. They are meant for a group
+// using JWTAuth and RequireAdmin.
+func (handler *NewsHandler) AdminNewsRouters(router fiber.Router) {
 	router.Get("/news", handler.GetAllNews)
-	router.Get("/news/:id", handler.GetNewsByID)
-	router.Post("/news", handler.CreateNews)
-	router.Put("/news/:id", handler.UpdateNews)
+	router.Put("/news/:id/publish", handler.PublishNews)
+	router.Delete("/news/:id/publish", handler.UnpublishNews)
 	router.Delete("/news/:id", handler.DeleteNews)
 }
 
-func NewNewsHandler(newsService newsService.NewsService, validation *validator.Validate) *NewsHandler {
+func NewNewsHandler(newsService newsService.NewsService, validation *validator.Validate, events *pubsub.Hub) *NewsHandler {
 	return &NewsHandler{
 		newsService: newsService,
 		validation:  validation,
+		events:      events,
 	}
 }
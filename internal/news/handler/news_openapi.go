@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var newsTags = []string{"News"}
+
+// streamParams are accepted by the stream routes, for clients that cannot
+// set the Authorization and Last-Event-ID headers.
+var streamParams = []openapi.Param{
+	{Name: "access_token", Type: "string", Description: "JWT, instead of the Authorization header"},
+	{Name: "last_event_id", Type: "integer", Description: "Resume after this event, like the Last-Event-ID header"},
+}
+
+// NewsOperations documents the routes of NewsRouters.
+func (handler *NewsHandler) NewsOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/news", Summary: "Get all news, drafts included", Tags: newsTags, Security: openapi.BearerAuth,
+			Response: dto.NewsListResponse{}, Errors: []int{500}},
+		{Method: "GET", Path: "/news/stream", Summary: "Stream news changes as Server-Sent Events", Tags: newsTags, Security: openapi.BearerAuth,
+			Query: streamParams, ContentType: "text/event-stream", Errors: []int{400}},
+		{Method: "GET", Path: "/news/ws", Summary: "Stream news changes over a WebSocket", Tags: newsTags, Security: openapi.BearerAuth,
+			Query: streamParams, Status: 101, Errors: []int{400, 426}},
+		{Method: "GET", Path: "/news/:id", Summary: "Get a news by id", Tags: newsTags, Security: openapi.BearerAuth,
+			Response: dto.NewsResponse{}, Errors: []int{400, 404, 500}},
+		{Method: "POST", Path: "/news", Summary: "Create a news", Tags: newsTags, Security: openapi.BearerAuth,
+			Request: dto.NewsCreateRequest{}, Status: 201, Errors: []int{400, 422, 500}},
+		{Method: "PUT", Path: "/news/:id", Summary: "Edit a news", Tags: newsTags, Security: openapi.BearerAuth,
+			Request: dto.NewsUpdateRequest{}, Errors: []int{400, 404, 422, 500}},
+		{Method: "DELETE", Path: "/news/:id", Summary: "Delete a news", Tags: newsTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 404, 500}},
+	}
+}
+
+// PublicNewsOperations documents the routes of PublicNewsRouters.
+func (handler *NewsHandler) PublicNewsOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/news", Summary: "Get published news", Tags: newsTags, Security: openapi.OptionalBearerAuth,
+			Response: dto.NewsListResponse{}, Errors: []int{500}},
+		{Method: "GET", Path: "/news/:id", Summary: "Get a published news, or your own draft", Tags: newsTags, Security: openapi.OptionalBearerAuth,
+			Response: dto.NewsResponse{}, Errors: []int{400, 404, 500}},
+	}
+}
+
+// AdminNewsOperations documents the routes of AdminNewsRouters.
+func (handler *NewsHandler) AdminNewsOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/news", Summary: "Get all news, drafts included", Tags: newsTags, Security: openapi.BearerAuth,
+			Response: dto.NewsListResponse{}, Errors: []int{403, 500}},
+		{Method: "PUT", Path: "/news/:id/publish", Summary: "Publish a news now or at published_at", Tags: newsTags, Security: openapi.BearerAuth,
+			Request: dto.NewsPublishRequest{}, Errors: []int{400, 403, 404, 500}},
+		{Method: "DELETE", Path: "/news/:id/publish", Summary: "Turn a news back into a draft", Tags: newsTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 403, 404, 500}},
+		{Method: "DELETE", Path: "/news/:id", Summary: "Delete any news", Tags: newsTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 403, 404, 500}},
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// streamKeepAlive is how often an idle stream is pinged, so that proxies
+	// keep it open and dead clients are noticed.
+	streamKeepAlive = 15 * time.Second
+	streamWriteWait = 10 * time.Second
+	// streamRetry is how long EventSource waits before reconnecting.
+	streamRetry = 3 * time.Second
+)
+
+// lastEventID reads where a client resumes from: the Last-Event-ID header
+// EventSource sends when reconnecting, or the last_event_id query parameter.
+func lastEventID(context *fiber.Ctx) (uint64, error) {
+	value := context.Get("Last-Event-ID", context.Query("last_event_id"))
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// StreamNews pushes news changes as Server-Sent Events. A client dropped for
+// being too slow sees the stream end, and resumes with Last-Event-ID.
+func (handler *NewsHandler) StreamNews(context *fiber.Ctx) error {
+	lastId, err := lastEventID(context)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	subscription := handler.events.Subscribe(lastId)
+
+	context.Set("Content-Type", "text/event-stream")
+	context.Set("Cache-Control", "no-cache")
+	context.Set("Connection", "keep-alive")
+	context.Set("X-Accel-Buffering", "no")
+
+	context.Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		defer subscription.Close()
+
+		ticker := time.NewTicker(streamKeepAlive)
+		defer ticker.Stop()
+
+		fmt.Fprintf(writer, "retry: %d\n\n", streamRetry.Milliseconds())
+		for {
+			if err := writer.Flush(); err != nil {
+				log.Println("News stream client went away:", err)
+				return
+			}
+
+			select {
+			case event, ok := <-subscription.Events():
+				if !ok {
+					return
+				}
+				fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			case <-ticker.C:
+				fmt.Fprint(writer, ": keep-alive\n\n")
+			}
+		}
+	})
+
+	return nil
+}
+
+// StreamNewsSocket pushes the same events as StreamNews over a WebSocket,
+// one JSON pubsub.Event per message. Resuming goes through last_event_id.
+func (handler *NewsHandler) StreamNewsSocket(context *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(context) {
+		return response.JSONResponse(context, 426, "Upgrade Required", nil)
+	}
+
+	lastId, err := lastEventID(context)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	return websocket.New(func(conn *websocket.Conn) {
+		handler.pushEvents(conn, handler.events.Subscribe(lastId))
+	})(context)
+}
+
+func (handler *NewsHandler) pushEvents(conn *websocket.Conn, subscription *pubsub.Subscription) {
+	defer subscription.Close()
+
+	// Clients send nothing but control frames; reading is how a closed
+	// connection is noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow, resume with last_event_id")
+				conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(streamWriteWait))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				log.Println("News socket client went away:", err)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
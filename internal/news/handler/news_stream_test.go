@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
+)
+
+const testSecret = "test-secret"
+
+// serve starts the news routes on a real listener, since streams never end
+// and cannot go through app.Test.
+func serve(t *testing.T) (string, *pubsub.Hub) {
+	t.Helper()
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+
+	events := pubsub.NewHub(pubsub.Config{ReplaySize: 10, ClientBuffer: 10})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	handler.NewNewsHandler(nil, validator.New(), events).NewsRouters(app)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go app.Listener(listener)
+	t.Cleanup(func() { app.ShutdownWithTimeout(time.Second) })
+
+	return listener.Addr().String(), events
+}
+
+func token(t *testing.T) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 7,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	assert.NoError(t, err)
+
+	return signed
+}
+
+// readEvent reads the next event of an SSE stream, skipping comments and
+// the retry field.
+func readEvent(t *testing.T, reader *bufio.Reader) map[string]string {
+	t.Helper()
+
+	event := map[string]string{}
+	for {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return event
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if _, ok := event["id"]; ok {
+				return event
+			}
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		event[field] = value
+	}
+}
+
+func TestStreamNews(t *testing.T) {
+	address, events := serve(t)
+	events.Publish("news.created", map[string]int{"id": 1})
+	events.Publish("news.updated", map[string]int{"id": 1})
+
+	t.Run("requires a token", func(t *testing.T) {
+		resp, err := http.Get("http://" + address + "/news/stream")
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	req, err := http.NewRequest("GET", "http://"+address+"/news/stream?access_token="+token(t), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	replayed := readEvent(t, reader)
+	assert.Equal(t, "2", replayed["id"])
+	assert.Equal(t, "news.updated", replayed["event"])
+	assert.Equal(t, `{"id":1}`, replayed["data"])
+
+	events.Publish("news.deleted", map[string]int{"id": 1})
+	live := readEvent(t, reader)
+	assert.Equal(t, "3", live["id"])
+	assert.Equal(t, "news.deleted", live["event"])
+}
+
+func TestStreamNewsSocket(t *testing.T) {
+	address, events := serve(t)
+	events.Publish("news.created", map[string]int{"id": 1})
+	events.Publish("news.updated", map[string]int{"id": 1})
+
+	resp, err := http.Get("http://" + address + "/news/ws?access_token=" + token(t))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 426, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+address+"/news/ws?last_event_id=1&access_token="+token(t), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var replayed pubsub.Event
+	assert.NoError(t, conn.ReadJSON(&replayed))
+	assert.Equal(t, uint64(2), replayed.ID)
+	assert.Equal(t, "news.updated", replayed.Type)
+
+	// The replayed event proves the subscription exists.
+	events.Publish("news.deleted", map[string]int{"id": 1})
+
+	var live pubsub.Event
+	assert.NoError(t, conn.ReadJSON(&live))
+	assert.Equal(t, uint64(3), live.ID)
+	assert.JSONEq(t, `{"id":1}`, string(live.Data))
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheName is the key of the news counters in cache.Hits and cache.Misses.
+const CacheName = "news"
+
+const allNewsKey = "news:all"
+
+func newsKey(id int) string {
+	return "news:id:" + strconv.Itoa(id)
+}
+
+// cachedNewsRepository caches GetAllNews and GetNewsById. Changes made through
+// it drop the entries they affect, but reaction counts and author names are
+// changed by other modules and can stay stale for up to ttl.
+type cachedNewsRepository struct {
+	NewsRepository
+	cache *cache.Fenced
+	ttl   time.Duration
+	group *singleflight.Group
+	tx    *database.Tx
+}
+
+// NewCachedNewsRepository wraps repo so that its reads go through store.
+// Concurrent misses on one key share a single query, and a failing cache is
+// bypassed rather than failing reads. A read racing a change is not stored
+// once the change invalidated it; for that to hold across repositories
+// sharing the cache, they must share the *cache.Fenced too.
+func NewCachedNewsRepository(repo NewsRepository, store cache.Cache, ttl time.Duration) NewsRepository {
+	fenced, ok := store.(*cache.Fenced)
+	if !ok {
+		fenced = cache.NewFenced(store)
+	}
+	return &cachedNewsRepository{NewsRepository: repo, cache: fenced, ttl: ttl, group: &singleflight.Group{}}
+}
+
+// WithTx returns a copy of the repository running its queries in tx. Its
+// reads bypass the cache, as they can see changes not committed yet, and its
+// changes invalidate entries once tx commits.
+func (repo *cachedNewsRepository) WithTx(tx *database.Tx) NewsRepository {
+	bound := *repo
+	bound.NewsRepository, bound.tx = repo.NewsRepository.WithTx(tx), tx
+	return &bound
+}
+
+// load returns the value cached under key, or stores what fetch returns
+// unless the news changed meanwhile. Errors are not cached.
+func load[T any](repo *cachedNewsRepository, key string, fetch func() (*T, error)) (*T, error) {
+	if repo.tx != nil {
+		return fetch()
+	}
+
+	ctx := context.Background()
+
+	data, ok, err := repo.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("Error reading cache key %q: %v", key, err)
+	}
+	if ok {
+		cache.Hits.Add(CacheName, 1)
+	} else {
+		cache.Misses.Add(CacheName, 1)
+
+		shared, err, _ := repo.group.Do(key, func() (any, error) {
+			generation := repo.cache.Generation()
+			value, err := fetch()
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.cache.Fill(ctx, generation, key, data, repo.ttl); err != nil {
+				log.Printf("Error writing cache key %q: %v", key, err)
+			}
+			return data, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		data = shared.([]byte)
+	}
+
+	// Every caller decodes its own copy, so none can change what another got.
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// invalidate drops keys once a change has been made. The change is not
+// undone when that fails, so the entries are only stale until they expire.
+func (repo *cachedNewsRepository) invalidate(err error, keys ...string) error {
+	if err != nil {
+		return err
+	}
+
+	invalidate := func() {
+		if err := repo.cache.Delete(context.Background(), keys...); err != nil {
+			log.Printf("Error invalidating cache keys %v: %v", keys, err)
+		}
+	}
+
+	if repo.tx != nil {
+		repo.tx.AfterCommit(invalidate)
+	} else {
+		invalidate()
+	}
+	return nil
+}
+
+func (repo *cachedNewsRepository) GetAllNews() (*dto.NewsListResponse, error) {
+	return load(repo, allNewsKey, repo.NewsRepository.GetAllNews)
+}
+
+func (repo *cachedNewsRepository) GetNewsById(id int) (*dto.NewsResponse, error) {
+	return load(repo, newsKey(id), func() (*dto.NewsResponse, error) {
+		return repo.NewsRepository.GetNewsById(id)
+	})
+}
+
+func (repo *cachedNewsRepository) CreateNews(news *dto.NewsCreateRequest) error {
+	return repo.invalidate(repo.NewsRepository.CreateNews(news), allNewsKey)
+}
+
+func (repo *cachedNewsRepository) UpdateNews(id int, news dto.NewsUpdateRequest) error {
+	return repo.invalidate(repo.NewsRepository.UpdateNews(id, news), allNewsKey, newsKey(id))
+}
+
+func (repo *cachedNewsRepository) DeleteNews(id int) error {
+	return repo.invalidate(repo.NewsRepository.DeleteNews(id), allNewsKey, newsKey(id))
+}
+
+func (repo *cachedNewsRepository) PublishNews(id int, publishedAt *time.Time) error {
+	return repo.invalidate(repo.NewsRepository.PublishNews(id, publishedAt), allNewsKey, newsKey(id))
+}
@@ -0,0 +1,179 @@
+package repository_test
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+)
+
+func cacheCount(counters *expvar.Map) int64 {
+	if count, ok := counters.Get(repository.CacheName).(*expvar.Int); ok {
+		return count.Value()
+	}
+	return 0
+}
+
+func TestCachedNewsRepository(t *testing.T) {
+	newsRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows(newsColumns).
+			AddRow(1, "Title", "Content", 1, "Author", "2024-01-01", "2024-01-01", "", nil, 2, 0, 0, 0, 0, 0, nil)
+	}
+
+	t.Run("serves reads from the cache until a change", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		repo := repository.NewCachedNewsRepository(repository.NewNewsRepository(db, db), cache.NewMemory(10), time.Minute)
+		hits, misses := cacheCount(cache.Hits), cacheCount(cache.Misses)
+
+		mock.ExpectQuery("WHERE n.id = \\$1").WithArgs(1).WillReturnRows(newsRow())
+
+		for range 3 {
+			news, err := repo.GetNewsById(1)
+			assert.NoError(t, err)
+			assert.Equal(t, "Title", news.Title)
+			assert.Equal(t, 2, news.Reactions.Like)
+		}
+		assert.Equal(t, hits+2, cacheCount(cache.Hits))
+		assert.Equal(t, misses+1, cacheCount(cache.Misses))
+
+		mock.ExpectExec("UPDATE news SET title").WillReturnResult(sqlmock.NewResult(0, 1))
+		assert.NoError(t, repo.UpdateNews(1, dto.NewsUpdateRequest{Title: "New", Content: "Content", AuthorId: 1}))
+
+		mock.ExpectQuery("WHERE n.id = \\$1").WithArgs(1).WillReturnRows(newsRow())
+		_, err = repo.GetNewsById(1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalidates the list on delete", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		repo := repository.NewCachedNewsRepository(repository.NewNewsRepository(db, db), cache.NewMemory(10), time.Minute)
+
+		mock.ExpectQuery("ORDER BY n.id").WillReturnRows(newsRow())
+		mock.ExpectExec("DELETE FROM news").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("ORDER BY n.id").WillReturnRows(sqlmock.NewRows(newsColumns))
+
+		list, err := repo.GetAllNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, list.Total)
+
+		list, err = repo.GetAllNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, list.Total)
+
+		assert.NoError(t, repo.DeleteNews(1))
+
+		list, err = repo.GetAllNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, list.Total)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		repo := repository.NewCachedNewsRepository(repository.NewNewsRepository(db, db), cache.NewMemory(10), time.Minute)
+
+		mock.ExpectQuery("WHERE n.id = \\$1").WithArgs(1).WillReturnError(errors.New("connection reset"))
+		mock.ExpectQuery("WHERE n.id = \\$1").WithArgs(1).WillReturnRows(newsRow())
+
+		_, err = repo.GetNewsById(1)
+		assert.EqualError(t, err, "connection reset")
+
+		_, err = repo.GetNewsById(1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("shares one query between concurrent misses", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		repo := repository.NewCachedNewsRepository(repository.NewNewsRepository(db, db), cache.NewMemory(10), time.Minute)
+
+		mock.ExpectQuery("WHERE n.id = \\$1").WithArgs(1).WillDelayFor(50 * time.Millisecond).WillReturnRows(newsRow())
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				news, err := repo.GetNewsById(1)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, news.ID)
+			}()
+		}
+		wg.Wait()
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("does not store a read racing a change", func(t *testing.T) {
+		stub := &slowNewsRepository{title: "Old", fetching: make(chan struct{}, 2), release: make(chan struct{})}
+		repo := repository.NewCachedNewsRepository(stub, cache.NewMemory(10), time.Minute)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			news, err := repo.GetNewsById(1)
+			assert.NoError(t, err)
+			assert.Equal(t, "Old", news.Title)
+		}()
+
+		// The change lands while the read is in flight.
+		<-stub.fetching
+		assert.NoError(t, repo.UpdateNews(1, dto.NewsUpdateRequest{Title: "New"}))
+		close(stub.release)
+		<-done
+
+		news, err := repo.GetNewsById(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "New", news.Title)
+	})
+}
+
+// slowNewsRepository reads a news as it is when GetNewsById is called, but
+// returns it once release is closed.
+type slowNewsRepository struct {
+	repository.NewsRepository
+	mu       sync.Mutex
+	title    string
+	fetching chan struct{}
+	release  chan struct{}
+}
+
+func (repo *slowNewsRepository) GetNewsById(id int) (*dto.NewsResponse, error) {
+	repo.mu.Lock()
+	news := &dto.NewsResponse{ID: id, Title: repo.title}
+	repo.mu.Unlock()
+
+	repo.fetching <- struct{}{}
+	<-repo.release
+	return news, nil
+}
+
+func (repo *slowNewsRepository) UpdateNews(id int, news dto.NewsUpdateRequest) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.title = news.Title
+	return nil
+}
@@ -1,89 +1,227 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	userModel "github.com/ahmadammarm/go-rest-api-template/internal/user/model"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
 )
 
 type NewsRepository interface {
 	GetAllNews() (*dto.NewsListResponse, error)
+	GetPublishedNews() (*dto.NewsListResponse, error)
 	GetNewsById(id int) (*dto.NewsResponse, error)
+	GetPublishedNewsById(id int) (*dto.NewsResponse, error)
 	CreateNews(news *dto.NewsCreateRequest) error
 	UpdateNews(id int, news dto.NewsUpdateRequest) error
 	DeleteNews(id int) error
+	PublishNews(id int, publishedAt *time.Time) error
+	// AnnounceDueNews emits dto.EventPublished for the news scheduled for
+	// a time that has now passed, once per publication, and returns their
+	// IDs.
+	AnnounceDueNews() ([]int, error)
+	// GetLatestNews returns the newest published news first, limited to
+	// one author when authorId is not 0, and to one tag when tag is not
+	// empty.
+	GetLatestNews(limit int, authorId int, tag string) ([]dto.NewsResponse, error)
+	// WithTx returns the repository running its queries in tx, for
+	// services to make several calls atomically.
+	WithTx(tx *database.Tx) NewsRepository
 }
 
-type newsRepository struct {
-	db *sql.DB
+// reactionCountColumns aggregates news_reactions per reaction type. It must stay
+// in the same order as reactionCountTargets.
+const reactionCountColumns = `COUNT(r.user_id) FILTER (WHERE r.type = 'like') AS like_count,
+              COUNT(r.user_id) FILTER (WHERE r.type = 'love') AS love_count,
+              COUNT(r.user_id) FILTER (WHERE r.type = 'laugh') AS laugh_count,
+              COUNT(r.user_id) FILTER (WHERE r.type = 'wow') AS wow_count,
+              COUNT(r.user_id) FILTER (WHERE r.type = 'sad') AS sad_count,
+              COUNT(r.user_id) FILTER (WHERE r.type = 'angry') AS angry_count`
+
+// tagsColumn lists the tags of a news item, separated by commas, which tags
+// cannot contain.
+const tagsColumn = `(SELECT string_agg(t.tag, ',' ORDER BY t.tag) FROM news_tags t WHERE t.news_id = n.id) AS tags`
+
+func reactionCountTargets(counts *dto.ReactionCounts) []any {
+	return []any{&counts.Like, &counts.Love, &counts.Laugh, &counts.Wow, &counts.Sad, &counts.Angry}
 }
 
-func (repo *newsRepository) GetAllNews() (*dto.NewsListResponse, error) {
-	query := `SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at
-              FROM news n
-              JOIN users u ON n.user_id = u.id`
+// ErrNotFound is returned for a news ID no row has.
+var ErrNotFound = errors.New("news not found")
+
+// newsTable maps news, with their author and reaction counts, to
+// dto.NewsResponse.
+var newsTable = crud.Table[dto.NewsResponse]{
+	Name:  "news",
+	Alias: "n",
+	Joins: `JOIN users u ON n.user_id = u.id
+              LEFT JOIN news_reactions r ON r.news_id = n.id`,
+	Key: "id",
+	Columns: []string{"n.id", "n.title", "n.content", "n.user_id", "u.name AS author_name", "n.created_at", "n.updated_at",
+		"u.avatar_key AS author_avatar_key", "n.published_at", reactionCountColumns, tagsColumn},
+	GroupBy:  "n.id, u.name, u.avatar_key",
+	OrderBy:  "n.id",
+	Scan:     scanNews,
+	NotFound: ErrNotFound,
+}
 
-	rows, err := repo.db.Query(query)
+// publishedCondition keeps drafts and news scheduled for later out of public
+// results.
+const publishedCondition = `n.published_at IS NOT NULL AND n.published_at <= NOW()`
 
-	if err != nil {
-		return nil, err
-	}
+// ChangeHook runs in the transaction of a change, with one of the dto.Event*
+// events and its data, so that what it writes commits or rolls back with the
+// change. An error aborts the change.
+type ChangeHook func(tx *sql.Tx, event string, data any) error
 
-	defer rows.Close()
+type newsRepository struct {
+	news *crud.Repository[dto.NewsResponse, int]
+	// db is the database, or tx when the repository is bound to one.
+	db database.Querier
+	// reads run lists on the read replicas, if any, unless bound to tx.
+	reads        database.Querier
+	transactions *database.TxManager
+	tx           *database.Tx
+	changeHooks  []ChangeHook
+}
 
-	var news []dto.NewsResponse
+// WithTx returns a copy of the repository running its queries in tx.
+func (repo *newsRepository) WithTx(tx *database.Tx) NewsRepository {
+	bound := *repo
+	bound.news = repo.news.With(tx)
+	bound.db, bound.reads, bound.tx = tx, tx, tx
+	return &bound
+}
 
-	for rows.Next() {
-		var n dto.NewsResponse
-		err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.AuthorId, &n.AuthorName, &n.CreatedAt, &n.UpdatedAt)
+// write applies change, which returns the ID of the news item and the events
+// it caused, then runs the change hooks in the same transaction. Without
+// hooks, a single statement needs no transaction.
+func (repo *newsRepository) write(single bool, change func(q database.Querier) (int, []string, error)) error {
+	if single && len(repo.changeHooks) == 0 {
+		_, _, err := change(repo.db)
+		return err
+	}
+
+	run := func(tx *database.Tx) error {
+		id, events, err := change(tx)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		news = append(news, n)
+
+		var news *dto.NewsResponse
+		for _, event := range events {
+			var data any = dto.DeletedEvent{ID: id}
+			if event != dto.EventDeleted {
+				if news == nil {
+					if news, err = repo.news.With(tx).Get(id); err != nil {
+						return err
+					}
+				}
+				data = news
+			}
+
+			for _, hook := range repo.changeHooks {
+				if err := hook(tx.Tx, event, data); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+
+	if repo.tx != nil {
+		return run(repo.tx)
+	}
+	return repo.transactions.Run(context.Background(), run)
+}
+
+func scanNews(scanner crud.Scanner, n *dto.NewsResponse) error {
+	var authorAvatarKey string
+	var publishedAt, tags sql.NullString
+
+	targets := append([]any{&n.ID, &n.Title, &n.Content, &n.AuthorId, &n.AuthorName, &n.CreatedAt, &n.UpdatedAt, &authorAvatarKey, &publishedAt}, reactionCountTargets(&n.Reactions)...)
+	if err := scanner.Scan(append(targets, &tags)...); err != nil {
+		return err
+	}
+
+	n.AuthorAvatarURL = userModel.AvatarURL(n.AuthorId, authorAvatarKey)
+	n.PublishedAt = publishedAt.String
+	n.Tags = []string{}
+	if tags.String != "" {
+		n.Tags = strings.Split(tags.String, ",")
 	}
 
-	total := len(news)
+	return nil
+}
+
+func newsList(news []dto.NewsResponse, err error) (*dto.NewsListResponse, error) {
+	if err != nil {
+		return nil, err
+	}
 
 	return &dto.NewsListResponse{
 		News:  news,
-		Total: total,
+		Total: len(news),
 	}, nil
 }
 
+func (repo *newsRepository) GetAllNews() (*dto.NewsListResponse, error) {
+	return newsList(repo.news.All())
+}
+
+func (repo *newsRepository) GetPublishedNews() (*dto.NewsListResponse, error) {
+	return newsList(repo.news.Where(publishedCondition, "n.published_at DESC, n.id DESC"))
+}
+
 func (repo *newsRepository) GetNewsById(id int) (*dto.NewsResponse, error) {
-	query := `SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at
-              FROM news n
-              JOIN users u ON n.user_id = u.id WHERE n.id = $1`
+	return repo.news.Get(id)
+}
 
-	var n dto.NewsResponse
-	err := repo.db.QueryRow(query, id).Scan(&n.ID, &n.Title, &n.Content, &n.AuthorId, &n.AuthorName, &n.CreatedAt, &n.UpdatedAt)
+func (repo *newsRepository) GetPublishedNewsById(id int) (*dto.NewsResponse, error) {
+	return repo.news.Find(`n.id = $1 AND `+publishedCondition, id)
+}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("news not found")
-		}
-		return nil, err
+// publication returns the event of publishing at publishedAt, and when it is
+// written: dto.EventPublished now if publishedAt has passed. News scheduled
+// for later are only updated, and announced by AnnounceDueNews once public.
+func publication(publishedAt *time.Time) (string, *time.Time) {
+	now := time.Now()
+	switch {
+	case publishedAt == nil:
+		return dto.EventUnpublished, nil
+	case publishedAt.After(now):
+		return dto.EventUpdated, nil
+	default:
+		return dto.EventPublished, &now
 	}
-
-	return &n, nil
 }
 
 func (repo *newsRepository) CreateNews(news *dto.NewsCreateRequest) error {
-	query := "INSERT INTO news (title, content, user_id) VALUES ($1, $2, $3) RETURNING id"
+	query := "INSERT INTO news (title, content, user_id, published_at, published_event_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
 
-	err := repo.db.QueryRow(query, news.Title, news.Content, news.AuthorId).Scan(&news.ID)
+	event, publishedEventAt := publication(news.PublishedAt)
 
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return repo.write(len(news.Tags) == 0, func(q database.Querier) (int, []string, error) {
+		if err := q.QueryRow(query, news.Title, news.Content, news.AuthorId, news.PublishedAt, publishedEventAt).Scan(&news.ID); err != nil {
+			return 0, nil, err
+		}
+		if err := writeTags(q, news.ID, news.Tags); err != nil {
+			return 0, nil, err
+		}
 
+		events := []string{dto.EventCreated}
+		if event == dto.EventPublished {
+			events = append(events, dto.EventPublished)
+		}
+		return news.ID, events, nil
+	})
 }
 
 func (repo *newsRepository) UpdateNews(id int, news dto.NewsUpdateRequest) error {
@@ -91,44 +229,159 @@ func (repo *newsRepository) UpdateNews(id int, news dto.NewsUpdateRequest) error
 
 	updatedAt := time.Now()
 
-	result, err := repo.db.Exec(query, news.Title, news.Content, news.AuthorId, updatedAt, id)
+	return repo.write(news.Tags == nil, func(q database.Querier) (int, []string, error) {
+		result, err := q.Exec(query, news.Title, news.Content, news.AuthorId, updatedAt, id)
+		if err != nil {
+			return 0, nil, err
+		}
+		if err := crud.ExpectOne(result, ErrNotFound); err != nil {
+			return 0, nil, err
+		}
 
-	if err != nil {
-		return err
-	}
+		if news.Tags != nil {
+			if _, err := q.Exec("DELETE FROM news_tags WHERE news_id = $1", id); err != nil {
+				return 0, nil, err
+			}
+			if err := writeTags(q, id, news.Tags); err != nil {
+				return 0, nil, err
+			}
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		return id, []string{dto.EventUpdated}, nil
+	})
+}
 
-	if rowsAffected == 0 {
-		return errors.New("news not found")
-	}
+// writeTags adds tags to the news item id, normalized and once each.
+func writeTags(q database.Querier, id int, tags []string) error {
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = dto.NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
 
+		if _, err := q.Exec("INSERT INTO news_tags (news_id, tag) VALUES ($1, $2)", id, tag); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (repo *newsRepository) DeleteNews(id int) error {
-	query := "DELETE FROM news WHERE id = $1"
+	return repo.write(true, func(q database.Querier) (int, []string, error) {
+		return id, []string{dto.EventDeleted}, repo.news.With(q).Delete(id)
+	})
+}
+
+// PublishNews sets when a news item becomes public. A nil publishedAt turns
+// it back into a draft.
+func (repo *newsRepository) PublishNews(id int, publishedAt *time.Time) error {
+	query := "UPDATE news SET published_at = $1, published_event_at = $2 WHERE id = $3"
+
+	event, publishedEventAt := publication(publishedAt)
+
+	return repo.write(true, func(q database.Querier) (int, []string, error) {
+		result, err := q.Exec(query, publishedAt, publishedEventAt, id)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		return id, []string{event}, crud.ExpectOne(result, ErrNotFound)
+	})
+}
 
-	result, err := repo.db.Exec(query, id)
+// AnnounceDueNews announces each news item in its own transaction, which only
+// one of concurrent calls makes the change of.
+func (repo *newsRepository) AnnounceDueNews() ([]int, error) {
+	rows, err := repo.db.Query(`SELECT id FROM news WHERE published_event_at IS NULL AND published_at <= NOW() ORDER BY published_at, id`)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	var due []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE news SET published_event_at = NOW() WHERE id = $1 AND published_event_at IS NULL AND published_at <= NOW()`
+
+	announced := []int{}
+	for _, id := range due {
+		var changed int64
+		err := repo.write(true, func(q database.Querier) (int, []string, error) {
+			result, err := q.Exec(query, id)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			// Announced meanwhile, or rescheduled.
+			if changed, err = result.RowsAffected(); err != nil || changed == 0 {
+				return id, nil, err
+			}
+			return id, []string{dto.EventPublished}, nil
+		})
+		if err != nil {
+			return announced, err
+		}
+		if changed > 0 {
+			announced = append(announced, id)
+		}
+	}
+
+	return announced, nil
+}
+
+func (repo *newsRepository) GetLatestNews(limit int, authorId int, tag string) ([]dto.NewsResponse, error) {
+	query := `SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at, n.published_at
+              FROM news n
+              JOIN users u ON n.user_id = u.id
+              WHERE ($1 = 0 OR n.user_id = $1)
+                AND ($3 = '' OR EXISTS (SELECT 1 FROM news_tags t WHERE t.news_id = n.id AND t.tag = $3))
+                AND ` + publishedCondition + `
+              ORDER BY n.published_at DESC, n.id DESC
+              LIMIT $2`
+
+	rows, err := repo.reads.Query(query, authorId, limit, dto.NormalizeTag(tag))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return errors.New("news not found")
+	news := []dto.NewsResponse{}
+	for rows.Next() {
+		var n dto.NewsResponse
+		var publishedAt sql.NullString
+		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.AuthorId, &n.AuthorName, &n.CreatedAt, &n.UpdatedAt, &publishedAt); err != nil {
+			return nil, err
+		}
+		n.PublishedAt = publishedAt.String
+		news = append(news, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return news, nil
 }
 
-func NewNewsRepository(db *sql.DB) NewsRepository {
-	return &newsRepository{db: db}
+// NewNewsRepository returns the news of db. Lists are read from reads,
+// which may lag behind db.
+func NewNewsRepository(db *sql.DB, reads database.Querier, changeHooks ...ChangeHook) NewsRepository {
+	return &newsRepository{
+		news:         crud.New[dto.NewsResponse, int](db, newsTable).Replicated(reads),
+		db:           db,
+		reads:        reads,
+		transactions: database.NewTxManager(db),
+		changeHooks:  changeHooks,
+	}
 }
@@ -0,0 +1,186 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestNewsRepository_SQLite(t *testing.T) {
+	db := databasetest.SQLite(t)
+
+	factory := fixtures.NewFactory(1)
+	ammar := factory.User(func(user *fixtures.User) { user.Name = "Ammar" })
+	sholum := factory.User(func(user *fixtures.User) { user.Name = "Sholum" })
+	assert.NoError(t, fixtures.Load(context.Background(), db, &fixtures.Set{Users: []*fixtures.User{ammar, sholum}}))
+
+	var events []string
+	repo := repository.NewNewsRepository(db, db, func(tx *sql.Tx, event string, data any) error {
+		events = append(events, event)
+		return nil
+	})
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	published := &dto.NewsCreateRequest{Title: "Published", Content: "Out now", AuthorId: ammar.ID, PublishedAt: &past,
+		Tags: []string{"Go", "go", " Releases "}}
+	scheduled := &dto.NewsCreateRequest{Title: "Scheduled", Content: "Soon", AuthorId: sholum.ID, PublishedAt: &future}
+	draft := &dto.NewsCreateRequest{Title: "Draft", Content: "Not yet", AuthorId: ammar.ID}
+	for _, news := range []*dto.NewsCreateRequest{published, scheduled, draft} {
+		assert.NoError(t, repo.CreateNews(news))
+	}
+	assert.Equal(t, []int{1, 2, 3}, []int{published.ID, scheduled.ID, draft.ID})
+	// The news scheduled for later is not announced yet.
+	assert.Equal(t, []string{dto.EventCreated, dto.EventPublished, dto.EventCreated, dto.EventCreated}, events)
+
+	_, err := db.Exec(`INSERT INTO news_reactions (news_id, user_id, type) VALUES ($1, 1, 'like'), ($1, 2, 'like'), ($2, 1, 'sad')`,
+		published.ID, scheduled.ID)
+	assert.NoError(t, err)
+
+	t.Run("gets news with reactions", func(t *testing.T) {
+		all, err := repo.GetAllNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, all.Total)
+
+		news := all.News[0]
+		assert.Equal(t, "Ammar", news.AuthorName)
+		assert.Equal(t, dto.ReactionCounts{Like: 2}, news.Reactions)
+		assert.Equal(t, dto.ReactionCounts{Sad: 1}, all.News[1].Reactions)
+		assert.Empty(t, all.News[2].PublishedAt)
+
+		createdAt, err := time.Parse(time.RFC3339Nano, news.CreatedAt)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+		publishedAt, err := time.Parse(time.RFC3339Nano, news.PublishedAt)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, past, publishedAt, time.Millisecond)
+	})
+
+	t.Run("keeps drafts and scheduled news private", func(t *testing.T) {
+		public, err := repo.GetPublishedNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, public.Total)
+		assert.Equal(t, published.ID, public.News[0].ID)
+
+		_, err = repo.GetPublishedNewsById(scheduled.ID)
+		assert.EqualError(t, err, "news not found")
+
+		news, err := repo.GetNewsById(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Draft", news.Title)
+	})
+
+	t.Run("tags news", func(t *testing.T) {
+		news, err := repo.GetNewsById(published.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"go", "releases"}, news.Tags)
+
+		news, err = repo.GetNewsById(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{}, news.Tags)
+
+		tagged, err := repo.GetLatestNews(10, 0, "GO")
+		assert.NoError(t, err)
+		assert.Len(t, tagged, 1)
+		assert.Equal(t, published.ID, tagged[0].ID)
+
+		tagged, err = repo.GetLatestNews(10, 0, "sqlite")
+		assert.NoError(t, err)
+		assert.Empty(t, tagged)
+
+		update := dto.NewsUpdateRequest{Title: published.Title, Content: published.Content, AuthorId: ammar.ID, Tags: []string{"sqlite"}}
+		assert.NoError(t, repo.UpdateNews(published.ID, update))
+
+		tagged, err = repo.GetLatestNews(10, 0, "sqlite")
+		assert.NoError(t, err)
+		assert.Len(t, tagged, 1)
+		tagged, err = repo.GetLatestNews(10, 0, "go")
+		assert.NoError(t, err)
+		assert.Empty(t, tagged)
+
+		// Leaving the tags out keeps them.
+		update.Tags = nil
+		assert.NoError(t, repo.UpdateNews(published.ID, update))
+		news, err = repo.GetNewsById(published.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sqlite"}, news.Tags)
+	})
+
+	t.Run("publishes and unpublishes", func(t *testing.T) {
+		assert.NoError(t, repo.PublishNews(draft.ID, &past))
+
+		latest, err := repo.GetLatestNews(10, 1, "")
+		assert.NoError(t, err)
+		assert.Len(t, latest, 2)
+
+		assert.NoError(t, repo.PublishNews(draft.ID, nil))
+
+		latest, err = repo.GetLatestNews(10, 0, "")
+		assert.NoError(t, err)
+		assert.Len(t, latest, 1)
+
+		assert.EqualError(t, repo.PublishNews(404, nil), "news not found")
+	})
+
+	t.Run("updates and deletes", func(t *testing.T) {
+		err := repo.UpdateNews(published.ID, dto.NewsUpdateRequest{Title: "Updated", Content: "Changed", AuthorId: 2})
+		assert.NoError(t, err)
+
+		news, err := repo.GetNewsById(published.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Updated", news.Title)
+		assert.Equal(t, "Sholum", news.AuthorName)
+
+		assert.NoError(t, repo.DeleteNews(published.ID))
+		assert.EqualError(t, repo.DeleteNews(published.ID), "news not found")
+
+		var reactions int
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(1) FROM news_reactions WHERE news_id = $1`, published.ID).Scan(&reactions))
+		assert.Zero(t, reactions)
+	})
+
+	t.Run("announces scheduled news once public", func(t *testing.T) {
+		events = nil
+		announced, err := repo.AnnounceDueNews()
+		assert.NoError(t, err)
+		assert.Empty(t, announced)
+
+		assert.NoError(t, repo.PublishNews(draft.ID, &future))
+		assert.Equal(t, []string{dto.EventUpdated}, events)
+
+		// The time of the scheduled news passes.
+		_, err = db.Exec(`UPDATE news SET published_at = $1 WHERE id = $2`, past, scheduled.ID)
+		assert.NoError(t, err)
+
+		events = nil
+		announced, err = repo.AnnounceDueNews()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{scheduled.ID}, announced)
+		assert.Equal(t, []string{dto.EventPublished}, events)
+
+		announced, err = repo.AnnounceDueNews()
+		assert.NoError(t, err)
+		assert.Empty(t, announced)
+	})
+
+	t.Run("rolls back when a change hook fails", func(t *testing.T) {
+		failing := repository.NewNewsRepository(db, db, func(tx *sql.Tx, event string, data any) error {
+			return errors.New("hook failed")
+		})
+
+		err := failing.CreateNews(&dto.NewsCreateRequest{Title: "Lost", Content: "Never saved", AuthorId: 1})
+		assert.EqualError(t, err, "hook failed")
+
+		all, err := repo.GetAllNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, all.Total)
+	})
+}
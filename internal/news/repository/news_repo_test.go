@@ -12,17 +12,20 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var newsColumns = []string{"id", "title", "content", "user_id", "author_name", "created_at", "updated_at",
+	"author_avatar_key", "published_at", "like_count", "love_count", "laugh_count", "wow_count", "sad_count", "angry_count", "tags"}
+
 func TestGetAllNews(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewNewsRepository(db)
+	repo := repository.NewNewsRepository(db, db)
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "content", "user_id", "author_name", "created_at", "updated_at"}).
-			AddRow(1, "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now()).
-			AddRow(2, "Title 2", "Content 2", 2, "Author 2", time.Now(), time.Now())
+		rows := sqlmock.NewRows(newsColumns).
+			AddRow(1, "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now(), "", nil, 0, 0, 0, 0, 0, 0, nil).
+			AddRow(2, "Title 2", "Content 2", 2, "Author 2", time.Now(), time.Now(), "", nil, 0, 0, 0, 0, 0, 0, nil)
 
 		mock.ExpectQuery("SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at").
 			WillReturnRows(rows)
@@ -44,8 +47,8 @@ func TestGetAllNews(t *testing.T) {
 	})
 
 	t.Run("scan error", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "content", "user_id", "author_name", "created_at", "updated_at"}).
-			AddRow("invalid", "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now())
+		rows := sqlmock.NewRows(newsColumns).
+			AddRow("invalid", "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now(), "", nil, 0, 0, 0, 0, 0, 0, nil)
 
 		mock.ExpectQuery("SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at").
 			WillReturnRows(rows)
@@ -61,11 +64,11 @@ func TestGetNewsByID(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewNewsRepository(db)
+	repo := repository.NewNewsRepository(db, db)
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "content", "user_id", "author_name", "created_at", "updated_at"}).
-			AddRow(1, "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now())
+		rows := sqlmock.NewRows(newsColumns).
+			AddRow(1, "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now(), "avatars/1/abc", nil, 3, 1, 0, 0, 0, 2, "go,releases")
 
 		mock.ExpectQuery("SELECT n.id, n.title, n.content, n.user_id, u.name AS author_name, n.created_at, n.updated_at").
 			WithArgs(1).
@@ -75,6 +78,10 @@ func TestGetNewsByID(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, 1, result.ID)
+		assert.Equal(t, dto.ReactionCounts{Like: 3, Love: 1, Angry: 2}, result.Reactions)
+		assert.Equal(t, "/api/v1/users/1/avatar?v=abc", result.AuthorAvatarURL)
+		assert.Equal(t, []string{"go", "releases"}, result.Tags)
+		assert.Equal(t, "", result.PublishedAt)
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -104,11 +111,11 @@ func TestCreateNews(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewNewsRepository(db)
+	repo := repository.NewNewsRepository(db, db)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectQuery("INSERT INTO news \\(title, content, user_id\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id").
-			WithArgs("Title 1", "Content 1", 1).
+		mock.ExpectQuery("INSERT INTO news \\(title, content, user_id, published_at, published_event_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id").
+			WithArgs("Title 1", "Content 1", 1, nil, nil).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		req := &dto.NewsCreateRequest{
@@ -121,8 +128,8 @@ func TestCreateNews(t *testing.T) {
 	})
 
 	t.Run("exec error", func(t *testing.T) {
-		mock.ExpectQuery("INSERT INTO news \\(title, content, user_id\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id").
-			WithArgs("Title 1", "Content 1", 1).
+		mock.ExpectQuery("INSERT INTO news \\(title, content, user_id, published_at, published_event_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id").
+			WithArgs("Title 1", "Content 1", 1, nil, nil).
 			WillReturnError(errors.New("exec error"))
 
 		req := &dto.NewsCreateRequest{
@@ -140,7 +147,7 @@ func TestUpdateNews(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewNewsRepository(db)
+	repo := repository.NewNewsRepository(db, db)
 
 	t.Run("success", func(t *testing.T) {
 		mock.ExpectExec("UPDATE news SET title = \\$1, content = \\$2, user_id = \\$3, updated_at = \\$4 WHERE id = \\$5").
@@ -194,7 +201,7 @@ func TestDeleteNews(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewNewsRepository(db)
+	repo := repository.NewNewsRepository(db, db)
 
 	t.Run("success", func(t *testing.T) {
 		mock.ExpectExec("DELETE FROM news WHERE id = \\$1").
@@ -224,3 +231,205 @@ func TestDeleteNews(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetLatestNews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewNewsRepository(db, db)
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "content", "user_id", "author_name", "created_at", "updated_at", "published_at"}).
+			AddRow(2, "Title 2", "Content 2", 7, "Author 7", time.Now(), time.Now(), "2024-01-02T00:00:00Z").
+			AddRow(1, "Title 1", "Content 1", 7, "Author 7", time.Now(), time.Now(), "2024-01-01T00:00:00Z")
+
+		mock.ExpectQuery("WHERE \\(\\$1 = 0 OR n.user_id = \\$1\\)\\s+AND \\(\\$3 = '' OR EXISTS \\(SELECT 1 FROM news_tags t WHERE t.news_id = n.id AND t.tag = \\$3\\)\\)\\s+AND n.published_at IS NOT NULL AND n.published_at <= NOW\\(\\)\\s+ORDER BY n.published_at DESC, n.id DESC\\s+LIMIT \\$2").
+			WithArgs(7, 20, "").
+			WillReturnRows(rows)
+
+		result, err := repo.GetLatestNews(20, 7, "")
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 2, result[0].ID)
+		assert.Equal(t, "2024-01-02T00:00:00Z", result[0].PublishedAt)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery("ORDER BY n.published_at DESC").
+			WithArgs(0, 20, "go").
+			WillReturnError(errors.New("query error"))
+
+		// Tags are matched as they are stored.
+		result, err := repo.GetLatestNews(20, 0, " Go")
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetPublishedNews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewNewsRepository(db, db)
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(newsColumns).
+			AddRow(1, "Title 1", "Content 1", 1, "Author 1", time.Now(), time.Now(), "", "2024-01-01T00:00:00Z", 0, 0, 0, 0, 0, 0, nil)
+
+		mock.ExpectQuery("WHERE n.published_at IS NOT NULL AND n.published_at <= NOW\\(\\)").
+			WillReturnRows(rows)
+
+		result, err := repo.GetPublishedNews()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, "2024-01-01T00:00:00Z", result.News[0].PublishedAt)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery("WHERE n.published_at IS NOT NULL").
+			WillReturnError(errors.New("query error"))
+
+		result, err := repo.GetPublishedNews()
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetPublishedNewsByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewNewsRepository(db, db)
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("WHERE n.id = \\$1 AND n.published_at IS NOT NULL AND n.published_at <= NOW\\(\\)").
+			WithArgs(5).
+			WillReturnError(sql.ErrNoRows)
+
+		result, err := repo.GetPublishedNewsById(5)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "news not found")
+	})
+}
+
+func TestPublishNews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewNewsRepository(db, db)
+	publishedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE news SET published_at = \\$1, published_event_at = \\$2 WHERE id = \\$3").
+			WithArgs(&publishedAt, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.PublishNews(1, &publishedAt)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE news SET published_at = \\$1, published_event_at = \\$2 WHERE id = \\$3").
+			WithArgs(nil, nil, 999).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.PublishNews(999, nil)
+		assert.EqualError(t, err, "news not found")
+	})
+}
+
+func TestChangeHooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var events []string
+	var data []any
+	var hookErr error
+	repo := repository.NewNewsRepository(db, db, func(tx *sql.Tx, event string, eventData any) error {
+		events = append(events, event)
+		data = append(data, eventData)
+		return hookErr
+	})
+
+	t.Run("run in the transaction of the change", func(t *testing.T) {
+		publishedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO news").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery("SELECT n.id, n.title").
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows(newsColumns).
+				AddRow(4, "Title", "Content", 1, "Author", time.Now(), time.Now(), "", publishedAt, 0, 0, 0, 0, 0, 0, nil))
+		mock.ExpectCommit()
+
+		err := repo.CreateNews(&dto.NewsCreateRequest{Title: "Title", Content: "Content", AuthorId: 1, PublishedAt: &publishedAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{dto.EventCreated, dto.EventPublished}, events)
+		assert.Equal(t, 4, data[0].(*dto.NewsResponse).ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("news scheduled for later is not published yet", func(t *testing.T) {
+		events, data = nil, nil
+		publishedAt := time.Now().Add(time.Hour)
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO news").
+			WithArgs("Title", "Content", 1, &publishedAt, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+		mock.ExpectQuery("SELECT n.id, n.title").
+			WithArgs(6).
+			WillReturnRows(sqlmock.NewRows(newsColumns).
+				AddRow(6, "Title", "Content", 1, "Author", time.Now(), time.Now(), "", publishedAt, 0, 0, 0, 0, 0, 0, nil))
+		mock.ExpectCommit()
+
+		err := repo.CreateNews(&dto.NewsCreateRequest{Title: "Title", Content: "Content", AuthorId: 1, PublishedAt: &publishedAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{dto.EventCreated}, events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("deleted news is passed by ID", func(t *testing.T) {
+		events, data = nil, nil
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM news WHERE id = \\$1").
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.DeleteNews(4))
+		assert.Equal(t, []string{dto.EventDeleted}, events)
+		assert.Equal(t, dto.DeletedEvent{ID: 4}, data[0])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not run when the change fails", func(t *testing.T) {
+		events = nil
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE news SET published_at").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, repo.PublishNews(9, nil), "news not found")
+		assert.Empty(t, events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a failing hook rolls the change back", func(t *testing.T) {
+		hookErr = errors.New("hook error")
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM news WHERE id = \\$1").
+			WithArgs(5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, repo.DeleteNews(5), "hook error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
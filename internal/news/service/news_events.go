@@ -0,0 +1,83 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+)
+
+// Publisher is told about changes made through the service, e.g. a
+// pubsub.Hub pushing them to stream clients.
+type Publisher interface {
+	Publish(eventType string, data any)
+}
+
+type publishingNewsService struct {
+	NewsService
+	publisher Publisher
+}
+
+// WithEvents returns service publishing one of the dto.Event* events after
+// every successful change.
+func WithEvents(service NewsService, publisher Publisher) NewsService {
+	return &publishingNewsService{NewsService: service, publisher: publisher}
+}
+
+func (service *publishingNewsService) CreateNews(news *dto.NewsCreateRequest) error {
+	if err := service.NewsService.CreateNews(news); err != nil {
+		return err
+	}
+	service.publishNews(dto.EventCreated, news.ID)
+	return nil
+}
+
+func (service *publishingNewsService) UpdateNews(newsId int, news dto.NewsUpdateRequest) error {
+	if err := service.NewsService.UpdateNews(newsId, news); err != nil {
+		return err
+	}
+	service.publishNews(dto.EventUpdated, newsId)
+	return nil
+}
+
+func (service *publishingNewsService) DeleteNews(id int) error {
+	if err := service.NewsService.DeleteNews(id); err != nil {
+		return err
+	}
+	service.publisher.Publish(dto.EventDeleted, dto.DeletedEvent{ID: id})
+	return nil
+}
+
+// PublishNews sends dto.EventPublished once the news item is public: a news
+// item scheduled for later is only updated.
+func (service *publishingNewsService) PublishNews(id int, publishedAt *time.Time) error {
+	if err := service.NewsService.PublishNews(id, publishedAt); err != nil {
+		return err
+	}
+
+	if publishedAt != nil && publishedAt.After(time.Now()) {
+		service.publishNews(dto.EventUpdated, id)
+	} else {
+		service.publishNews(dto.EventPublished, id)
+	}
+	return nil
+}
+
+func (service *publishingNewsService) UnpublishNews(id int) error {
+	if err := service.NewsService.UnpublishNews(id); err != nil {
+		return err
+	}
+	service.publishNews(dto.EventUnpublished, id)
+	return nil
+}
+
+// publishNews sends the news item as it is now stored. The change itself
+// has succeeded, so a failed read is only logged.
+func (service *publishingNewsService) publishNews(eventType string, id int) {
+	news, err := service.NewsService.GetNewsByID(id)
+	if err != nil {
+		log.Printf("Error loading news %d for the %s event: %v", id, eventType, err)
+		return
+	}
+	service.publisher.Publish(eventType, news)
+}
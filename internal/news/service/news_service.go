@@ -1,8 +1,10 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
 	newsRepo "github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
@@ -11,13 +13,25 @@ import (
 type NewsService interface {
 	GetAllNews() (*dto.NewsListResponse, error)
 	GetNewsByID(id int) (*dto.NewsResponse, error)
+	GetPublishedNews() (*dto.NewsListResponse, error)
+	GetPublishedNewsByID(id int, viewerId int) (*dto.NewsResponse, error)
 	CreateNews(news *dto.NewsCreateRequest) error
 	UpdateNews(newsId int, news dto.NewsUpdateRequest) error
 	DeleteNews(id int) error
+	PublishNews(id int, publishedAt *time.Time) error
+	UnpublishNews(id int) error
 }
 
+// ErrNotFound is returned for a news ID no row has, wrapped or not.
+var ErrNotFound = newsRepo.ErrNotFound
+
+// DeleteHook runs after a news item has been deleted, e.g. to clean up data
+// owned by other modules. Errors are logged and do not fail the delete.
+type DeleteHook func(id int) error
+
 type newsServiceImpl struct {
-	newsRepo newsRepo.NewsRepository
+	newsRepo    newsRepo.NewsRepository
+	deleteHooks []DeleteHook
 }
 
 func (service *newsServiceImpl) GetAllNews() (*dto.NewsListResponse, error) {
@@ -59,6 +73,52 @@ func (service *newsServiceImpl) GetNewsByID(id int) (*dto.NewsResponse, error) {
 	return news, nil
 }
 
+func (service *newsServiceImpl) GetPublishedNews() (*dto.NewsListResponse, error) {
+	log.Println("Fetching published news...")
+	news, err := service.newsRepo.GetPublishedNews()
+
+	if err != nil {
+		log.Printf("Error fetching published news: %v", err)
+		return nil, fmt.Errorf("error getting published news: %w", err)
+	}
+
+	if news == nil || news.News == nil {
+		return &dto.NewsListResponse{
+			News:  []dto.NewsResponse{},
+			Total: 0,
+		}, nil
+	}
+
+	log.Println("Successfully fetched published news")
+	return news, nil
+}
+
+// GetPublishedNewsByID returns a published news item. A draft is only
+// returned when viewerId is its author, so authors can preview it through the
+// public routes; viewerId is 0 for anonymous callers.
+func (service *newsServiceImpl) GetPublishedNewsByID(id int, viewerId int) (*dto.NewsResponse, error) {
+	log.Printf("Fetching published news by ID: %d...", id)
+	news, err := service.newsRepo.GetPublishedNewsById(id)
+
+	if errors.Is(err, ErrNotFound) && viewerId != 0 {
+		draft, draftErr := service.newsRepo.GetNewsById(id)
+		if draftErr == nil && draft.AuthorId == viewerId {
+			return draft, nil
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		log.Printf("Error fetching published news by ID %d: %v", id, err)
+		return nil, fmt.Errorf("error getting published news by ID: %w", err)
+	}
+
+	log.Printf("Successfully fetched published news by ID: %d", id)
+	return news, nil
+}
+
 func (service *newsServiceImpl) CreateNews(news *dto.NewsCreateRequest) error {
 	log.Println("Creating news...")
 	err := service.newsRepo.CreateNews(news)
@@ -94,13 +154,55 @@ func (service *newsServiceImpl) DeleteNews(id int) error {
 		return fmt.Errorf("error deleting news: %w", err)
 	}
 
+	for _, hook := range service.deleteHooks {
+		if err := hook(id); err != nil {
+			log.Printf("Error running delete hook for news with ID %d: %v", id, err)
+		}
+	}
+
 	log.Printf("Successfully deleted news with ID: %d", id)
 	return nil
 }
 
-func NewNewsService(newsRepo newsRepo.NewsRepository) NewsService {
+// PublishNews makes a news item public at publishedAt, or immediately when it
+// is nil.
+func (service *newsServiceImpl) PublishNews(id int, publishedAt *time.Time) error {
+	log.Printf("Publishing news with ID: %d...", id)
+	if publishedAt == nil {
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	if err := service.newsRepo.PublishNews(id, publishedAt); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		log.Printf("Error publishing news with ID %d: %v", id, err)
+		return fmt.Errorf("error publishing news: %w", err)
+	}
+
+	log.Printf("Successfully published news with ID: %d", id)
+	return nil
+}
+
+func (service *newsServiceImpl) UnpublishNews(id int) error {
+	log.Printf("Unpublishing news with ID: %d...", id)
+	if err := service.newsRepo.PublishNews(id, nil); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		log.Printf("Error unpublishing news with ID %d: %v", id, err)
+		return fmt.Errorf("error unpublishing news: %w", err)
+	}
+
+	log.Printf("Successfully unpublished news with ID: %d", id)
+	return nil
+}
+
+func NewNewsService(newsRepo newsRepo.NewsRepository, deleteHooks ...DeleteHook) NewsService {
 	log.Println("Initializing NewsService...")
 	return &newsServiceImpl{
-		newsRepo: newsRepo,
+		newsRepo:    newsRepo,
+		deleteHooks: deleteHooks,
 	}
 }
@@ -3,18 +3,26 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/news/repository"
 	"github.com/ahmadammarm/go-rest-api-template/internal/news/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
 )
 
 type MockNewsRepository struct {
 	mock.Mock
 }
 
+// WithTx returns the mock itself, which expects the calls made in tx.
+func (m *MockNewsRepository) WithTx(tx *database.Tx) repository.NewsRepository {
+	return m
+}
+
 func (m *MockNewsRepository) GetAllNews() (*dto.NewsListResponse, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -46,6 +54,40 @@ func (m *MockNewsRepository) DeleteNews(id int) error {
 	return args.Error(0)
 }
 
+func (m *MockNewsRepository) GetPublishedNews() (*dto.NewsListResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NewsListResponse), args.Error(1)
+}
+
+func (m *MockNewsRepository) GetPublishedNewsById(id int) (*dto.NewsResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NewsResponse), args.Error(1)
+}
+
+func (m *MockNewsRepository) PublishNews(id int, publishedAt *time.Time) error {
+	args := m.Called(id, publishedAt)
+	return args.Error(0)
+}
+
+func (m *MockNewsRepository) AnnounceDueNews() ([]int, error) {
+	args := m.Called()
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockNewsRepository) GetLatestNews(limit int, authorId int, tag string) ([]dto.NewsResponse, error) {
+	args := m.Called(limit, authorId, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.NewsResponse), args.Error(1)
+}
+
 func TestGetAllNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
 	newsService := service.NewNewsService(mockRepo)
@@ -158,7 +200,7 @@ func TestGetNewsByID(t *testing.T) {
 
 	t.Run("news not found", func(t *testing.T) {
 		newsID := 999
-		notFoundErr := errors.New("news not found")
+		notFoundErr := repository.ErrNotFound
 
 		mockRepo.On("GetNewsById", newsID).Return(nil, notFoundErr).Once()
 
@@ -315,3 +357,179 @@ func TestDeleteNews(t *testing.T) {
     })
 }
 
+
+func TestDeleteNewsRunsDeleteHooks(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+
+	var hookedIDs []int
+	hook := func(id int) error {
+		hookedIDs = append(hookedIDs, id)
+		return errors.New("hook error")
+	}
+	newsService := service.NewNewsService(mockRepo, hook)
+
+	t.Run("runs after delete and ignores hook errors", func(t *testing.T) {
+		mockRepo.On("DeleteNews", 3).Return(nil).Once()
+
+		err := newsService.DeleteNews(3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3}, hookedIDs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("skipped when delete fails", func(t *testing.T) {
+		mockRepo.On("DeleteNews", 4).Return(repository.ErrNotFound).Once()
+
+		err := newsService.DeleteNews(4)
+
+		assert.Error(t, err)
+		assert.Equal(t, []int{3}, hookedIDs)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetPublishedNewsByID(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	newsService := service.NewNewsService(mockRepo)
+
+	draft := &dto.NewsResponse{ID: 5, Title: "Draft", AuthorId: 2}
+
+	t.Run("published", func(t *testing.T) {
+		published := &dto.NewsResponse{ID: 1, Title: "Published", AuthorId: 2, PublishedAt: "2024-01-01T00:00:00Z"}
+		mockRepo.On("GetPublishedNewsById", 1).Return(published, nil).Once()
+
+		news, err := newsService.GetPublishedNewsByID(1, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, published, news)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("draft hidden from anonymous callers", func(t *testing.T) {
+		mockRepo.On("GetPublishedNewsById", 5).Return(nil, repository.ErrNotFound).Once()
+
+		news, err := newsService.GetPublishedNewsByID(5, 0)
+
+		assert.Nil(t, news)
+		assert.EqualError(t, err, "news not found")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("draft hidden from other users", func(t *testing.T) {
+		mockRepo.On("GetPublishedNewsById", 5).Return(nil, repository.ErrNotFound).Once()
+		mockRepo.On("GetNewsById", 5).Return(draft, nil).Once()
+
+		news, err := newsService.GetPublishedNewsByID(5, 3)
+
+		assert.Nil(t, news)
+		assert.EqualError(t, err, "news not found")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("draft visible to its author", func(t *testing.T) {
+		mockRepo.On("GetPublishedNewsById", 5).Return(nil, repository.ErrNotFound).Once()
+		mockRepo.On("GetNewsById", 5).Return(draft, nil).Once()
+
+		news, err := newsService.GetPublishedNewsByID(5, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, draft, news)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPublishNews(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	newsService := service.NewNewsService(mockRepo)
+
+	t.Run("defaults to now", func(t *testing.T) {
+		mockRepo.On("PublishNews", 1, mock.MatchedBy(func(at *time.Time) bool {
+			return at != nil && time.Since(*at) < time.Minute
+		})).Return(nil).Once()
+
+		err := newsService.PublishNews(1, nil)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unpublish", func(t *testing.T) {
+		mockRepo.On("PublishNews", 1, (*time.Time)(nil)).Return(nil).Once()
+
+		err := newsService.UnpublishNews(1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockRepo.On("PublishNews", 9, &at).Return(repository.ErrNotFound).Once()
+
+		err := newsService.PublishNews(9, &at)
+
+		assert.EqualError(t, err, "news not found")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+type recordingPublisher struct {
+	types []string
+	data  []any
+}
+
+func (publisher *recordingPublisher) Publish(eventType string, data any) {
+	publisher.types = append(publisher.types, eventType)
+	publisher.data = append(publisher.data, data)
+}
+
+func TestWithEvents(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	publisher := &recordingPublisher{}
+	newsService := service.WithEvents(service.NewNewsService(mockRepo), publisher)
+
+	t.Run("publishes the stored news after a change", func(t *testing.T) {
+		stored := &dto.NewsResponse{ID: 5, Title: "Stored"}
+		mockRepo.On("UpdateNews", 5, mock.Anything).Return(nil).Once()
+		mockRepo.On("GetNewsById", 5).Return(stored, nil).Once()
+
+		err := newsService.UpdateNews(5, dto.NewsUpdateRequest{Title: "Stored"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{dto.EventUpdated}, publisher.types)
+		assert.Equal(t, stored, publisher.data[0])
+	})
+
+	t.Run("publishes the ID of deleted news", func(t *testing.T) {
+		mockRepo.On("DeleteNews", 6).Return(nil).Once()
+
+		err := newsService.DeleteNews(6)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.EventDeleted, publisher.types[1])
+		assert.Equal(t, dto.DeletedEvent{ID: 6}, publisher.data[1])
+	})
+
+	t.Run("publishes nothing when the change fails", func(t *testing.T) {
+		mockRepo.On("PublishNews", 7, mock.Anything).Return(repository.ErrNotFound).Once()
+
+		err := newsService.PublishNews(7, nil)
+
+		assert.Error(t, err)
+		assert.Len(t, publisher.types, 2)
+	})
+
+	t.Run("news scheduled for later is updated, not published", func(t *testing.T) {
+		later := time.Now().Add(time.Hour)
+		mockRepo.On("PublishNews", 8, &later).Return(nil).Once()
+		mockRepo.On("GetNewsById", 8).Return(&dto.NewsResponse{ID: 8}, nil).Once()
+
+		err := newsService.PublishNews(8, &later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.EventUpdated, publisher.types[2])
+	})
+
+	mockRepo.AssertExpectations(t)
+}
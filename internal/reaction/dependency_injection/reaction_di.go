@@ -0,0 +1,17 @@
+package dependency_injection
+
+import (
+	"database/sql"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/handler"
+	reactionRepository "github.com/ahmadammarm/go-rest-api-template/internal/reaction/repository"
+	reactionService "github.com/ahmadammarm/go-rest-api-template/internal/reaction/service"
+	"github.com/go-playground/validator/v10"
+)
+
+func InitializeReaction(db *sql.DB, validator *validator.Validate) *handler.ReactionHandler {
+	reactionRepo := reactionRepository.NewReactionRepository(db)
+	reactionService := reactionService.NewReactionService(reactionRepo)
+
+	return handler.NewReactionHandler(reactionService, validator)
+}
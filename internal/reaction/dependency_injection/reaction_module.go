@@ -0,0 +1,29 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the reactions to news and the bookmarks.
+type Module struct {
+	app.Base
+	handler *handler.ReactionHandler
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "reaction"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	module.handler = InitializeReaction(c.DB, c.Validator)
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Root.Add(module.handler.ReactionRouters, module.handler.ReactionOperations())
+}
@@ -0,0 +1,25 @@
+package dto
+
+// Request body
+type ReactionRequest struct {
+	Type string `json:"type" validate:"required,oneof=like love laugh wow sad angry"`
+}
+
+// Response body
+type ReactionResponse struct {
+	NewsId int    `json:"news_id"`
+	Type   string `json:"type"`
+}
+
+type BookmarkResponse struct {
+	NewsId       int    `json:"news_id"`
+	Title        string `json:"title"`
+	AuthorId     int    `json:"user_id"`
+	AuthorName   string `json:"author_name"`
+	BookmarkedAt string `json:"bookmarked_at"`
+}
+
+type BookmarkListResponse struct {
+	Bookmarks []BookmarkResponse `json:"bookmarks"`
+	Total     int                `json:"total"`
+}
@@ -0,0 +1,32 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+	"github.com/go-playground/validator/v10"
+)
+
+func TestReactionRequestValidation(t *testing.T) {
+	validate := validator.New()
+
+	tests := []struct {
+		name    string
+		request dto.ReactionRequest
+		wantErr bool
+	}{
+		{name: "Like", request: dto.ReactionRequest{Type: "like"}, wantErr: false},
+		{name: "Angry", request: dto.ReactionRequest{Type: "angry"}, wantErr: false},
+		{name: "Missing type", request: dto.ReactionRequest{}, wantErr: true},
+		{name: "Unknown type", request: dto.ReactionRequest{Type: "dislike"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.Struct(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validation error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+	reactionService "github.com/ahmadammarm/go-rest-api-template/internal/reaction/service"
+	formvalidation "github.com/ahmadammarm/go-rest-api-template/pkg/form-validation"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReactionHandler struct {
+	reactionService reactionService.ReactionService
+	validation      *validator.Validate
+}
+
+func (handler *ReactionHandler) React(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for reaction:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	var request dto.ReactionRequest
+	if err := context.BodyParser(&request); err != nil {
+		log.Println("Error parsing request body for reaction:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	if err := handler.validation.Struct(request); err != nil {
+		return response.JSONResponse(context, 422, "Validation Error", formvalidation.FormValidationError(err))
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	reaction, err := handler.reactionService.React(newsId, userId, request)
+	if err != nil {
+		if err.Error() == "news not found" {
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		}
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", reaction)
+}
+
+func (handler *ReactionHandler) Unreact(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for reaction removal:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	if err := handler.reactionService.Unreact(newsId, userId); err != nil {
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", nil)
+}
+
+func (handler *ReactionHandler) Bookmark(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for bookmark:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	if err := handler.reactionService.Bookmark(newsId, userId); err != nil {
+		if err.Error() == "news not found" {
+			return response.JSONResponse(context, 404, "Not Found", nil)
+		}
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", nil)
+}
+
+func (handler *ReactionHandler) Unbookmark(context *fiber.Ctx) error {
+	newsId, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		log.Println("Error parsing news ID for bookmark removal:", err)
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	if err := handler.reactionService.Unbookmark(newsId, userId); err != nil {
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", nil)
+}
+
+func (handler *ReactionHandler) GetBookmarks(context *fiber.Ctx) error {
+	userId, ok := context.Locals("user_id").(int)
+	if !ok {
+		return response.JSONResponse(context, 401, "Unauthorized", nil)
+	}
+
+	bookmarks, err := handler.reactionService.GetBookmarks(userId)
+	if err != nil {
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", bookmarks)
+}
+
+// ReactionRouters registers reaction and bookmark routes. PUT and DELETE are
+// used so that repeated requests leave the same state behind.
+func (handler *ReactionHandler) ReactionRouters(router fiber.Router) {
+	router.Put("/news/:id/reaction", middleware.JWTAuth(), handler.React)
+	router.Delete("/news/:id/reaction", middleware.JWTAuth(), handler.Unreact)
+	router.Put("/news/:id/bookmark", middleware.JWTAuth(), handler.Bookmark)
+	router.Delete("/news/:id/bookmark", middleware.JWTAuth(), handler.Unbookmark)
+	router.Get("/users/me/bookmarks", middleware.JWTAuth(), handler.GetBookmarks)
+}
+
+func NewReactionHandler(reactionService reactionService.ReactionService, validation *validator.Validate) *ReactionHandler {
+	return &ReactionHandler{
+		reactionService: reactionService,
+		validation:      validation,
+	}
+}
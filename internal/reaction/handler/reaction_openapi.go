@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var reactionTags = []string{"Reactions & Bookmarks"}
+
+// ReactionOperations documents the routes of ReactionRouters.
+func (handler *ReactionHandler) ReactionOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "PUT", Path: "/news/:id/reaction", Summary: "React to a news", Tags: reactionTags, Security: openapi.BearerAuth,
+			Request: dto.ReactionRequest{}, Response: dto.ReactionResponse{}, Errors: []int{400, 404, 422, 500}},
+		{Method: "DELETE", Path: "/news/:id/reaction", Summary: "Remove your reaction", Tags: reactionTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 500}},
+		{Method: "PUT", Path: "/news/:id/bookmark", Summary: "Bookmark a news", Tags: reactionTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 404, 500}},
+		{Method: "DELETE", Path: "/news/:id/bookmark", Summary: "Remove a bookmark", Tags: reactionTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 500}},
+		{Method: "GET", Path: "/users/me/bookmarks", Summary: "Get your bookmarked news", Tags: reactionTags, Security: openapi.BearerAuth,
+			Response: dto.BookmarkListResponse{}, Errors: []int{500}},
+	}
+}
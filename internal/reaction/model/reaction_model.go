@@ -0,0 +1,23 @@
+package model
+
+const (
+	ReactionLike  = "like"
+	ReactionLove  = "love"
+	ReactionLaugh = "laugh"
+	ReactionWow   = "wow"
+	ReactionSad   = "sad"
+	ReactionAngry = "angry"
+)
+
+type Reaction struct {
+	NewsId    int    `json:"news_id"`
+	UserId    int    `json:"user_id"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Bookmark struct {
+	NewsId    int    `json:"news_id"`
+	UserId    int    `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
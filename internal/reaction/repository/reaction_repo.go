@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+)
+
+type ReactionRepository interface {
+	IsNewsExists(newsId int) (bool, error)
+	UpsertReaction(newsId int, userId int, reactionType string) error
+	DeleteReaction(newsId int, userId int) error
+	AddBookmark(newsId int, userId int) error
+	RemoveBookmark(newsId int, userId int) error
+	GetBookmarksByUser(userId int) (*dto.BookmarkListResponse, error)
+}
+
+type reactionRepository struct {
+	db *sql.DB
+}
+
+func (repo *reactionRepository) IsNewsExists(newsId int) (bool, error) {
+	query := `SELECT COUNT(1) FROM news WHERE id = $1`
+
+	var count int
+	if err := repo.db.QueryRow(query, newsId).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpsertReaction stores the reaction of a user on a news item. A user has at
+// most one reaction per news item, so reacting again replaces the type and
+// repeating the same request leaves the row unchanged.
+func (repo *reactionRepository) UpsertReaction(newsId int, userId int, reactionType string) error {
+	query := `INSERT INTO news_reactions (news_id, user_id, type) VALUES ($1, $2, $3)
+              ON CONFLICT (news_id, user_id) DO UPDATE SET type = EXCLUDED.type`
+
+	_, err := repo.db.Exec(query, newsId, userId, reactionType)
+
+	return err
+}
+
+func (repo *reactionRepository) DeleteReaction(newsId int, userId int) error {
+	query := `DELETE FROM news_reactions WHERE news_id = $1 AND user_id = $2`
+
+	_, err := repo.db.Exec(query, newsId, userId)
+
+	return err
+}
+
+func (repo *reactionRepository) AddBookmark(newsId int, userId int) error {
+	query := `INSERT INTO news_bookmarks (news_id, user_id) VALUES ($1, $2)
+              ON CONFLICT (news_id, user_id) DO NOTHING`
+
+	_, err := repo.db.Exec(query, newsId, userId)
+
+	return err
+}
+
+func (repo *reactionRepository) RemoveBookmark(newsId int, userId int) error {
+	query := `DELETE FROM news_bookmarks WHERE news_id = $1 AND user_id = $2`
+
+	_, err := repo.db.Exec(query, newsId, userId)
+
+	return err
+}
+
+func (repo *reactionRepository) GetBookmarksByUser(userId int) (*dto.BookmarkListResponse, error) {
+	query := `SELECT n.id, n.title, n.user_id, u.name AS author_name, b.created_at
+              FROM news_bookmarks b
+              JOIN news n ON b.news_id = n.id
+              JOIN users u ON n.user_id = u.id
+              WHERE b.user_id = $1
+              ORDER BY b.created_at DESC`
+
+	rows, err := repo.db.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookmarks := []dto.BookmarkResponse{}
+	for rows.Next() {
+		var b dto.BookmarkResponse
+		if err := rows.Scan(&b.NewsId, &b.Title, &b.AuthorId, &b.AuthorName, &b.BookmarkedAt); err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &dto.BookmarkListResponse{
+		Bookmarks: bookmarks,
+		Total:     len(bookmarks),
+	}, nil
+}
+
+func NewReactionRepository(db *sql.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/fixtures"
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestReactionRepository_SQLite(t *testing.T) {
+	db := databasetest.SQLite(t)
+
+	factory := fixtures.NewFactory(1)
+	author := factory.User(func(user *fixtures.User) { user.Name = "Ammar" })
+	set := &fixtures.Set{News: []*fixtures.News{factory.News(author, fixtures.Draft), factory.News(author, fixtures.Draft)}}
+	assert.NoError(t, fixtures.Load(context.Background(), db, set))
+
+	repo := repository.NewReactionRepository(db)
+
+	exists, err := repo.IsNewsExists(2)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	t.Run("replaces the reaction of a user", func(t *testing.T) {
+		assert.NoError(t, repo.UpsertReaction(1, 1, "like"))
+		assert.NoError(t, repo.UpsertReaction(1, 1, "love"))
+
+		var reaction string
+		assert.NoError(t, db.QueryRow(`SELECT type FROM news_reactions WHERE news_id = $1 AND user_id = $2`, 1, 1).Scan(&reaction))
+		assert.Equal(t, "love", reaction)
+
+		assert.ErrorContains(t, repo.UpsertReaction(1, 1, "meh"), "CHECK constraint failed")
+
+		assert.NoError(t, repo.DeleteReaction(1, 1))
+		assert.NoError(t, repo.DeleteReaction(1, 1))
+	})
+
+	t.Run("bookmarks once", func(t *testing.T) {
+		assert.NoError(t, repo.AddBookmark(1, 1))
+		assert.NoError(t, repo.AddBookmark(1, 1))
+		assert.NoError(t, repo.AddBookmark(2, 1))
+		assert.Error(t, repo.AddBookmark(404, 1))
+
+		bookmarks, err := repo.GetBookmarksByUser(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, bookmarks.Total)
+		assert.Equal(t, "Ammar", bookmarks.Bookmarks[0].AuthorName)
+		assert.NotEmpty(t, bookmarks.Bookmarks[0].BookmarkedAt)
+
+		assert.NoError(t, repo.RemoveBookmark(1, 1))
+
+		bookmarks, err = repo.GetBookmarksByUser(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, bookmarks.Total)
+		assert.Equal(t, 2, bookmarks.Bookmarks[0].NewsId)
+	})
+}
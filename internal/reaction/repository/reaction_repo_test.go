@@ -0,0 +1,152 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNewsExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReactionRepository(db)
+
+	t.Run("exists", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(1\\) FROM news WHERE id = \\$1").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		exists, err := repo.IsNewsExists(1)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(1\\) FROM news WHERE id = \\$1").
+			WithArgs(999).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		exists, err := repo.IsNewsExists(999)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestUpsertReaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReactionRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO news_reactions \\(news_id, user_id, type\\) VALUES \\(\\$1, \\$2, \\$3\\)\\s+ON CONFLICT \\(news_id, user_id\\) DO UPDATE").
+			WithArgs(1, 7, "like").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpsertReaction(1, 7, "like")
+		assert.NoError(t, err)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO news_reactions").
+			WithArgs(1, 7, "like").
+			WillReturnError(errors.New("exec error"))
+
+		err := repo.UpsertReaction(1, 7, "like")
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteReaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReactionRepository(db)
+
+	t.Run("nothing to delete is not an error", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM news_reactions WHERE news_id = \\$1 AND user_id = \\$2").
+			WithArgs(1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.DeleteReaction(1, 7)
+		assert.NoError(t, err)
+	})
+}
+
+func TestAddBookmark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReactionRepository(db)
+
+	t.Run("duplicate is ignored", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO news_bookmarks \\(news_id, user_id\\) VALUES \\(\\$1, \\$2\\)\\s+ON CONFLICT \\(news_id, user_id\\) DO NOTHING").
+			WithArgs(1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.AddBookmark(1, 7)
+		assert.NoError(t, err)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO news_bookmarks").
+			WithArgs(1, 7).
+			WillReturnError(errors.New("exec error"))
+
+		err := repo.AddBookmark(1, 7)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetBookmarksByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReactionRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "user_id", "author_name", "created_at"}).
+			AddRow(1, "Title 1", 2, "Author 2", time.Now()).
+			AddRow(3, "Title 3", 2, "Author 2", time.Now())
+
+		mock.ExpectQuery("SELECT n.id, n.title, n.user_id, u.name AS author_name, b.created_at").
+			WithArgs(7).
+			WillReturnRows(rows)
+
+		result, err := repo.GetBookmarksByUser(7)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, 3, result.Bookmarks[1].NewsId)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mock.ExpectQuery("SELECT n.id, n.title, n.user_id, u.name AS author_name, b.created_at").
+			WithArgs(8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "user_id", "author_name", "created_at"}))
+
+		result, err := repo.GetBookmarksByUser(8)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.NotNil(t, result.Bookmarks)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT n.id, n.title, n.user_id, u.name AS author_name, b.created_at").
+			WithArgs(7).
+			WillReturnError(errors.New("query error"))
+
+		result, err := repo.GetBookmarksByUser(7)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
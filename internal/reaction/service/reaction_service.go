@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+	reactionRepo "github.com/ahmadammarm/go-rest-api-template/internal/reaction/repository"
+)
+
+type ReactionService interface {
+	React(newsId int, userId int, request dto.ReactionRequest) (*dto.ReactionResponse, error)
+	Unreact(newsId int, userId int) error
+	Bookmark(newsId int, userId int) error
+	Unbookmark(newsId int, userId int) error
+	GetBookmarks(userId int) (*dto.BookmarkListResponse, error)
+}
+
+type reactionServiceImpl struct {
+	reactionRepo reactionRepo.ReactionRepository
+}
+
+func (service *reactionServiceImpl) ensureNewsExists(newsId int) error {
+	exists, err := service.reactionRepo.IsNewsExists(newsId)
+	if err != nil {
+		return fmt.Errorf("error checking news: %w", err)
+	}
+
+	if !exists {
+		return errors.New("news not found")
+	}
+
+	return nil
+}
+
+func (service *reactionServiceImpl) React(newsId int, userId int, request dto.ReactionRequest) (*dto.ReactionResponse, error) {
+	log.Printf("User %d reacting %q to news %d...", userId, request.Type, newsId)
+	if err := service.ensureNewsExists(newsId); err != nil {
+		return nil, err
+	}
+
+	if err := service.reactionRepo.UpsertReaction(newsId, userId, request.Type); err != nil {
+		log.Printf("Error reacting to news %d: %v", newsId, err)
+		return nil, fmt.Errorf("error reacting to news: %w", err)
+	}
+
+	return &dto.ReactionResponse{NewsId: newsId, Type: request.Type}, nil
+}
+
+func (service *reactionServiceImpl) Unreact(newsId int, userId int) error {
+	log.Printf("User %d removing reaction from news %d...", userId, newsId)
+	if err := service.reactionRepo.DeleteReaction(newsId, userId); err != nil {
+		log.Printf("Error removing reaction from news %d: %v", newsId, err)
+		return fmt.Errorf("error removing reaction: %w", err)
+	}
+
+	return nil
+}
+
+func (service *reactionServiceImpl) Bookmark(newsId int, userId int) error {
+	log.Printf("User %d bookmarking news %d...", userId, newsId)
+	if err := service.ensureNewsExists(newsId); err != nil {
+		return err
+	}
+
+	if err := service.reactionRepo.AddBookmark(newsId, userId); err != nil {
+		log.Printf("Error bookmarking news %d: %v", newsId, err)
+		return fmt.Errorf("error bookmarking news: %w", err)
+	}
+
+	return nil
+}
+
+func (service *reactionServiceImpl) Unbookmark(newsId int, userId int) error {
+	log.Printf("User %d removing bookmark from news %d...", userId, newsId)
+	if err := service.reactionRepo.RemoveBookmark(newsId, userId); err != nil {
+		log.Printf("Error removing bookmark from news %d: %v", newsId, err)
+		return fmt.Errorf("error removing bookmark: %w", err)
+	}
+
+	return nil
+}
+
+func (service *reactionServiceImpl) GetBookmarks(userId int) (*dto.BookmarkListResponse, error) {
+	log.Printf("Fetching bookmarks for user %d...", userId)
+	bookmarks, err := service.reactionRepo.GetBookmarksByUser(userId)
+	if err != nil {
+		log.Printf("Error fetching bookmarks for user %d: %v", userId, err)
+		return nil, fmt.Errorf("error getting bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+func NewReactionService(reactionRepo reactionRepo.ReactionRepository) ReactionService {
+	log.Println("Initializing ReactionService...")
+	return &reactionServiceImpl{
+		reactionRepo: reactionRepo,
+	}
+}
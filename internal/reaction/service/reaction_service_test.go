@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/reaction/service"
+)
+
+type MockReactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReactionRepository) IsNewsExists(newsId int) (bool, error) {
+	args := m.Called(newsId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReactionRepository) UpsertReaction(newsId int, userId int, reactionType string) error {
+	args := m.Called(newsId, userId, reactionType)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) DeleteReaction(newsId int, userId int) error {
+	args := m.Called(newsId, userId)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) AddBookmark(newsId int, userId int) error {
+	args := m.Called(newsId, userId)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) RemoveBookmark(newsId int, userId int) error {
+	args := m.Called(newsId, userId)
+	return args.Error(0)
+}
+
+func (m *MockReactionRepository) GetBookmarksByUser(userId int) (*dto.BookmarkListResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BookmarkListResponse), args.Error(1)
+}
+
+func TestReact(t *testing.T) {
+	mockRepo := new(MockReactionRepository)
+	reactionService := service.NewReactionService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.On("IsNewsExists", 1).Return(true, nil).Once()
+		mockRepo.On("UpsertReaction", 1, 7, "love").Return(nil).Once()
+
+		result, err := reactionService.React(1, 7, dto.ReactionRequest{Type: "love"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.ReactionResponse{NewsId: 1, Type: "love"}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("news not found", func(t *testing.T) {
+		mockRepo.On("IsNewsExists", 999).Return(false, nil).Once()
+
+		result, err := reactionService.React(999, 7, dto.ReactionRequest{Type: "like"})
+
+		assert.Error(t, err)
+		assert.Equal(t, "news not found", err.Error())
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.On("IsNewsExists", 1).Return(true, nil).Once()
+		mockRepo.On("UpsertReaction", 1, 7, "like").Return(errors.New("database error")).Once()
+
+		result, err := reactionService.React(1, 7, dto.ReactionRequest{Type: "like"})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error reacting to news")
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBookmark(t *testing.T) {
+	mockRepo := new(MockReactionRepository)
+	reactionService := service.NewReactionService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.On("IsNewsExists", 1).Return(true, nil).Once()
+		mockRepo.On("AddBookmark", 1, 7).Return(nil).Once()
+
+		err := reactionService.Bookmark(1, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("news not found", func(t *testing.T) {
+		mockRepo.On("IsNewsExists", 999).Return(false, nil).Once()
+
+		err := reactionService.Bookmark(999, 7)
+
+		assert.Error(t, err)
+		assert.Equal(t, "news not found", err.Error())
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUnbookmark(t *testing.T) {
+	mockRepo := new(MockReactionRepository)
+	reactionService := service.NewReactionService(mockRepo)
+
+	mockRepo.On("RemoveBookmark", 1, 7).Return(nil).Once()
+
+	err := reactionService.Unbookmark(1, 7)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetBookmarks(t *testing.T) {
+	mockRepo := new(MockReactionRepository)
+	reactionService := service.NewReactionService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		expected := &dto.BookmarkListResponse{
+			Bookmarks: []dto.BookmarkResponse{{NewsId: 1, Title: "Title 1"}},
+			Total:     1,
+		}
+		mockRepo.On("GetBookmarksByUser", 7).Return(expected, nil).Once()
+
+		result, err := reactionService.GetBookmarks(7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.On("GetBookmarksByUser", 7).Return(nil, errors.New("database error")).Once()
+
+		result, err := reactionService.GetBookmarks(7)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,10 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/task/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+)
+
+func InitializeTask(scheduler *scheduler.Scheduler) *handler.TaskHandler {
+	return handler.NewTaskHandler(scheduler)
+}
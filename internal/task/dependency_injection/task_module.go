@@ -0,0 +1,54 @@
+package dependency_injection
+
+import (
+	"context"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/task/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+)
+
+// Module manages the scheduled tasks and runs them when due. Its table is
+// Postgres only: on SQLite, it does nothing.
+type Module struct {
+	app.Base
+	handler   *handler.TaskHandler
+	scheduler *scheduler.Scheduler
+	runner    app.Runner
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "task"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	if !c.Config.Postgres() {
+		return nil
+	}
+
+	module.handler = InitializeTask(c.Scheduler)
+	module.scheduler = c.Scheduler
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	if module.handler == nil {
+		return
+	}
+	routes.Admin.Add(module.handler.TaskRouters, module.handler.TaskOperations())
+}
+
+func (module *Module) Start(ctx context.Context) error {
+	if module.scheduler != nil {
+		module.runner.Start(ctx, module.scheduler.Run)
+	}
+	return nil
+}
+
+func (module *Module) Stop(ctx context.Context) error {
+	return module.runner.Stop(ctx)
+}
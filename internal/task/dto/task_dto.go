@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+
+type TaskListResponse struct {
+	Tasks []scheduler.Status `json:"tasks"`
+	Total int                `json:"total"`
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"log"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/task/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaskHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+func (handler *TaskHandler) GetTasks(context *fiber.Ctx) error {
+	tasks, err := handler.scheduler.Statuses(context.Context())
+	if err != nil {
+		log.Println("Error fetching scheduled tasks:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Success", dto.TaskListResponse{Tasks: tasks, Total: len(tasks)})
+}
+
+// TaskRouters registers the scheduled task admin routes. They are meant for a
+// group using JWTAuth and RequireAdmin.
+func (handler *TaskHandler) TaskRouters(router fiber.Router) {
+	router.Get("/tasks", handler.GetTasks)
+}
+
+func NewTaskHandler(scheduler *scheduler.Scheduler) *TaskHandler {
+	return &TaskHandler{scheduler: scheduler}
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/task/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var taskTags = []string{"Tasks"}
+
+// TaskOperations documents the routes of TaskRouters.
+func (handler *TaskHandler) TaskOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/tasks", Summary: "Get the scheduled tasks with their last run", Tags: taskTags, Security: openapi.BearerAuth,
+			Response: dto.TaskListResponse{}, Errors: []int{403, 500}},
+	}
+}
@@ -6,13 +6,28 @@ import (
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/handler"
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
 	"github.com/go-playground/validator/v10"
 )
 
-func InitializeUser(db *sql.DB, validator *validator.Validate) *handler.UserHandler {
-    userRepository := repository.NewUserRepository(db)
-    userService := service.NewUserService(userRepository)
+// Hooks connect other modules to user changes, whichever API they are made
+// through.
+type Hooks struct {
+	// Change hooks run in the transaction of each change.
+	Change []repository.ChangeHook
+}
+
+// NewUserService builds the user service used by every API.
+func NewUserService(db *sql.DB, reads database.Querier, avatarStorage storage.Storage, hooks Hooks) service.UserService {
+    userRepository := repository.NewUserRepository(db, reads, hooks.Change...)
+
+    return service.NewUserService(userRepository, avatarStorage, database.NewTxManager(db))
+}
+
+func InitializeUser(db *sql.DB, reads database.Querier, validator *validator.Validate, avatarStorage storage.Storage, hooks Hooks) *handler.UserHandler {
+    userService := NewUserService(db, reads, avatarStorage, hooks)
     userHandler := handler.NewUserHandler(userService, validator)
 
     return userHandler
-}
\ No newline at end of file
+}
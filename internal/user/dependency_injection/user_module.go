@@ -0,0 +1,31 @@
+package dependency_injection
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/handler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module serves the users and their sign-in.
+type Module struct {
+	app.Base
+	// Hooks are shared with the other APIs serving users.
+	Hooks   Hooks
+	handler *handler.UserHandler
+}
+
+func NewModule(hooks Hooks) *Module {
+	return &Module{Hooks: hooks}
+}
+
+func (module *Module) Name() string {
+	return "user"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	module.handler = InitializeUser(c.DB, c.Reads, c.Validator, c.Store, module.Hooks)
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	routes.Root.Add(module.handler.UserRouters, module.handler.UserOperations())
+}
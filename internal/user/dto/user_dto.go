@@ -23,21 +23,46 @@ type UserUpdateRequest struct {
 	Password string `json:"password,omitempty" validate:"omitempty,min=6,max=20"`
 }
 
+// UserProfileRequest changes the fields it sets and keeps the others. An
+// empty string clears a field.
+type UserProfileRequest struct {
+	Bio      *string `json:"bio" validate:"omitnil,max=500"`
+	Website  *string `json:"website" validate:"omitnil,max=255,eq=|url"`
+	Locale   *string `json:"locale" validate:"omitnil,eq=|bcp47_language_tag"`
+	Timezone *string `json:"timezone" validate:"omitnil,eq=|timezone"`
+}
+
 // Response
 type UserJWTResponse struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	IsAdmin bool   `json:"is_admin"`
+	Token   string `json:"token"`
 }
 
 type UserResponse struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Bio       string `json:"bio,omitempty"`
+	AvatarURL string `json:"avatar_url"`
+	Website   string `json:"website,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
 }
 
 type UserListResponse struct {
 	Users []UserResponse `json:"users"`
 	Total int            `json:"total"`
 }
+
+// EventRegistered is written to the outbox when an user registers, with a
+// RegisteredEvent.
+const EventRegistered = "user.registered"
+
+type RegisteredEvent struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
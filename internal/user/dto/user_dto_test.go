@@ -1,9 +1,11 @@
 package dto_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/go-playground/validator/v10"
 )
 
 func TestUserRegisterRequest(t *testing.T) {
@@ -123,149 +125,189 @@ func TestUserUpdateRequest(t *testing.T) {
 }
 
 func TestUserJWTResponse(t *testing.T) {
-    tests := []struct {
-        name     string
-        input    dto.UserJWTResponse
-        expected dto.UserJWTResponse
-    }{
-        {
-            name: "Valid Input",
-            input: dto.UserJWTResponse{
-                ID:       1,
-                Name:     "Test User",
-                Email:    "testuser@mail.com",
-                // Password: "password123",
-                Token:    "testtoken123",
-            },
-            expected: dto.UserJWTResponse{
-                ID:       1,
-                Name:     "Test User",
-                Email:    "testuser@mail.com",
-                // Password: "password123",
-                Token:    "testtoken123",
-            },
-        },
-        {
-            name: "Empty Fields",
-            input: dto.UserJWTResponse{
-                ID:       0,
-                Name:     "",
-                Email:    "",
-                // Password: "",
-                Token:    "",
-            },
-            expected: dto.UserJWTResponse{
-                ID:       0,
-                Name:     "",
-                Email:    "",
-                // Password: "",
-                Token:    "",
-            },
-        },
-    }
+	tests := []struct {
+		name     string
+		input    dto.UserJWTResponse
+		expected dto.UserJWTResponse
+	}{
+		{
+			name: "Valid Input",
+			input: dto.UserJWTResponse{
+				ID:    1,
+				Name:  "Test User",
+				Email: "testuser@mail.com",
+				// Password: "password123",
+				Token: "testtoken123",
+			},
+			expected: dto.UserJWTResponse{
+				ID:    1,
+				Name:  "Test User",
+				Email: "testuser@mail.com",
+				// Password: "password123",
+				Token: "testtoken123",
+			},
+		},
+		{
+			name: "Empty Fields",
+			input: dto.UserJWTResponse{
+				ID:    0,
+				Name:  "",
+				Email: "",
+				// Password: "",
+				Token: "",
+			},
+			expected: dto.UserJWTResponse{
+				ID:    0,
+				Name:  "",
+				Email: "",
+				// Password: "",
+				Token: "",
+			},
+		},
+	}
 
-    for _, test := range tests {
-        t.Run(test.name, func(t *testing.T) {
-            if test.input != test.expected {
-                t.Errorf("expected %v, got %v", test.expected, test.input)
-            }
-        })
-    }
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.input != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, test.input)
+			}
+		})
+	}
 }
 
 func TestUserResponse(t *testing.T) {
-    tests := []struct {
-        name     string
-        input    dto.UserResponse
-        expected dto.UserResponse
-    }{
-        {
-            name: "Valid Input",
-            input: dto.UserResponse{
-                ID:       1,
-                Name:     "Test User",
-                Email: "testuser@mail.com",
-            },
-            expected: dto.UserResponse{
-                ID:       1,
-                Name:     "Test User",
-                Email: "testuser@mail.com",
-            },
-        },
-    }
+	tests := []struct {
+		name     string
+		input    dto.UserResponse
+		expected dto.UserResponse
+	}{
+		{
+			name: "Valid Input",
+			input: dto.UserResponse{
+				ID:    1,
+				Name:  "Test User",
+				Email: "testuser@mail.com",
+			},
+			expected: dto.UserResponse{
+				ID:    1,
+				Name:  "Test User",
+				Email: "testuser@mail.com",
+			},
+		},
+	}
 
-    for _, test := range tests {
-        t.Run(test.name, func(t *testing.T) {
-            if test.input != test.expected {
-                t.Errorf("expected %v, got %v", test.expected, test.input)
-            }
-        })
-    }
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.input != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, test.input)
+			}
+		})
+	}
 }
 
-
 func TestUserListResponse(t *testing.T) {
-    tests := []struct {
-        name     string
-        input    dto.UserListResponse
-        expected dto.UserListResponse
-    }{
-        {
-            name: "Valid Input",
-            input: dto.UserListResponse{
-                Users: []dto.UserResponse{
-                    {
-                        ID:       1,
-                        Name:     "User One",
-                        Email:    "userone@mail.com",
-                    },
-                    {
-                        ID:       2,
-                        Name:     "User Two",
-                        Email:    "usertwo@mail.com",
-                    },
-                },
-                Total: 2,
-            },
-            expected: dto.UserListResponse{
-                Users: []dto.UserResponse{
-                    {
-                        ID:       1,
-                        Name:     "User One",
-                        Email:    "userone@mail.com",
-                    },
-                    {
-                        ID:       2,
-                        Name:     "User Two",
-                        Email:    "usertwo@mail.com",
-                    },
-                },
-                Total: 2,
-            },
-        },
-        {
-            name: "Empty List",
-            input: dto.UserListResponse{
-                Users: []dto.UserResponse{},
-                Total: 0,
-            },
-            expected: dto.UserListResponse{
-                Users: []dto.UserResponse{},
-                Total: 0,
-            },
-        },
-    }
+	tests := []struct {
+		name     string
+		input    dto.UserListResponse
+		expected dto.UserListResponse
+	}{
+		{
+			name: "Valid Input",
+			input: dto.UserListResponse{
+				Users: []dto.UserResponse{
+					{
+						ID:    1,
+						Name:  "User One",
+						Email: "userone@mail.com",
+					},
+					{
+						ID:    2,
+						Name:  "User Two",
+						Email: "usertwo@mail.com",
+					},
+				},
+				Total: 2,
+			},
+			expected: dto.UserListResponse{
+				Users: []dto.UserResponse{
+					{
+						ID:    1,
+						Name:  "User One",
+						Email: "userone@mail.com",
+					},
+					{
+						ID:    2,
+						Name:  "User Two",
+						Email: "usertwo@mail.com",
+					},
+				},
+				Total: 2,
+			},
+		},
+		{
+			name: "Empty List",
+			input: dto.UserListResponse{
+				Users: []dto.UserResponse{},
+				Total: 0,
+			},
+			expected: dto.UserListResponse{
+				Users: []dto.UserResponse{},
+				Total: 0,
+			},
+		},
+	}
 
-    for _, test := range tests {
-        t.Run(test.name, func(t *testing.T) {
-            if len(test.input.Users) != len(test.expected.Users) || test.input.Total != test.expected.Total {
-                t.Errorf("expected %v, got %v", test.expected, test.input)
-            }
-            for i, user := range test.input.Users {
-                if user != test.expected.Users[i] {
-                    t.Errorf("expected user %v, got %v", test.expected.Users[i], user)
-                }
-            }
-        })
-    }
-}
\ No newline at end of file
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if len(test.input.Users) != len(test.expected.Users) || test.input.Total != test.expected.Total {
+				t.Errorf("expected %v, got %v", test.expected, test.input)
+			}
+			for i, user := range test.input.Users {
+				if user != test.expected.Users[i] {
+					t.Errorf("expected user %v, got %v", test.expected.Users[i], user)
+				}
+			}
+		})
+	}
+}
+
+func TestUserProfileRequestValidation(t *testing.T) {
+	validate := validator.New()
+	text := func(value string) *string { return &value }
+
+	tests := []struct {
+		name    string
+		input   dto.UserProfileRequest
+		wantErr bool
+	}{
+		{name: "Empty profile", input: dto.UserProfileRequest{}, wantErr: false},
+		{
+			name: "Full profile",
+			input: dto.UserProfileRequest{
+				Bio:      text("Hello"),
+				Website:  text("https://example.com"),
+				Locale:   text("id-ID"),
+				Timezone: text("Asia/Jakarta"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Cleared fields",
+			input:   dto.UserProfileRequest{Bio: text(""), Website: text(""), Locale: text(""), Timezone: text("")},
+			wantErr: false,
+		},
+		{name: "Invalid website", input: dto.UserProfileRequest{Website: text("not a url")}, wantErr: true},
+		{name: "Invalid locale", input: dto.UserProfileRequest{Locale: text("english please")}, wantErr: true},
+		{name: "Invalid timezone", input: dto.UserProfileRequest{Timezone: text("Mars/Olympus")}, wantErr: true},
+		{name: "Bio too long", input: dto.UserProfileRequest{Bio: text(strings.Repeat("a", 501))}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validate.Struct(test.input)
+			if (err != nil) != test.wantErr {
+				t.Errorf("validation error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/model"
+
 	"github.com/ahmadammarm/go-rest-api-template/internal/middleware"
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
 	userService "github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
 	formvalidation "github.com/ahmadammarm/go-rest-api-template/pkg/form-validation"
 	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
 	"github.com/go-playground/validator/v10"
@@ -29,7 +33,7 @@ func (handler *UserHandler) RegisterUser(context *fiber.Ctx) error {
 	}
 
 	if err := handler.userService.RegisterUser(user); err != nil {
-		if err.Error() == "email already exists" {
+		if errors.Is(err, userService.ErrEmailExists) {
 			return response.JSONResponse(context, 409, "Email Already Exists", nil)
 		}
 		return response.JSONResponse(context, 500, "Register User Failed", nil)
@@ -75,7 +79,7 @@ func (handler *UserHandler) UpdateUser(context *fiber.Ctx) error {
 	userId := context.Locals("user_id").(int)
 
 	if err := handler.userService.UpdateUser(user, userId); err != nil {
-		if err.Error() == "email already exists" {
+		if errors.Is(err, userService.ErrEmailExists) {
 			return response.JSONResponse(context, 409, "Email Already Exists", nil)
 		}
 		return response.JSONResponse(context, 500, "Update User Failed", nil)
@@ -84,29 +88,101 @@ func (handler *UserHandler) UpdateUser(context *fiber.Ctx) error {
 	return response.JSONResponse(context, 200, "Update User Success", nil)
 }
 
+var (
+	userResource = crud.Resource[int]{
+		Name:     "user",
+		ParseID:  crud.IntID,
+		NotFound: userService.ErrNotFound,
+		Messages: map[int]string{400: "Invalid Request", 404: "User Not Found"},
+	}
+	userListResource = crud.Resource[int]{
+		Name:     "user",
+		Messages: map[int]string{500: "User List Failed"},
+	}
+)
+
 func (handler *UserHandler) GetUserByID(context *fiber.Ctx) error {
-	userIdString := context.Params("id")
-	userId, err := strconv.Atoi(userIdString)
+	return crud.Get(userResource, "Get User Success", handler.userService.GetUserByID)(context)
+}
 
-	if err != nil || userId < 1 {
+func (handler *UserHandler) UserList(context *fiber.Ctx) error {
+	return crud.List(userListResource, "Get User List Success", handler.userService.UserList)(context)
+}
+
+func (handler *UserHandler) UpdateProfile(context *fiber.Ctx) error {
+	profile := new(dto.UserProfileRequest)
+	if err := context.BodyParser(profile); err != nil {
+		return response.JSONResponse(context, 400, "Invalid Request", nil)
+	}
+
+	if err := handler.validation.Struct(profile); err != nil {
+		errorValidations := formvalidation.FormValidationError(err)
+		return response.JSONResponse(context, 400, "Invalid Request", errorValidations)
+	}
+
+	userId := context.Locals("user_id").(int)
+
+	if err := handler.userService.UpdateProfile(profile, userId); err != nil {
+		if errors.Is(err, userService.ErrNotFound) {
+			return response.JSONResponse(context, 404, "User Not Found", nil)
+		}
+		return response.JSONResponse(context, 500, "Update Profile Failed", nil)
+	}
+
+	return response.JSONResponse(context, 200, "Update Profile Success", nil)
+}
+
+func (handler *UserHandler) UpdateAvatar(context *fiber.Ctx) error {
+	fileHeader, err := context.FormFile("avatar")
+	if err != nil {
 		return response.JSONResponse(context, 400, "Invalid Request", nil)
 	}
 
-	user, err := handler.userService.GetUserByID(userId)
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.JSONResponse(context, 400, "Invalid Request", nil)
+	}
+	defer file.Close()
+
+	userId := context.Locals("user_id").(int)
+
+	user, err := handler.userService.UpdateAvatar(userId, file)
 	if err != nil {
-		return response.JSONResponse(context, 404, "User Not Found", nil)
+		switch err.Error() {
+		case "file too large":
+			return response.JSONResponse(context, 413, "Avatar Too Large", nil)
+		case "invalid image":
+			return response.JSONResponse(context, 415, "Invalid Avatar Image", nil)
+		case "user not found":
+			return response.JSONResponse(context, 404, "User Not Found", nil)
+		}
+		return response.JSONResponse(context, 500, "Update Avatar Failed", nil)
 	}
 
-	return response.JSONResponse(context, 200, "Get User Success", user)
+	return response.JSONResponse(context, 200, "Update Avatar Success", user)
 }
 
-func (handler *UserHandler) UserList(context *fiber.Ctx) error {
-	userList, err := handler.userService.UserList()
+func (handler *UserHandler) GetAvatar(context *fiber.Ctx) error {
+	userId, err := strconv.Atoi(context.Params("id"))
+	if err != nil || userId < 1 {
+		return response.JSONResponse(context, 400, "Invalid Request", nil)
+	}
+
+	size := context.QueryInt("size", model.AvatarSizes[len(model.AvatarSizes)-1])
+
+	avatar, err := handler.userService.OpenAvatar(userId, size)
 	if err != nil {
-		return response.JSONResponse(context, 500, "User List Failed", nil)
+		if err.Error() == "invalid avatar size" {
+			return response.JSONResponse(context, 400, "Invalid Avatar Size", nil)
+		}
+		return response.JSONResponse(context, 404, "Avatar Not Found", nil)
 	}
 
-	return response.JSONResponse(context, 200, "Get User List Success", userList)
+	context.Set(fiber.HeaderContentType, "image/jpeg")
+	// The avatar URL changes with every upload, so responses never go stale.
+	context.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+
+	return context.SendStream(avatar)
 }
 
 func (handler *UserHandler) UserRouters(router fiber.Router) {
@@ -114,7 +190,10 @@ func (handler *UserHandler) UserRouters(router fiber.Router) {
 	router.Post("/auth/login", handler.LoginUser)
 	router.Get("/users", handler.UserList)
 	router.Get("/users/:id", handler.GetUserByID)
+	router.Get("/users/:id/avatar", handler.GetAvatar)
 	router.Put("/users/me", middleware.JWTAuth(), handler.UpdateUser)
+	router.Put("/users/me/profile", middleware.JWTAuth(), handler.UpdateProfile)
+	router.Put("/users/me/avatar", middleware.JWTAuth(), handler.UpdateAvatar)
 }
 
 func NewUserHandler(userService userService.UserService, validation *validator.Validate) *UserHandler {
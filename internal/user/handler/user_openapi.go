@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var (
+	authTags = []string{"Auth"}
+	userTags = []string{"Users"}
+)
+
+// UserOperations documents the routes of UserRouters.
+func (handler *UserHandler) UserOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "POST", Path: "/auth/register", Summary: "Register an user", Tags: authTags,
+			Request: dto.UserRegisterRequest{}, Response: dto.UserResponse{}, Errors: []int{400, 409, 500}},
+		{Method: "POST", Path: "/auth/login", Summary: "Login and get a JWT", Tags: authTags,
+			Request: dto.UserLoginRequest{}, Response: dto.UserJWTResponse{}, Errors: []int{400, 401}},
+		{Method: "GET", Path: "/users", Summary: "Get all users", Tags: userTags,
+			Response: dto.UserListResponse{}, Errors: []int{500}},
+		{Method: "GET", Path: "/users/:id", Summary: "Get an user by id", Tags: userTags,
+			Response: dto.UserResponse{}, Errors: []int{400, 404, 500}},
+		{Method: "GET", Path: "/users/:id/avatar", Summary: "Get an user avatar", Tags: userTags,
+			Query:       []openapi.Param{{Name: "size", Type: "integer", Description: "Width in pixels: 64, 128 or 256"}},
+			ContentType: "image/jpeg", Errors: []int{400, 404}},
+		{Method: "PUT", Path: "/users/me", Summary: "Update your account", Tags: userTags, Security: openapi.BearerAuth,
+			Request: dto.UserUpdateRequest{}, Errors: []int{400, 409, 500}},
+		{Method: "PUT", Path: "/users/me/profile", Summary: "Update your profile", Tags: userTags, Security: openapi.BearerAuth,
+			Request: dto.UserProfileRequest{}, Errors: []int{400, 500}},
+		{Method: "PUT", Path: "/users/me/avatar", Summary: "Upload your avatar", Tags: userTags, Security: openapi.BearerAuth,
+			Files: []string{"avatar"}, Response: dto.UserResponse{}, Errors: []int{400, 404, 413, 415, 500}},
+	}
+}
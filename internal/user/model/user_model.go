@@ -1,9 +1,37 @@
 package model
 
+import (
+	"fmt"
+	"path"
+)
+
+// AvatarSizes are the square sizes, in pixels, every uploaded avatar is
+// resized to.
+var AvatarSizes = []int{64, 128, 256}
 
 type User struct {
 	ID        int       `json:"id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	Password  string    `json:"password"`
-}
\ No newline at end of file
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
+	Website   string    `json:"website"`
+	Locale    string    `json:"locale"`
+	Timezone  string    `json:"timezone"`
+}
+
+// AvatarURL returns the public path of a user's avatar, or "" when the user
+// has none. The stored key is part of the URL so a new upload busts caches.
+func AvatarURL(userId int, avatarKey string) string {
+	if avatarKey == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("/api/v1/users/%d/avatar?v=%s", userId, path.Base(avatarKey))
+}
+
+// AvatarObjectKey is the storage key of one resized avatar image.
+func AvatarObjectKey(avatarKey string, size int) string {
+	return fmt.Sprintf("%s_%d.jpg", avatarKey, size)
+}
@@ -30,3 +30,17 @@ func TestUser(t *testing.T) {
 		t.Errorf("expected Password to be 'securepassword', got %s", user.Password)
 	}
 }
+
+func TestAvatarURL(t *testing.T) {
+	if url := model.AvatarURL(1, ""); url != "" {
+		t.Errorf("expected empty avatar URL, got %s", url)
+	}
+
+	if url := model.AvatarURL(1, "avatars/1/abc"); url != "/api/v1/users/1/avatar?v=abc" {
+		t.Errorf("expected '/api/v1/users/1/avatar?v=abc', got %s", url)
+	}
+
+	if key := model.AvatarObjectKey("avatars/1/abc", 64); key != "avatars/1/abc_64.jpg" {
+		t.Errorf("expected 'avatars/1/abc_64.jpg', got %s", key)
+	}
+}
@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"strconv"
+	"strings"
 
 	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/model"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -13,66 +19,140 @@ type UserRepo interface {
 	LoginUser(user *userDTO.UserLoginRequest) (*userDTO.UserJWTResponse, error)
 	UpdateUser(name string, email string, hashedPassword string, id int) error
 	GetUserByID(userId int) (*userDTO.UserResponse, error)
+	GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error)
 	IsEmailExists(email string) (bool, error)
 	IsEmailTakenByOther(email string, id int) (bool, error)
 	UserList() (*userDTO.UserListResponse, error)
+	UpdateProfile(profile *userDTO.UserProfileRequest, id int) error
+	GetAvatarKey(id int) (string, error)
+	UpdateAvatarKey(avatarKey string, id int) error
+	SetAdmin(id int, admin bool) error
+	UpdatePassword(email string, hashedPassword string) error
+	// WithTx returns the repository running its queries in tx, for
+	// services to make several calls atomically.
+	WithTx(tx *database.Tx) UserRepo
 }
 
-type userRepoImpl struct {
-	db *sql.DB
+// ChangeHook runs in the transaction of a change, with one of the dto.Event*
+// events and its data, so that what it writes commits or rolls back with the
+// change. An error aborts the change.
+type ChangeHook func(tx *sql.Tx, event string, data any) error
+
+var (
+	// ErrNotFound is returned for a user no row matches.
+	ErrNotFound = errors.New("user not found")
+	// ErrInvalidPassword is returned when logging in with a wrong password.
+	ErrInvalidPassword = errors.New("invalid password")
+)
+
+// userTable maps users to their profile.
+var userTable = crud.Table[userDTO.UserResponse]{
+	Name:     "users",
+	Key:      "id",
+	Columns:  []string{"id", "name", "email", "bio", "website", "locale", "timezone", "avatar_key"},
+	Scan:     scanUser,
+	NotFound: ErrNotFound,
 }
 
-func (repository *userRepoImpl) RegisterUser(user *userDTO.UserRegisterRequest) (err error) {
-	tx, err := repository.db.Begin()
+// userSummaryTable maps users to what lists show of them.
+var userSummaryTable = crud.Table[userDTO.UserResponse]{
+	Name:     "users",
+	Key:      "id",
+	Columns:  []string{"id", "email", "name", "avatar_key"},
+	Scan:     scanUserSummary,
+	NotFound: ErrNotFound,
+}
 
-	if err != nil {
+func scanUser(scanner crud.Scanner, user *userDTO.UserResponse) error {
+	var avatarKey string
+	if err := scanner.Scan(&user.ID, &user.Name, &user.Email, &user.Bio, &user.Website, &user.Locale, &user.Timezone, &avatarKey); err != nil {
 		return err
 	}
 
-	defer func() {
-		if pan := recover(); pan != nil {
-			_ = tx.Rollback()
-			panic(pan)
-		} else if err != nil {
-			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
-		}
-	}()
+	user.AvatarURL = model.AvatarURL(user.ID, avatarKey)
+	return nil
+}
 
-	query := `INSERT INTO users (email, name, password) VALUES ($1, $2, $3) RETURNING id`
+func scanUserSummary(scanner crud.Scanner, user *userDTO.UserResponse) error {
+	var avatarKey string
+	if err := scanner.Scan(&user.ID, &user.Email, &user.Name, &avatarKey); err != nil {
+		return err
+	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	user.AvatarURL = model.AvatarURL(user.ID, avatarKey)
+	return nil
+}
 
-	if err != nil {
-		return err
+type userRepoImpl struct {
+	users *crud.Repository[userDTO.UserResponse, int]
+	// summaries read lists on the read replicas, if any, unless bound to
+	// tx.
+	summaries *crud.Repository[userDTO.UserResponse, int]
+	// db is the database, or tx when the repository is bound to one.
+	db           database.Querier
+	transactions *database.TxManager
+	tx           *database.Tx
+	changeHooks  []ChangeHook
+}
+
+// inTx runs fn in the transaction the repository is bound to, or in a new
+// one.
+func (repository *userRepoImpl) inTx(fn func(tx *database.Tx) error) error {
+	if repository.tx != nil {
+		return fn(repository.tx)
 	}
 
-	err = tx.QueryRow(query, user.Email, user.Name, hashedPassword).Scan(&user.ID)
+	return repository.transactions.Run(context.Background(), fn)
+}
+
+// WithTx returns a copy of the repository running its queries in tx.
+func (repository *userRepoImpl) WithTx(tx *database.Tx) UserRepo {
+	bound := *repository
+	bound.users, bound.summaries = repository.users.With(tx), repository.summaries.With(tx)
+	bound.db, bound.tx = tx, tx
+	return &bound
+}
+
+func (repository *userRepoImpl) RegisterUser(user *userDTO.UserRegisterRequest) error {
+	query := `INSERT INTO users (email, name, password) VALUES ($1, $2, $3) RETURNING id`
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return repository.inTx(func(tx *database.Tx) error {
+		if err := tx.QueryRow(query, user.Email, user.Name, hashedPassword).Scan(&user.ID); err != nil {
+			return err
+		}
+
+		registered := userDTO.RegisteredEvent{ID: user.ID, Name: user.Name, Email: user.Email}
+		for _, hook := range repository.changeHooks {
+			if err := hook(tx.Tx, userDTO.EventRegistered, registered); err != nil {
+				return err
+			}
+		}
 
+		return nil
+	})
 }
 
 func (repository *userRepoImpl) LoginUser(user *userDTO.UserLoginRequest) (*userDTO.UserJWTResponse, error) {
-	query := `SELECT id, name, email, password FROM users WHERE email = $1`
+	query := `SELECT id, name, email, password, is_admin FROM users WHERE email = $1`
 	jwtUser := &userDTO.UserJWTResponse{}
 	var hashedPassword string
 
-	err := repository.db.QueryRow(query, user.Email).Scan(&jwtUser.ID, &jwtUser.Name, &jwtUser.Email, &hashedPassword)
+	err := repository.db.QueryRow(query, user.Email).Scan(&jwtUser.ID, &jwtUser.Name, &jwtUser.Email, &hashedPassword, &jwtUser.IsAdmin)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(user.Password)); err != nil {
-		return nil, errors.New("invalid password")
+		return nil, ErrInvalidPassword
 	}
 
 	return jwtUser, nil
@@ -91,45 +171,33 @@ func (repository *userRepoImpl) UpdateUser(name string, email string, hashedPass
 }
 
 func (repository *userRepoImpl) GetUserByID(userId int) (*userDTO.UserResponse, error) {
-	query := `SELECT id, name, email FROM users WHERE id = $1`
-	user := &userDTO.UserResponse{}
+	return repository.users.Get(userId)
+}
 
-	err := repository.db.QueryRow(query, userId).Scan(&user.ID, &user.Name, &user.Email)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
-		}
-		return nil, err
+// GetUsersByIDs returns the users found among userIds, in no particular
+// order.
+func (repository *userRepoImpl) GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error) {
+	if len(userIds) == 0 {
+		return []userDTO.UserResponse{}, nil
 	}
 
-	return user, nil
+	placeholders := make([]string, len(userIds))
+	args := make([]any, len(userIds))
+	for i, id := range userIds {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+
+	return repository.users.Where(`id IN (`+strings.Join(placeholders, ", ")+`)`, "", args...)
 }
 
 func (repository *userRepoImpl) UserList() (*userDTO.UserListResponse, error) {
-	query := `SELECT id, email, name FROM users`
-	rows, err := repository.db.Query(query)
+	users, err := repository.summaries.All()
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var users []userDTO.UserResponse
-	for rows.Next() {
-		user := userDTO.UserResponse{}
-		err := rows.Scan(&user.ID, &user.Email, &user.Name)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
-	}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-
-	total := len(users)
-
-	return &userDTO.UserListResponse{Users: users, Total: total}, nil
+	return &userDTO.UserListResponse{Users: users, Total: len(users)}, nil
 }
 
 func (repository *userRepoImpl) IsEmailTakenByOther(email string, id int) (bool, error) {
@@ -152,8 +220,75 @@ func (repository *userRepoImpl) IsEmailExists(email string) (bool, error) {
     return count > 0, nil
 }
 
-func NewUserRepository(db *sql.DB) UserRepo {
+func (repository *userRepoImpl) UpdateProfile(profile *userDTO.UserProfileRequest, id int) error {
+	// A NULL argument keeps the column: only the fields set change.
+	query := `UPDATE users SET bio = COALESCE($1, bio), website = COALESCE($2, website), locale = COALESCE($3, locale),
+              timezone = COALESCE($4, timezone) WHERE id = $5`
+
+	result, err := repository.db.Exec(query, profile.Bio, profile.Website, profile.Locale, profile.Timezone, id)
+	if err != nil {
+		return err
+	}
+
+	return crud.ExpectOne(result, ErrNotFound)
+}
+
+func (repository *userRepoImpl) GetAvatarKey(id int) (string, error) {
+	query := `SELECT avatar_key FROM users WHERE id = $1`
+
+	var avatarKey string
+	err := repository.db.QueryRow(query, id).Scan(&avatarKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	return avatarKey, nil
+}
+
+func (repository *userRepoImpl) UpdateAvatarKey(avatarKey string, id int) error {
+	query := `UPDATE users SET avatar_key = $1 WHERE id = $2`
+
+	result, err := repository.db.Exec(query, avatarKey, id)
+	if err != nil {
+		return err
+	}
+
+	return crud.ExpectOne(result, ErrNotFound)
+}
+
+func (repository *userRepoImpl) SetAdmin(id int, admin bool) error {
+	query := `UPDATE users SET is_admin = $1 WHERE id = $2`
+
+	result, err := repository.db.Exec(query, admin, id)
+	if err != nil {
+		return err
+	}
+
+	return crud.ExpectOne(result, ErrNotFound)
+}
+
+func (repository *userRepoImpl) UpdatePassword(email string, hashedPassword string) error {
+	query := `UPDATE users SET password = $1 WHERE email = $2`
+
+	result, err := repository.db.Exec(query, hashedPassword, email)
+	if err != nil {
+		return err
+	}
+
+	return crud.ExpectOne(result, ErrNotFound)
+}
+
+// NewUserRepository returns the users of db. Lists are read from reads,
+// which may lag behind db.
+func NewUserRepository(db *sql.DB, reads database.Querier, changeHooks ...ChangeHook) UserRepo {
 	return &userRepoImpl{
-		db: db,
+		users:        crud.New[userDTO.UserResponse, int](db, userTable),
+		summaries:    crud.New[userDTO.UserResponse, int](db, userSummaryTable).Replicated(reads),
+		db:           db,
+		transactions: database.NewTxManager(db),
+		changeHooks:  changeHooks,
 	}
 }
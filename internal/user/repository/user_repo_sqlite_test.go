@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database/databasetest"
+)
+
+func TestUserRepository_SQLite(t *testing.T) {
+	db := databasetest.SQLite(t)
+
+	var events []string
+	repo := repository.NewUserRepository(db, db, func(tx *sql.Tx, event string, data any) error {
+		events = append(events, event)
+		return nil
+	})
+
+	ammar := &userDTO.UserRegisterRequest{Email: "ammar@mail.com", Name: "Ammar", Password: "secret1"}
+	assert.NoError(t, repo.RegisterUser(ammar))
+	assert.Equal(t, 1, ammar.ID)
+	assert.Equal(t, []string{userDTO.EventRegistered}, events)
+
+	admin := &userDTO.UserRegisterRequest{Email: "admin@mail.com", Name: "Admin", Password: "secret2"}
+	assert.NoError(t, repo.RegisterUser(admin))
+	assert.Equal(t, 2, admin.ID)
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		err := repo.RegisterUser(&userDTO.UserRegisterRequest{Email: "ammar@mail.com", Name: "Other", Password: "secret3"})
+		assert.ErrorContains(t, err, "UNIQUE constraint failed")
+
+		exists, err := repo.IsEmailExists("ammar@mail.com")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		taken, err := repo.IsEmailTakenByOther("ammar@mail.com", ammar.ID)
+		assert.NoError(t, err)
+		assert.False(t, taken)
+	})
+
+	t.Run("logs in", func(t *testing.T) {
+		user, err := repo.LoginUser(&userDTO.UserLoginRequest{Email: "ammar@mail.com", Password: "secret1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &userDTO.UserJWTResponse{ID: ammar.ID, Name: "Ammar", Email: "ammar@mail.com"}, user)
+
+		_, err = repo.LoginUser(&userDTO.UserLoginRequest{Email: "ammar@mail.com", Password: "wrong"})
+		assert.EqualError(t, err, "invalid password")
+
+		_, err = repo.LoginUser(&userDTO.UserLoginRequest{Email: "nobody@mail.com", Password: "secret1"})
+		assert.EqualError(t, err, "user not found")
+	})
+
+	t.Run("keeps the password when updated without one", func(t *testing.T) {
+		assert.NoError(t, repo.UpdateUser("Ammar M", "ammar@mail.com", "", ammar.ID))
+
+		user, err := repo.LoginUser(&userDTO.UserLoginRequest{Email: "ammar@mail.com", Password: "secret1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Ammar M", user.Name)
+	})
+
+	t.Run("updates the profile", func(t *testing.T) {
+		bio, website, locale, timezone := "Writer", "https://example.com", "id", "Asia/Jakarta"
+		profile := &userDTO.UserProfileRequest{Bio: &bio, Website: &website, Locale: &locale, Timezone: &timezone}
+		assert.NoError(t, repo.UpdateProfile(profile, ammar.ID))
+		assert.NoError(t, repo.UpdateAvatarKey("avatars/1.png", ammar.ID))
+
+		user, err := repo.GetUserByID(ammar.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Writer", user.Bio)
+		assert.Equal(t, "Asia/Jakarta", user.Timezone)
+		assert.NotEmpty(t, user.AvatarURL)
+
+		// Only the fields set change, and an empty one is cleared.
+		bio, website = "Editor", ""
+		assert.NoError(t, repo.UpdateProfile(&userDTO.UserProfileRequest{Bio: &bio, Website: &website}, ammar.ID))
+
+		user, err = repo.GetUserByID(ammar.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Editor", user.Bio)
+		assert.Empty(t, user.Website)
+		assert.Equal(t, "id", user.Locale)
+		assert.Equal(t, "Asia/Jakarta", user.Timezone)
+
+		assert.EqualError(t, repo.UpdateProfile(profile, 404), "user not found")
+		_, err = repo.GetUserByID(404)
+		assert.EqualError(t, err, "user not found")
+	})
+
+	t.Run("lists users", func(t *testing.T) {
+		users, err := repo.GetUsersByIDs([]int{admin.ID, 404, ammar.ID})
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+
+		list, err := repo.UserList()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, list.Total)
+	})
+
+	t.Run("rolls back with the transaction", func(t *testing.T) {
+		err := database.NewTxManager(db).Run(context.Background(), func(tx *database.Tx) error {
+			if err := repo.WithTx(tx).RegisterUser(&userDTO.UserRegisterRequest{Email: "temp@mail.com", Name: "Temp", Password: "secret4"}); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		exists, err := repo.IsEmailExists("temp@mail.com")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
 	"github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -23,7 +25,7 @@ func TestRegisterUser(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserRegisterRequest{
 		Email:    "test@example.com",
 		Name:     "Test User",
@@ -35,12 +37,78 @@ func TestRegisterUser(t *testing.T) {
 	assert.Equal(t, 1, request.ID)
 }
 
+func TestRegisterUser_WithTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// The check and the insert share the caller's transaction.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COUNT\(1\) FROM users WHERE email = \$1`).
+		WithArgs("test@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	repo := repository.NewUserRepository(db, db)
+	request := &userDTO.UserRegisterRequest{Email: "test@example.com", Name: "Test User", Password: "password123"}
+
+	err = database.NewTxManager(db).Run(context.Background(), func(tx *database.Tx) error {
+		bound := repo.WithTx(tx)
+		if exists, err := bound.IsEmailExists(request.Email); err != nil {
+			return err
+		} else if exists {
+			return errors.New("email already exists")
+		}
+		return bound.RegisterUser(request)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, request.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterUser_ChangeHooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var events []string
+	var hookErr error
+	hook := func(tx *sql.Tx, event string, data any) error {
+		events = append(events, event)
+		assert.Equal(t, userDTO.RegisteredEvent{ID: 1, Name: "Test User", Email: "test@example.com"}, data)
+		return hookErr
+	}
+	repo := repository.NewUserRepository(db, db, hook)
+	request := func() *userDTO.UserRegisterRequest {
+		return &userDTO.UserRegisterRequest{Email: "test@example.com", Name: "Test User", Password: "password123"}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RegisterUser(request()))
+	assert.Equal(t, []string{userDTO.EventRegistered}, events)
+
+	hookErr = errors.New("outbox unavailable")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectRollback()
+
+	assert.EqualError(t, repo.RegisterUser(request()), "outbox unavailable")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRegisterUser_HashPasswordError(t *testing.T) {
 	db, _, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserRegisterRequest{
 		Email:    "test@example.com",
 		Name:     "Test User",
@@ -62,7 +130,7 @@ func TestRegisterUser_QueryError(t *testing.T) {
 		WillReturnError(errors.New("query error"))
 	mock.ExpectRollback()
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserRegisterRequest{
 		Email:    "test@example.com",
 		Name:     "Test User",
@@ -80,12 +148,12 @@ func TestLoginUser_Success(t *testing.T) {
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 
-	mock.ExpectQuery(`SELECT id, name, email, password FROM users WHERE email = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, password, is_admin FROM users WHERE email = \$1`).
 		WithArgs("test@example.com").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-			AddRow(1, "Test User", "test@example.com", hashedPassword))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "is_admin"}).
+			AddRow(1, "Test User", "test@example.com", hashedPassword, false))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserLoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
@@ -104,11 +172,11 @@ func TestLoginUser_UserNotFound(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, name, email, password FROM users WHERE email = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, password, is_admin FROM users WHERE email = \$1`).
 		WithArgs("nonexistent@example.com").
 		WillReturnError(sql.ErrNoRows)
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserLoginRequest{
 		Email:    "nonexistent@example.com",
 		Password: "password123",
@@ -127,12 +195,12 @@ func TestLoginUser_InvalidPassword(t *testing.T) {
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 
-	mock.ExpectQuery(`SELECT id, name, email, password FROM users WHERE email = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, password, is_admin FROM users WHERE email = \$1`).
 		WithArgs("test@example.com").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-			AddRow(1, "Test User", "test@example.com", hashedPassword))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "is_admin"}).
+			AddRow(1, "Test User", "test@example.com", hashedPassword, false))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserLoginRequest{
 		Email:    "test@example.com",
 		Password: "wrongpassword",
@@ -149,11 +217,11 @@ func TestLoginUser_QueryError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, name, email, password FROM users WHERE email = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, password, is_admin FROM users WHERE email = \$1`).
 		WithArgs("test@example.com").
 		WillReturnError(errors.New("query error"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 	request := &userDTO.UserLoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
@@ -170,7 +238,7 @@ func TestUpdateUser_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	mock.ExpectExec(`UPDATE users SET name = \$1, email = \$2, password = CASE WHEN \$3 <> '' THEN \$3 ELSE password END WHERE id = \$4`).
 		WithArgs("Updated User", "updated@example.com", "hashedpassword123", 1).
@@ -190,7 +258,7 @@ func TestUpdateUser_QueryError(t *testing.T) {
 		WithArgs("Updated User", "updated@example.com", "hashedpassword123", 1).
 		WillReturnError(errors.New("query error"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	err = repo.UpdateUser("Updated User", "updated@example.com", "hashedpassword123", 1)
 	assert.Error(t, err)
@@ -201,12 +269,12 @@ func TestGetUserByID_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, name, email FROM users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, bio, website, locale, timezone, avatar_key FROM users WHERE id = \$1`).
 		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow(1, "Test User", "test@example.com"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "bio", "website", "locale", "timezone", "avatar_key"}).
+			AddRow(1, "Test User", "test@example.com", "Hello", "https://example.com", "en-US", "Asia/Jakarta", "avatars/1/abc"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.GetUserByID(1)
 	assert.NoError(t, err)
@@ -214,6 +282,8 @@ func TestGetUserByID_Success(t *testing.T) {
 	assert.Equal(t, 1, response.ID)
 	assert.Equal(t, "Test User", response.Name)
 	assert.Equal(t, "test@example.com", response.Email)
+	assert.Equal(t, "Asia/Jakarta", response.Timezone)
+	assert.Equal(t, "/api/v1/users/1/avatar?v=abc", response.AvatarURL)
 }
 
 func TestGetUserByID_UserNotFound(t *testing.T) {
@@ -221,11 +291,11 @@ func TestGetUserByID_UserNotFound(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, name, email FROM users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, bio, website, locale, timezone, avatar_key FROM users WHERE id = \$1`).
 		WithArgs(999).
 		WillReturnError(sql.ErrNoRows)
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.GetUserByID(999)
 	assert.Error(t, err)
@@ -238,11 +308,11 @@ func TestGetUserByID_QueryError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, name, email FROM users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, email, bio, website, locale, timezone, avatar_key FROM users WHERE id = \$1`).
 		WithArgs(1).
 		WillReturnError(errors.New("query error"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.GetUserByID(1)
 	assert.Error(t, err)
@@ -250,17 +320,47 @@ func TestGetUserByID_QueryError(t *testing.T) {
 	assert.Equal(t, "query error", err.Error())
 }
 
+func TestGetUsersByIDs_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, name, email, bio, website, locale, timezone, avatar_key FROM users WHERE id IN \(\$1, \$2\)`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "bio", "website", "locale", "timezone", "avatar_key"}).
+			AddRow(2, "User 2", "user2@example.com", "", "", "", "", ""))
+
+	repo := repository.NewUserRepository(db, db)
+
+	users, err := repo.GetUsersByIDs([]int{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, 2, users[0].ID)
+}
+
+func TestGetUsersByIDs_Empty(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db, db)
+
+	users, err := repo.GetUsersByIDs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
 func TestUserList_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, email, name FROM users`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name"}).
-			AddRow(1, "test1@example.com", "Test User 1").
-			AddRow(2, "test2@example.com", "Test User 2"))
+	mock.ExpectQuery(`SELECT id, email, name, avatar_key FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "avatar_key"}).
+			AddRow(1, "test1@example.com", "Test User 1", "").
+			AddRow(2, "test2@example.com", "Test User 2", "avatars/2/abc"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.UserList()
 	assert.NoError(t, err)
@@ -270,6 +370,8 @@ func TestUserList_Success(t *testing.T) {
 	assert.Equal(t, "Test User 1", response.Users[0].Name)
 	assert.Equal(t, "test2@example.com", response.Users[1].Email)
 	assert.Equal(t, "Test User 2", response.Users[1].Name)
+	assert.Empty(t, response.Users[0].AvatarURL)
+	assert.Equal(t, "/api/v1/users/2/avatar?v=abc", response.Users[1].AvatarURL)
 }
 
 func TestUserList_Empty(t *testing.T) {
@@ -277,10 +379,10 @@ func TestUserList_Empty(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, email, name FROM users`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name"}))
+	mock.ExpectQuery(`SELECT id, email, name, avatar_key FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "avatar_key"}))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.UserList()
 	assert.NoError(t, err)
@@ -294,13 +396,110 @@ func TestUserList_QueryError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, email, name FROM users`).
+	mock.ExpectQuery(`SELECT id, email, name, avatar_key FROM users`).
 		WillReturnError(errors.New("query error"))
 
-	repo := repository.NewUserRepository(db)
+	repo := repository.NewUserRepository(db, db)
 
 	response, err := repo.UserList()
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Equal(t, "query error", err.Error())
 }
+
+func TestUpdateProfile_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET bio = COALESCE\(\$1, bio\), website = COALESCE\(\$2, website\), locale = COALESCE\(\$3, locale\),\s+timezone = COALESCE\(\$4, timezone\) WHERE id = \$5`).
+		WithArgs("Hello", nil, "", "Asia/Jakarta", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewUserRepository(db, db)
+	bio, locale, timezone := "Hello", "", "Asia/Jakarta"
+	profile := &userDTO.UserProfileRequest{
+		Bio:      &bio,
+		Locale:   &locale,
+		Timezone: &timezone,
+	}
+
+	err = repo.UpdateProfile(profile, 1)
+	assert.NoError(t, err)
+}
+
+func TestUpdateProfile_UserNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET bio = COALESCE\(\$1, bio\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := repository.NewUserRepository(db, db)
+
+	err = repo.UpdateProfile(&userDTO.UserProfileRequest{}, 999)
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestGetAvatarKey_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT avatar_key FROM users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"avatar_key"}).AddRow("avatars/1/abc"))
+
+	repo := repository.NewUserRepository(db, db)
+
+	avatarKey, err := repo.GetAvatarKey(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "avatars/1/abc", avatarKey)
+}
+
+func TestUpdateAvatarKey_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET avatar_key = \$1 WHERE id = \$2`).
+		WithArgs("avatars/1/def", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewUserRepository(db, db)
+
+	err = repo.UpdateAvatarKey("avatars/1/def", 1)
+	assert.NoError(t, err)
+}
+
+func TestSetAdmin_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET is_admin = \$1 WHERE id = \$2`).
+		WithArgs(true, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewUserRepository(db, db)
+
+	err = repo.SetAdmin(1, true)
+	assert.NoError(t, err)
+}
+
+func TestUpdatePassword_UserNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET password = \$1 WHERE email = \$2`).
+		WithArgs("hash", "nonexistent@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := repository.NewUserRepository(db, db)
+
+	err = repo.UpdatePassword("nonexistent@example.com", "hash")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
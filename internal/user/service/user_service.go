@@ -1,46 +1,125 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
 	"os"
+	"slices"
 	"time"
 
 	"errors"
 
 	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/model"
 	userRepo "github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	imageresize "github.com/ahmadammarm/go-rest-api-template/pkg/image-resize"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrNotFound is returned for a user no row has.
+	ErrNotFound = userRepo.ErrNotFound
+	// ErrInvalidPassword is returned when logging in with a wrong password.
+	ErrInvalidPassword = userRepo.ErrInvalidPassword
+	// ErrEmailExists is returned for an email another user has.
+	ErrEmailExists = errors.New("email already exists")
+)
+
 type UserService interface {
 	RegisterUser(user *userDTO.UserRegisterRequest) error
+	// CreateUser registers user, as an admin when admin is set. It is what
+	// the command line creates accounts with.
+	CreateUser(user *userDTO.UserRegisterRequest, admin bool) error
+	// ResetPassword replaces the password of the user registered with
+	// email.
+	ResetPassword(email string, password string) error
 	LoginUser(user *userDTO.UserLoginRequest) (any, error)
 	UpdateUser(user *userDTO.UserUpdateRequest, id int) error
 	GetUserByID(userId int) (*userDTO.UserResponse, error)
+	GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error)
 	UserList() (*userDTO.UserListResponse, error)
+	UpdateProfile(profile *userDTO.UserProfileRequest, id int) error
+	UpdateAvatar(id int, body io.Reader) (*userDTO.UserResponse, error)
+	OpenAvatar(userId int, size int) (io.ReadCloser, error)
 }
 
+// avatarMaxSize is the largest avatar upload accepted, before resizing.
+const avatarMaxSize = 5 << 20
+
+// avatarMaxSide bounds the dimensions of an avatar upload, checked before it
+// is decoded: a small file can declare an image that would not fit in memory.
+const avatarMaxSide = 4096
+
 type userServiceImpl struct {
-	userRepo  userRepo.UserRepo
-	jwtSecret string
+	userRepo      userRepo.UserRepo
+	avatarStorage storage.Storage
+	transactions  *database.TxManager
+	jwtSecret     string
 }
 
-func NewUserService(userRepo userRepo.UserRepo) UserService {
+// NewUserService builds the service. Without transactions, checks and the
+// changes they guard are not made atomically.
+func NewUserService(userRepo userRepo.UserRepo, avatarStorage storage.Storage, transactions *database.TxManager) UserService {
 	return &userServiceImpl{
-		userRepo:  userRepo,
-		jwtSecret: os.Getenv("JWT_SECRET_KEY"),
+		userRepo:      userRepo,
+		avatarStorage: avatarStorage,
+		transactions:  transactions,
+		jwtSecret:     os.Getenv("JWT_SECRET_KEY"),
+	}
+}
+
+// serializable runs fn with the repository bound to a serializable
+// transaction, so that a concurrent change invalidating what fn read makes
+// it run again rather than commit.
+func (service *userServiceImpl) serializable(fn func(repo userRepo.UserRepo) error) error {
+	if service.transactions == nil {
+		return fn(service.userRepo)
 	}
+
+	return service.transactions.RunWith(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *database.Tx) error {
+		return fn(service.userRepo.WithTx(tx))
+	})
 }
 
 func (service *userServiceImpl) RegisterUser(user *userDTO.UserRegisterRequest) error {
+	return service.CreateUser(user, false)
+}
+
+func (service *userServiceImpl) CreateUser(user *userDTO.UserRegisterRequest, admin bool) error {
+	return service.serializable(func(repo userRepo.UserRepo) error {
+		if exists, err := repo.IsEmailExists(user.Email); err != nil {
+			return err
+		} else if exists {
+			return ErrEmailExists
+		}
+
+		if err := repo.RegisterUser(user); err != nil {
+			return err
+		}
+
+		if admin {
+			return repo.SetAdmin(user.ID, true)
+		}
+		return nil
+	})
+}
 
-	if exists, err := service.userRepo.IsEmailExists(user.Email); err != nil {
+func (service *userServiceImpl) ResetPassword(email string, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
 		return err
-	} else if exists {
-		return errors.New("email already exists")
 	}
 
-	return service.userRepo.RegisterUser(user)
+	return service.userRepo.UpdatePassword(email, string(hashedPassword))
 }
 
 func (service *userServiceImpl) LoginUser(user *userDTO.UserLoginRequest) (any, error) {
@@ -49,38 +128,27 @@ func (service *userServiceImpl) LoginUser(user *userDTO.UserLoginRequest) (any,
 		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"apps":    "go-rest-api-template",
-		"user_id": dbUser.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	})
-
 	if service.jwtSecret == "" {
 		return "", errors.New("JWT_SECRET is not set in environment variables")
 	}
 
-	stringToken, err := token.SignedString([]byte(service.jwtSecret))
+	stringToken, err := token.Issue(service.jwtSecret, dbUser.ID, dbUser.IsAdmin, time.Hour*24)
 	if err != nil {
 		return "", err
 	}
 
 	response := userDTO.UserJWTResponse{
-		ID:    dbUser.ID,
-		Name:  dbUser.Name,
-		Email: dbUser.Email,
-		Token: stringToken,
+		ID:      dbUser.ID,
+		Name:    dbUser.Name,
+		Email:   dbUser.Email,
+		IsAdmin: dbUser.IsAdmin,
+		Token:   stringToken,
 	}
 
 	return response, nil
 }
 
 func (service *userServiceImpl) UpdateUser(user *userDTO.UserUpdateRequest, id int) error {
-	if exists, err := service.userRepo.IsEmailTakenByOther(user.Email, id); err != nil {
-		return err
-	} else if exists {
-		return errors.New("email already exists")
-	}
-
 	var hashedPassword string
 	if user.Password != "" {
 		hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
@@ -90,7 +158,15 @@ func (service *userServiceImpl) UpdateUser(user *userDTO.UserUpdateRequest, id i
 		hashedPassword = string(hash)
 	}
 
-	return service.userRepo.UpdateUser(user.Name, user.Email, hashedPassword, id)
+	return service.serializable(func(repo userRepo.UserRepo) error {
+		if exists, err := repo.IsEmailTakenByOther(user.Email, id); err != nil {
+			return err
+		} else if exists {
+			return ErrEmailExists
+		}
+
+		return repo.UpdateUser(user.Name, user.Email, hashedPassword, id)
+	})
 }
 
 func (service *userServiceImpl) GetUserByID(userId int) (*userDTO.UserResponse, error) {
@@ -102,6 +178,10 @@ func (service *userServiceImpl) GetUserByID(userId int) (*userDTO.UserResponse,
 	return user, nil
 }
 
+func (service *userServiceImpl) GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error) {
+	return service.userRepo.GetUsersByIDs(userIds)
+}
+
 func (service *userServiceImpl) UserList() (*userDTO.UserListResponse, error) {
 	users, err := service.userRepo.UserList()
 	if err != nil {
@@ -110,3 +190,91 @@ func (service *userServiceImpl) UserList() (*userDTO.UserListResponse, error) {
 
 	return users, nil
 }
+
+func (service *userServiceImpl) UpdateProfile(profile *userDTO.UserProfileRequest, id int) error {
+	return service.userRepo.UpdateProfile(profile, id)
+}
+
+// UpdateAvatar validates an uploaded image, stores it in every size of
+// model.AvatarSizes and then removes the previous avatar.
+func (service *userServiceImpl) UpdateAvatar(id int, body io.Reader) (*userDTO.UserResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(body, avatarMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > avatarMaxSize {
+		return nil, errors.New("file too large")
+	}
+
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return nil, errors.New("invalid image")
+	}
+
+	img, err := imageresize.DecodeWithin(data, avatarMaxSide, avatarMaxSide*avatarMaxSide)
+	if errors.Is(err, imageresize.ErrTooLarge) {
+		return nil, errors.New("file too large")
+	}
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+
+	oldAvatarKey, err := service.userRepo.GetAvatarKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	avatarKey := fmt.Sprintf("avatars/%d/%s", id, uuid.NewString())
+
+	for _, size := range model.AvatarSizes {
+		resized, err := imageresize.EncodeJPEG(imageresize.Square(img, size))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := service.avatarStorage.Put(ctx, model.AvatarObjectKey(avatarKey, size), bytes.NewReader(resized), int64(len(resized)), "image/jpeg"); err != nil {
+			service.deleteAvatar(avatarKey)
+			return nil, fmt.Errorf("error storing avatar: %w", err)
+		}
+	}
+
+	if err := service.userRepo.UpdateAvatarKey(avatarKey, id); err != nil {
+		service.deleteAvatar(avatarKey)
+		return nil, err
+	}
+
+	service.deleteAvatar(oldAvatarKey)
+
+	return service.userRepo.GetUserByID(id)
+}
+
+func (service *userServiceImpl) deleteAvatar(avatarKey string) {
+	if avatarKey == "" {
+		return
+	}
+
+	for _, size := range model.AvatarSizes {
+		if err := service.avatarStorage.Delete(context.Background(), model.AvatarObjectKey(avatarKey, size)); err != nil {
+			log.Printf("Error deleting avatar %q: %v", avatarKey, err)
+		}
+	}
+}
+
+func (service *userServiceImpl) OpenAvatar(userId int, size int) (io.ReadCloser, error) {
+	if !slices.Contains(model.AvatarSizes, size) {
+		return nil, errors.New("invalid avatar size")
+	}
+
+	avatarKey, err := service.userRepo.GetAvatarKey(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if avatarKey == "" {
+		return nil, errors.New("avatar not found")
+	}
+
+	return service.avatarStorage.Open(context.Background(), model.AvatarObjectKey(avatarKey, size))
+}
@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+
+	userDTO "github.com/ahmadammarm/go-rest-api-template/internal/user/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/repository"
+	"github.com/ahmadammarm/go-rest-api-template/internal/user/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+)
+
+type MockUserRepo struct {
+	mock.Mock
+}
+
+// WithTx returns the mock itself, which expects the calls made in tx.
+func (m *MockUserRepo) WithTx(tx *database.Tx) repository.UserRepo {
+	return m
+}
+
+func (m *MockUserRepo) RegisterUser(user *userDTO.UserRegisterRequest) error {
+	return m.Called(user).Error(0)
+}
+
+func (m *MockUserRepo) LoginUser(user *userDTO.UserLoginRequest) (*userDTO.UserJWTResponse, error) {
+	args := m.Called(user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDTO.UserJWTResponse), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateUser(name string, email string, hashedPassword string, id int) error {
+	return m.Called(name, email, hashedPassword, id).Error(0)
+}
+
+func (m *MockUserRepo) GetUserByID(userId int) (*userDTO.UserResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDTO.UserResponse), args.Error(1)
+}
+
+func (m *MockUserRepo) GetUsersByIDs(userIds []int) ([]userDTO.UserResponse, error) {
+	args := m.Called(userIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]userDTO.UserResponse), args.Error(1)
+}
+
+func (m *MockUserRepo) IsEmailExists(email string) (bool, error) {
+	args := m.Called(email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) IsEmailTakenByOther(email string, id int) (bool, error) {
+	args := m.Called(email, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) UserList() (*userDTO.UserListResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDTO.UserListResponse), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateProfile(profile *userDTO.UserProfileRequest, id int) error {
+	return m.Called(profile, id).Error(0)
+}
+
+func (m *MockUserRepo) GetAvatarKey(id int) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateAvatarKey(avatarKey string, id int) error {
+	return m.Called(avatarKey, id).Error(0)
+}
+
+func (m *MockUserRepo) SetAdmin(id int, admin bool) error {
+	return m.Called(id, admin).Error(0)
+}
+
+func (m *MockUserRepo) UpdatePassword(email string, hashedPassword string) error {
+	return m.Called(email, hashedPassword).Error(0)
+}
+
+func newAvatarStorage(t *testing.T) *storage.LocalStorage {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	assert.NoError(t, err)
+	return store
+}
+
+func TestUpdateAvatar(t *testing.T) {
+	t.Run("stores every size and removes the old avatar", func(t *testing.T) {
+		store := newAvatarStorage(t)
+		mockRepo := new(MockUserRepo)
+		userService := service.NewUserService(mockRepo, store, nil)
+		ctx := context.Background()
+
+		for _, size := range model.AvatarSizes {
+			assert.NoError(t, store.Put(ctx, model.AvatarObjectKey("avatars/1/old", size), strings.NewReader("old"), 3, "image/jpeg"))
+		}
+
+		var newAvatarKey string
+		mockRepo.On("GetAvatarKey", 1).Return("avatars/1/old", nil).Once()
+		mockRepo.On("UpdateAvatarKey", mock.AnythingOfType("string"), 1).Run(func(args mock.Arguments) {
+			newAvatarKey = args.String(0)
+		}).Return(nil).Once()
+		mockRepo.On("GetUserByID", 1).Return(&userDTO.UserResponse{ID: 1}, nil).Once()
+
+		var upload bytes.Buffer
+		assert.NoError(t, png.Encode(&upload, image.NewRGBA(image.Rect(0, 0, 300, 200))))
+
+		user, err := userService.UpdateAvatar(1, &upload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+
+		for _, size := range model.AvatarSizes {
+			file, err := store.Open(ctx, model.AvatarObjectKey(newAvatarKey, size))
+			assert.NoError(t, err)
+			avatar, _, err := image.Decode(file)
+			file.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, image.Pt(size, size), avatar.Bounds().Size())
+
+			_, err = store.Open(ctx, model.AvatarObjectKey("avatars/1/old", size))
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects non images", func(t *testing.T) {
+		userService := service.NewUserService(new(MockUserRepo), newAvatarStorage(t), nil)
+
+		user, err := userService.UpdateAvatar(1, strings.NewReader("not an image"))
+
+		assert.Error(t, err)
+		assert.Equal(t, "invalid image", err.Error())
+		assert.Nil(t, user)
+	})
+
+	t.Run("rejects oversized dimensions before decoding", func(t *testing.T) {
+		userService := service.NewUserService(new(MockUserRepo), newAvatarStorage(t), nil)
+
+		// The header of a PNG declaring 50000x50000 pixels, without any.
+		ihdr := binary.BigEndian.AppendUint32([]byte("IHDR"), 50000)
+		ihdr = binary.BigEndian.AppendUint32(ihdr, 50000)
+		ihdr = append(ihdr, 8, 6, 0, 0, 0)
+		header := binary.BigEndian.AppendUint32([]byte("\x89PNG\r\n\x1a\n"), uint32(len(ihdr)-4))
+		header = binary.BigEndian.AppendUint32(append(header, ihdr...), crc32.ChecksumIEEE(ihdr))
+
+		user, err := userService.UpdateAvatar(1, bytes.NewReader(header))
+
+		assert.Error(t, err)
+		assert.Equal(t, "file too large", err.Error())
+		assert.Nil(t, user)
+	})
+}
+
+func TestOpenAvatar(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	userService := service.NewUserService(mockRepo, newAvatarStorage(t), nil)
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := userService.OpenAvatar(1, 100)
+		assert.Equal(t, "invalid avatar size", err.Error())
+	})
+
+	t.Run("user without avatar", func(t *testing.T) {
+		mockRepo.On("GetAvatarKey", 2).Return("", nil).Once()
+
+		_, err := userService.OpenAvatar(2, 64)
+		assert.Equal(t, "avatar not found", err.Error())
+	})
+}
+
+func TestCreateUser(t *testing.T) {
+	t.Run("creates an admin", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		userService := service.NewUserService(mockRepo, nil, nil)
+		user := &userDTO.UserRegisterRequest{Email: "admin@mail.com", Name: "Admin", Password: "password123"}
+
+		mockRepo.On("IsEmailExists", "admin@mail.com").Return(false, nil).Once()
+		mockRepo.On("RegisterUser", user).Run(func(args mock.Arguments) {
+			args.Get(0).(*userDTO.UserRegisterRequest).ID = 7
+		}).Return(nil).Once()
+		mockRepo.On("SetAdmin", 7, true).Return(nil).Once()
+
+		assert.NoError(t, userService.CreateUser(user, true))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		userService := service.NewUserService(mockRepo, nil, nil)
+
+		mockRepo.On("IsEmailExists", "admin@mail.com").Return(true, nil).Once()
+
+		err := userService.RegisterUser(&userDTO.UserRegisterRequest{Email: "admin@mail.com"})
+		assert.EqualError(t, err, "email already exists")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	userService := service.NewUserService(mockRepo, nil, nil)
+
+	var hashedPassword string
+	mockRepo.On("UpdatePassword", "admin@mail.com", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		hashedPassword = args.String(1)
+	}).Return(nil).Once()
+
+	assert.NoError(t, userService.ResetPassword("admin@mail.com", "new-password"))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte("new-password")))
+}
@@ -0,0 +1,39 @@
+package dependency_injection
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ahmadammarm/go-rest-api-template/config"
+	newsDTO "github.com/ahmadammarm/go-rest-api-template/internal/news/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/handler"
+	webhookRepository "github.com/ahmadammarm/go-rest-api-template/internal/webhook/repository"
+	webhookService "github.com/ahmadammarm/go-rest-api-template/internal/webhook/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+	"github.com/go-playground/validator/v10"
+)
+
+func InitializeWebhook(db *sql.DB, validator *validator.Validate) *handler.WebhookHandler {
+	webhookRepo := webhookRepository.NewWebhookRepository(db)
+	webhookService := webhookService.NewWebhookService(webhookRepo)
+
+	return handler.NewWebhookHandler(webhookService, validator)
+}
+
+// NewWorker builds the worker sending webhook deliveries, configured by the
+// WEBHOOK_* variables.
+func NewWorker(db *sql.DB) *webhookService.Worker {
+	return webhookService.NewWorker(webhookRepository.NewWebhookRepository(db), config.WebhookWorker())
+}
+
+// SubscribeNews queues, from the news events of bus, a delivery for every
+// webhook subscribed to them.
+func SubscribeNews(db *sql.DB, bus *outbox.Bus) {
+	webhookRepo := webhookRepository.NewWebhookRepository(db)
+
+	for _, eventType := range []string{newsDTO.EventCreated, newsDTO.EventUpdated, newsDTO.EventPublished, newsDTO.EventUnpublished, newsDTO.EventDeleted} {
+		bus.Subscribe(eventType, "webhooks.enqueue", func(ctx context.Context, event outbox.Event) error {
+			return webhookRepo.Enqueue(event.ID, event.Type, event.Payload)
+		})
+	}
+}
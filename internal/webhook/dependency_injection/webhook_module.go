@@ -0,0 +1,55 @@
+package dependency_injection
+
+import (
+	"context"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/handler"
+	webhookService "github.com/ahmadammarm/go-rest-api-template/internal/webhook/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+)
+
+// Module manages the webhooks, queues and sends their deliveries. Its tables
+// are Postgres only: on SQLite, it does nothing.
+type Module struct {
+	app.Base
+	handler *handler.WebhookHandler
+	worker  *webhookService.Worker
+	runner  app.Runner
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (module *Module) Name() string {
+	return "webhook"
+}
+
+func (module *Module) Init(c *app.Container) error {
+	if !c.Config.Postgres() {
+		return nil
+	}
+
+	module.handler = InitializeWebhook(c.DB, c.Validator)
+	SubscribeNews(c.DB, c.Bus)
+	module.worker = NewWorker(c.DB)
+	return nil
+}
+
+func (module *Module) RegisterRoutes(routes app.Routes) {
+	if module.handler == nil {
+		return
+	}
+	routes.Admin.Add(module.handler.WebhookRouters, module.handler.WebhookOperations())
+}
+
+func (module *Module) Start(ctx context.Context) error {
+	if module.worker != nil {
+		module.runner.Start(ctx, module.worker.Run)
+	}
+	return nil
+}
+
+func (module *Module) Stop(ctx context.Context) error {
+	return module.runner.Stop(ctx)
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "encoding/json"
+
+// Request body
+type SubscriptionRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048"`
+	// Secret signs the deliveries. One is generated when it is omitted.
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=news.created news.updated news.published news.unpublished news.deleted"`
+	// Active defaults to true. Inactive subscriptions get no deliveries.
+	Active *bool `json:"active"`
+}
+
+// Response body
+type SubscriptionResponse struct {
+	ID         int      `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	// Secret is only returned when the subscription is created.
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type SubscriptionListResponse struct {
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+	Total         int                    `json:"total"`
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionId int             `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at"`
+	LastAttemptAt  string          `json:"last_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+}
+
+type DeliveryListResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+	Total      int                `json:"total"`
+}
+
+// Payload is the JSON body POSTed to subscribers.
+type Payload struct {
+	// ID is the delivery ID. It is the same for every attempt, so receivers
+	// can drop duplicates.
+	ID        int64           `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
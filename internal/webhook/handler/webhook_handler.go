@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/dto"
+	webhookService "github.com/ahmadammarm/go-rest-api-template/internal/webhook/service"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookService webhookService.WebhookService
+	validation     *validator.Validate
+}
+
+// notFound answers the errors of unknown webhooks and deliveries.
+func notFound(context *fiber.Ctx, err error) error {
+	if errors.Is(err, webhookService.ErrNotFound) || errors.Is(err, webhookService.ErrDeliveryNotFound) {
+		return response.JSONResponse(context, 404, "Not Found", nil)
+	}
+	log.Println("Error handling webhook request:", err)
+	return response.JSONResponse(context, 500, "Internal Server Error", nil)
+}
+
+func (handler *WebhookHandler) parseRequest(context *fiber.Ctx) (*dto.SubscriptionRequest, error) {
+	var request dto.SubscriptionRequest
+	if err := context.BodyParser(&request); err != nil {
+		log.Println("Error parsing webhook request body:", err)
+		return nil, response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	if err := handler.validation.Struct(request); err != nil {
+		log.Println("Validation error for webhook:", err)
+		return nil, response.JSONResponse(context, 422, "Validation Error", nil)
+	}
+
+	return &request, nil
+}
+
+var webhookResource = crud.Resource[int]{
+	Name:     "webhook",
+	ParseID:  crud.IntID,
+	NotFound: webhookService.ErrNotFound,
+}
+
+func (handler *WebhookHandler) GetWebhooks(context *fiber.Ctx) error {
+	return crud.List(webhookResource, "Success", handler.webhookService.GetSubscriptions)(context)
+}
+
+func (handler *WebhookHandler) CreateWebhook(context *fiber.Ctx) error {
+	request, err := handler.parseRequest(context)
+	if request == nil {
+		return err
+	}
+
+	subscription, err := handler.webhookService.CreateSubscription(request)
+	if err != nil {
+		log.Println("Error creating webhook:", err)
+		return response.JSONResponse(context, 500, "Internal Server Error", nil)
+	}
+
+	return response.JSONResponse(context, 201, "Created", subscription)
+}
+
+func (handler *WebhookHandler) GetWebhookByID(context *fiber.Ctx) error {
+	return crud.Get(webhookResource, "Success", handler.webhookService.GetSubscriptionByID)(context)
+}
+
+func (handler *WebhookHandler) UpdateWebhook(context *fiber.Ctx) error {
+	id, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	request, err := handler.parseRequest(context)
+	if request == nil {
+		return err
+	}
+
+	subscription, err := handler.webhookService.UpdateSubscription(id, request)
+	if err != nil {
+		return notFound(context, err)
+	}
+
+	return response.JSONResponse(context, 200, "Success", subscription)
+}
+
+func (handler *WebhookHandler) DeleteWebhook(context *fiber.Ctx) error {
+	return crud.Delete(webhookResource, "Success", handler.webhookService.DeleteSubscription)(context)
+}
+
+func (handler *WebhookHandler) GetDeliveries(context *fiber.Ctx) error {
+	id, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	deliveries, err := handler.webhookService.GetDeliveries(id)
+	if err != nil {
+		return notFound(context, err)
+	}
+
+	return response.JSONResponse(context, 200, "Success", deliveries)
+}
+
+func (handler *WebhookHandler) Redeliver(context *fiber.Ctx) error {
+	id, err := strconv.Atoi(context.Params("id"))
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	deliveryId, err := strconv.ParseInt(context.Params("deliveryId"), 10, 64)
+	if err != nil {
+		return response.JSONResponse(context, 400, "Bad Request", nil)
+	}
+
+	if err := handler.webhookService.Redeliver(id, deliveryId); err != nil {
+		return notFound(context, err)
+	}
+
+	return response.JSONResponse(context, 202, "Accepted", nil)
+}
+
+// WebhookRouters registers the webhook management routes. They are meant for
+// a group using JWTAuth and RequireAdmin.
+func (handler *WebhookHandler) WebhookRouters(router fiber.Router) {
+	router.Get("/webhooks", handler.GetWebhooks)
+	router.Post("/webhooks", handler.CreateWebhook)
+	router.Get("/webhooks/:id", handler.GetWebhookByID)
+	router.Put("/webhooks/:id", handler.UpdateWebhook)
+	router.Delete("/webhooks/:id", handler.DeleteWebhook)
+	router.Get("/webhooks/:id/deliveries", handler.GetDeliveries)
+	router.Post("/webhooks/:id/deliveries/:deliveryId/redeliver", handler.Redeliver)
+}
+
+func NewWebhookHandler(webhookService webhookService.WebhookService, validation *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validation:     validation,
+	}
+}
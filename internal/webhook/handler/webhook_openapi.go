@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/dto"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+var webhookTags = []string{"Webhooks"}
+
+// WebhookOperations documents the routes of WebhookRouters.
+func (handler *WebhookHandler) WebhookOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/webhooks", Summary: "Get all webhooks", Tags: webhookTags, Security: openapi.BearerAuth,
+			Response: dto.SubscriptionListResponse{}, Errors: []int{403, 500}},
+		{Method: "POST", Path: "/webhooks", Summary: "Create a webhook, returning its secret", Tags: webhookTags, Security: openapi.BearerAuth,
+			Request: dto.SubscriptionRequest{}, Response: dto.SubscriptionResponse{}, Status: 201, Errors: []int{400, 403, 422, 500}},
+		{Method: "GET", Path: "/webhooks/:id", Summary: "Get a webhook by id", Tags: webhookTags, Security: openapi.BearerAuth,
+			Response: dto.SubscriptionResponse{}, Errors: []int{400, 403, 404, 500}},
+		{Method: "PUT", Path: "/webhooks/:id", Summary: "Edit a webhook", Tags: webhookTags, Security: openapi.BearerAuth,
+			Request: dto.SubscriptionRequest{}, Response: dto.SubscriptionResponse{}, Errors: []int{400, 403, 404, 422, 500}},
+		{Method: "DELETE", Path: "/webhooks/:id", Summary: "Delete a webhook and its deliveries", Tags: webhookTags, Security: openapi.BearerAuth,
+			Errors: []int{400, 403, 404, 500}},
+		{Method: "GET", Path: "/webhooks/:id/deliveries", Summary: "Get the latest deliveries of a webhook", Tags: webhookTags, Security: openapi.BearerAuth,
+			Response: dto.DeliveryListResponse{}, Errors: []int{400, 403, 404, 500}},
+		{Method: "POST", Path: "/webhooks/:id/deliveries/:deliveryId/redeliver", Summary: "Send a delivery again", Tags: webhookTags, Security: openapi.BearerAuth,
+			Status: 202, Errors: []int{400, 403, 404, 500}},
+	}
+}
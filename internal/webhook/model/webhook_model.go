@@ -0,0 +1,40 @@
+package model
+
+import "encoding/json"
+
+type Subscription struct {
+	ID         int      `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	// StatusFailed is final: every attempt failed. Redelivering makes it
+	// pending again.
+	StatusFailed = "failed"
+)
+
+type Delivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionId int             `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at"`
+	LastAttemptAt  string          `json:"last_attempt_at"`
+	ResponseStatus int             `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      string          `json:"created_at"`
+
+	// Set on deliveries claimed for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/model"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(subscription *model.Subscription) error
+	GetSubscriptions() ([]model.Subscription, error)
+	GetSubscriptionByID(id int) (*model.Subscription, error)
+	UpdateSubscription(subscription *model.Subscription) error
+	DeleteSubscription(id int) error
+	Enqueue(eventId int64, eventType string, payload json.RawMessage) error
+	GetDeliveries(subscriptionId int, limit int) ([]model.Delivery, error)
+	Redeliver(subscriptionId int, deliveryId int64) error
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]model.Delivery, error)
+	RecordAttempt(delivery *model.Delivery, retryIn time.Duration) error
+}
+
+type webhookRepository struct {
+	subscriptions *crud.Repository[model.Subscription, int]
+	db            *sql.DB
+}
+
+// ErrNotFound is returned for a webhook ID no subscription has.
+var ErrNotFound = errors.New("webhook not found")
+
+// ErrDeliveryNotFound is returned for a delivery ID the webhook has none of.
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+var subscriptionTable = crud.Table[model.Subscription]{
+	Name:     "webhook_subscriptions",
+	Key:      "id",
+	Columns:  []string{"id", "url", "secret", "event_types", "active", "created_at", "updated_at"},
+	OrderBy:  "id",
+	Scan:     scanSubscription,
+	NotFound: ErrNotFound,
+}
+
+func scanSubscription(scanner crud.Scanner, subscription *model.Subscription) error {
+	return scanner.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, pq.Array(&subscription.EventTypes),
+		&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt)
+}
+
+const deliveryColumns = `id, subscription_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+              response_status, last_error, created_at`
+
+func scanDelivery(scanner interface{ Scan(dest ...any) error }, delivery *model.Delivery) error {
+	var lastAttemptAt sql.NullString
+	var responseStatus sql.NullInt64
+
+	err := scanner.Scan(&delivery.ID, &delivery.SubscriptionId, &delivery.EventType, &delivery.Payload, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &lastAttemptAt, &responseStatus, &delivery.LastError, &delivery.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	delivery.LastAttemptAt = lastAttemptAt.String
+	delivery.ResponseStatus = int(responseStatus.Int64)
+
+	return nil
+}
+
+func (repo *webhookRepository) CreateSubscription(subscription *model.Subscription) error {
+	query := `INSERT INTO webhook_subscriptions (url, secret, event_types, active)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+
+	return repo.db.QueryRow(query, subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active).
+		Scan(&subscription.ID, &subscription.CreatedAt, &subscription.UpdatedAt)
+}
+
+func (repo *webhookRepository) GetSubscriptions() ([]model.Subscription, error) {
+	return repo.subscriptions.All()
+}
+
+func (repo *webhookRepository) GetSubscriptionByID(id int) (*model.Subscription, error) {
+	return repo.subscriptions.Get(id)
+}
+
+func (repo *webhookRepository) UpdateSubscription(subscription *model.Subscription) error {
+	query := `UPDATE webhook_subscriptions SET url = $1, secret = $2, event_types = $3, active = $4, updated_at = NOW()
+              WHERE id = $5 RETURNING created_at, updated_at`
+
+	err := repo.db.QueryRow(query, subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active, subscription.ID).
+		Scan(&subscription.CreatedAt, &subscription.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (repo *webhookRepository) DeleteSubscription(id int) error {
+	return repo.subscriptions.Delete(id)
+}
+
+// Enqueue adds a delivery of the outbox event eventId for every active
+// subscription to it. An event dispatched again adds no deliveries for the
+// subscriptions that already have one.
+func (repo *webhookRepository) Enqueue(eventId int64, eventType string, payload json.RawMessage) error {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+              SELECT id, $1, $2, $3 FROM webhook_subscriptions WHERE active AND $2 = ANY(event_types)
+              ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	_, err := repo.db.Exec(query, eventId, eventType, []byte(payload))
+	return err
+}
+
+func (repo *webhookRepository) GetDeliveries(subscriptionId int, limit int) ([]model.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+              WHERE subscription_id = $1 ORDER BY id DESC LIMIT $2`
+
+	rows, err := repo.db.Query(query, subscriptionId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.Delivery{}
+	for rows.Next() {
+		var delivery model.Delivery
+		if err := scanDelivery(rows, &delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver makes a delivery due now, with a fresh set of attempts.
+func (repo *webhookRepository) Redeliver(subscriptionId int, deliveryId int64) error {
+	query := `UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+              WHERE id = $1 AND subscription_id = $2`
+
+	result, err := repo.db.Exec(query, deliveryId, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries that are due,
+// with the URL and secret of their subscription. They are pushed lease into
+// the future, so that other workers skip them meanwhile and a worker that
+// dies while sending leaves them to be retried.
+func (repo *webhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]model.Delivery, error) {
+	query := `UPDATE webhook_deliveries d SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+              FROM webhook_subscriptions s
+              WHERE s.id = d.subscription_id AND d.id IN (
+                  SELECT due.id FROM webhook_deliveries due
+                  JOIN webhook_subscriptions active ON active.id = due.subscription_id AND active.active
+                  WHERE due.status = 'pending' AND due.next_attempt_at <= NOW()
+                  ORDER BY due.next_attempt_at, due.id
+                  LIMIT $1
+                  FOR UPDATE OF due SKIP LOCKED)
+              RETURNING d.id, d.subscription_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+                  d.last_attempt_at, d.response_status, d.last_error, d.created_at, s.url, s.secret`
+
+	rows, err := repo.db.Query(query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.Delivery{}
+	for rows.Next() {
+		var delivery model.Delivery
+		var lastAttemptAt sql.NullString
+		var responseStatus sql.NullInt64
+
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionId, &delivery.EventType, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &lastAttemptAt, &responseStatus, &delivery.LastError, &delivery.CreatedAt,
+			&delivery.URL, &delivery.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.LastAttemptAt = lastAttemptAt.String
+		delivery.ResponseStatus = int(responseStatus.Int64)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the Status, Attempts, ResponseStatus and LastError of
+// an attempt made now. A pending delivery is due again in retryIn.
+func (repo *webhookRepository) RecordAttempt(delivery *model.Delivery, retryIn time.Duration) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, response_status = $3, last_error = $4,
+              last_attempt_at = NOW(), next_attempt_at = NOW() + $5 * INTERVAL '1 millisecond' WHERE id = $6`
+
+	var responseStatus any
+	if delivery.ResponseStatus != 0 {
+		responseStatus = delivery.ResponseStatus
+	}
+
+	_, err := repo.db.Exec(query, delivery.Status, delivery.Attempts, responseStatus, delivery.LastError,
+		retryIn.Milliseconds(), delivery.ID)
+	return err
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{subscriptions: crud.New[model.Subscription, int](db, subscriptionTable), db: db}
+}
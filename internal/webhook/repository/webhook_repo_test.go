@@ -0,0 +1,142 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubscriptionByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewWebhookRepository(db)
+	columns := []string{"id", "url", "secret", "event_types", "active", "created_at", "updated_at"}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id = \\$1").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "https://example.com/hook", "secret", []byte("{news.created,news.deleted}"), true, time.Now(), time.Now()))
+
+		subscription, err := repo.GetSubscriptionByID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"news.created", "news.deleted"}, subscription.EventTypes)
+		assert.True(t, subscription.Active)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetSubscriptionByID(2)
+		assert.EqualError(t, err, "webhook not found")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewWebhookRepository(db)
+
+	mock.ExpectExec("INSERT INTO webhook_deliveries \\(subscription_id, event_id, event_type, payload\\)\\s+SELECT id, \\$1, \\$2, \\$3 FROM webhook_subscriptions WHERE active AND \\$2 = ANY\\(event_types\\)\\s+ON CONFLICT \\(subscription_id, event_id\\) DO NOTHING").
+		WithArgs(int64(7), "news.deleted", []byte(`{"id":3}`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.Enqueue(7, "news.deleted", []byte(`{"id":3}`)))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewWebhookRepository(db)
+
+	columns := []string{"id", "subscription_id", "event_type", "payload", "status", "attempts", "next_attempt_at",
+		"last_attempt_at", "response_status", "last_error", "created_at", "url", "secret"}
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET next_attempt_at = (.+) FOR UPDATE OF due SKIP LOCKED\\)").
+		WithArgs(10, int64(20000)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(7, 1, "news.created", []byte(`{"id":3}`), "pending", 2, time.Now(), time.Now(), 500, "unexpected status 500", time.Now(), "https://example.com/hook", "secret").
+			AddRow(8, 1, "news.deleted", []byte(`{"id":4}`), "pending", 0, time.Now(), nil, nil, "", time.Now(), "https://example.com/hook", "secret"))
+
+	deliveries, err := repo.ClaimDueDeliveries(10, 20*time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, 500, deliveries[0].ResponseStatus)
+	assert.Equal(t, "https://example.com/hook", deliveries[0].URL)
+	assert.Equal(t, "secret", deliveries[1].Secret)
+	assert.Equal(t, 0, deliveries[1].ResponseStatus)
+	assert.Empty(t, deliveries[1].LastAttemptAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewWebhookRepository(db)
+
+	t.Run("retry", func(t *testing.T) {
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$1, attempts = \\$2, response_status = \\$3").
+			WithArgs(model.StatusPending, 1, 503, "unexpected status 503", int64(30000), int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.RecordAttempt(&model.Delivery{ID: 7, Status: model.StatusPending, Attempts: 1, ResponseStatus: 503,
+			LastError: "unexpected status 503"}, 30*time.Second)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$1, attempts = \\$2, response_status = \\$3").
+			WithArgs(model.StatusFailed, 8, nil, "connection refused", int64(0), int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.RecordAttempt(&model.Delivery{ID: 7, Status: model.StatusFailed, Attempts: 8, LastError: "connection refused"}, 0)
+		assert.NoError(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeliver(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewWebhookRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW\\(\\)").
+			WithArgs(int64(7), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Redeliver(1, 7))
+	})
+
+	t.Run("delivery of another webhook", func(t *testing.T) {
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = 'pending'").
+			WithArgs(int64(7), 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.EqualError(t, repo.Redeliver(2, 7), "delivery not found")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/model"
+	webhookRepo "github.com/ahmadammarm/go-rest-api-template/internal/webhook/repository"
+)
+
+// The errors of unknown webhooks and deliveries.
+var (
+	ErrNotFound         = webhookRepo.ErrNotFound
+	ErrDeliveryNotFound = webhookRepo.ErrDeliveryNotFound
+)
+
+// deliveryLogLimit is how many of the latest deliveries GetDeliveries returns.
+const deliveryLogLimit = 100
+
+type WebhookService interface {
+	CreateSubscription(request *dto.SubscriptionRequest) (*dto.SubscriptionResponse, error)
+	GetSubscriptions() (*dto.SubscriptionListResponse, error)
+	GetSubscriptionByID(id int) (*dto.SubscriptionResponse, error)
+	UpdateSubscription(id int, request *dto.SubscriptionRequest) (*dto.SubscriptionResponse, error)
+	DeleteSubscription(id int) error
+	GetDeliveries(subscriptionId int) (*dto.DeliveryListResponse, error)
+	Redeliver(subscriptionId int, deliveryId int64) error
+}
+
+type webhookServiceImpl struct {
+	webhookRepo webhookRepo.WebhookRepository
+}
+
+func toSubscriptionResponse(subscription *model.Subscription) *dto.SubscriptionResponse {
+	return &dto.SubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+// generateSecret returns 32 random bytes, hex encoded.
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func (service *webhookServiceImpl) CreateSubscription(request *dto.SubscriptionRequest) (*dto.SubscriptionResponse, error) {
+	subscription := &model.Subscription{
+		URL:        request.URL,
+		Secret:     request.Secret,
+		EventTypes: request.EventTypes,
+		Active:     request.Active == nil || *request.Active,
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("error generating webhook secret: %w", err)
+		}
+		subscription.Secret = secret
+	}
+
+	if err := service.webhookRepo.CreateSubscription(subscription); err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	log.Printf("Webhook %d created for %s", subscription.ID, subscription.URL)
+
+	response := toSubscriptionResponse(subscription)
+	response.Secret = subscription.Secret
+	return response, nil
+}
+
+func (service *webhookServiceImpl) GetSubscriptions() (*dto.SubscriptionListResponse, error) {
+	subscriptions, err := service.webhookRepo.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SubscriptionResponse, 0, len(subscriptions))
+	for i := range subscriptions {
+		responses = append(responses, *toSubscriptionResponse(&subscriptions[i]))
+	}
+
+	return &dto.SubscriptionListResponse{Subscriptions: responses, Total: len(responses)}, nil
+}
+
+func (service *webhookServiceImpl) GetSubscriptionByID(id int) (*dto.SubscriptionResponse, error) {
+	subscription, err := service.webhookRepo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscriptionResponse(subscription), nil
+}
+
+// UpdateSubscription replaces the URL and event types. The secret and the
+// active flag are kept when they are omitted.
+func (service *webhookServiceImpl) UpdateSubscription(id int, request *dto.SubscriptionRequest) (*dto.SubscriptionResponse, error) {
+	subscription, err := service.webhookRepo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.URL = request.URL
+	subscription.EventTypes = request.EventTypes
+	if request.Secret != "" {
+		subscription.Secret = request.Secret
+	}
+	if request.Active != nil {
+		subscription.Active = *request.Active
+	}
+
+	if err := service.webhookRepo.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return toSubscriptionResponse(subscription), nil
+}
+
+func (service *webhookServiceImpl) DeleteSubscription(id int) error {
+	return service.webhookRepo.DeleteSubscription(id)
+}
+
+// GetDeliveries returns the latest deliveries of a subscription, newest first.
+func (service *webhookServiceImpl) GetDeliveries(subscriptionId int) (*dto.DeliveryListResponse, error) {
+	if _, err := service.webhookRepo.GetSubscriptionByID(subscriptionId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := service.webhookRepo.GetDeliveries(subscriptionId, deliveryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, dto.DeliveryResponse{
+			ID:             delivery.ID,
+			SubscriptionId: delivery.SubscriptionId,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			LastAttemptAt:  delivery.LastAttemptAt,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+
+	return &dto.DeliveryListResponse{Deliveries: responses, Total: len(responses)}, nil
+}
+
+// Redeliver sends a delivery again as soon as the worker polls, whatever its
+// status, with a full set of attempts.
+func (service *webhookServiceImpl) Redeliver(subscriptionId int, deliveryId int64) error {
+	log.Printf("Redelivering webhook delivery %d...", deliveryId)
+	return service.webhookRepo.Redeliver(subscriptionId, deliveryId)
+}
+
+func NewWebhookService(webhookRepo webhookRepo.WebhookRepository) WebhookService {
+	log.Println("Initializing WebhookService...")
+	return &webhookServiceImpl{
+		webhookRepo: webhookRepo,
+	}
+}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/model"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/repository"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/service"
+)
+
+type MockWebhookRepository struct {
+	repository.WebhookRepository
+	mock.Mock
+	mu       sync.Mutex
+	recorded []model.Delivery
+	retries  []time.Duration
+}
+
+func (m *MockWebhookRepository) CreateSubscription(subscription *model.Subscription) error {
+	args := m.Called(subscription)
+	subscription.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscriptionByID(id int) (*model.Subscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Subscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateSubscription(subscription *model.Subscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]model.Delivery, error) {
+	args := m.Called(limit, lease)
+	return args.Get(0).([]model.Delivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(delivery *model.Delivery, retryIn time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorded = append(m.recorded, *delivery)
+	m.retries = append(m.retries, retryIn)
+	return nil
+}
+
+var workerConfig = service.WorkerConfig{
+	PollInterval: time.Second,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   time.Minute,
+	Timeout:      time.Second,
+}
+
+func TestCreateSubscription(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	webhookService := service.NewWebhookService(repo)
+
+	t.Run("generated secret", func(t *testing.T) {
+		repo.On("CreateSubscription", mock.MatchedBy(func(subscription *model.Subscription) bool {
+			return len(subscription.Secret) == 64 && subscription.Active
+		})).Return(nil).Once()
+
+		response, err := webhookService.CreateSubscription(&dto.SubscriptionRequest{
+			URL:        "https://example.com/hook",
+			EventTypes: []string{"news.created"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, response.Secret, 64)
+	})
+
+	t.Run("given secret, inactive", func(t *testing.T) {
+		inactive := false
+		repo.On("CreateSubscription", mock.MatchedBy(func(subscription *model.Subscription) bool {
+			return subscription.Secret == "0123456789abcdef" && !subscription.Active
+		})).Return(nil).Once()
+
+		response, err := webhookService.CreateSubscription(&dto.SubscriptionRequest{
+			URL:        "https://example.com/hook",
+			Secret:     "0123456789abcdef",
+			EventTypes: []string{"news.created"},
+			Active:     &inactive,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "0123456789abcdef", response.Secret)
+	})
+
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateSubscriptionKeepsSecret(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	webhookService := service.NewWebhookService(repo)
+
+	repo.On("GetSubscriptionByID", 1).Return(&model.Subscription{ID: 1, Secret: "kept-secret-value", Active: true}, nil)
+	repo.On("UpdateSubscription", mock.MatchedBy(func(subscription *model.Subscription) bool {
+		return subscription.Secret == "kept-secret-value" && subscription.Active && subscription.URL == "https://example.com/new"
+	})).Return(nil)
+
+	response, err := webhookService.UpdateSubscription(1, &dto.SubscriptionRequest{
+		URL:        "https://example.com/new",
+		EventTypes: []string{"news.deleted"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, response.Secret)
+	repo.AssertExpectations(t)
+
+	repo.On("GetSubscriptionByID", 2).Return(nil, errors.New("webhook not found"))
+	_, err = webhookService.UpdateSubscription(2, &dto.SubscriptionRequest{})
+	assert.EqualError(t, err, "webhook not found")
+}
+
+func TestDeliverDue(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 10)
+	status := http.StatusOK
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	delivery := model.Delivery{
+		ID:        7,
+		EventType: "news.deleted",
+		Payload:   json.RawMessage(`{"id":3}`),
+		CreatedAt: "2026-01-02T03:04:05Z",
+		URL:       receiver.URL,
+		Secret:    "subscription-secret",
+	}
+
+	run := func(t *testing.T, delivery model.Delivery) *MockWebhookRepository {
+		t.Helper()
+		repo := new(MockWebhookRepository)
+		repo.On("ClaimDueDeliveries", 10, 2*time.Second).Return([]model.Delivery{delivery}, nil)
+
+		sent, err := service.NewWorker(repo, workerConfig).DeliverDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, repo.recorded, 1)
+		return repo
+	}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		repo := run(t, delivery)
+
+		request := <-requests
+		assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+		assert.Equal(t, "news.deleted", request.header.Get(service.HeaderEvent))
+		assert.Equal(t, "7", request.header.Get(service.HeaderDelivery))
+
+		timestamp := request.header.Get(service.HeaderTimestamp)
+		assert.Equal(t, service.Sign("subscription-secret", timestamp, request.body), request.header.Get(service.HeaderSignature))
+		assert.NotEqual(t, service.Sign("another-secret", timestamp, request.body), request.header.Get(service.HeaderSignature))
+		assert.JSONEq(t, `{"id":7,"event":"news.deleted","created_at":"2026-01-02T03:04:05Z","data":{"id":3}}`, string(request.body))
+
+		assert.Equal(t, model.StatusSucceeded, repo.recorded[0].Status)
+		assert.Equal(t, 1, repo.recorded[0].Attempts)
+		assert.Equal(t, 200, repo.recorded[0].ResponseStatus)
+		assert.Equal(t, time.Duration(0), repo.retries[0])
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		retry := delivery
+		retry.Attempts = 1
+
+		repo := run(t, retry)
+		<-requests
+
+		assert.Equal(t, model.StatusPending, repo.recorded[0].Status)
+		assert.Equal(t, 2, repo.recorded[0].Attempts)
+		assert.Equal(t, 503, repo.recorded[0].ResponseStatus)
+		assert.Equal(t, "unexpected status 503", repo.recorded[0].LastError)
+		assert.Equal(t, time.Minute, repo.retries[0])
+	})
+
+	t.Run("failed after the last attempt", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		last := delivery
+		last.Attempts = 2
+
+		repo := run(t, last)
+		<-requests
+
+		assert.Equal(t, model.StatusFailed, repo.recorded[0].Status)
+		assert.Equal(t, 3, repo.recorded[0].Attempts)
+	})
+
+	t.Run("unreachable receiver", func(t *testing.T) {
+		unreachable := delivery
+		unreachable.URL = "http://127.0.0.1:1"
+
+		repo := run(t, unreachable)
+
+		assert.Equal(t, model.StatusPending, repo.recorded[0].Status)
+		assert.Equal(t, 0, repo.recorded[0].ResponseStatus)
+		assert.NotEmpty(t, repo.recorded[0].LastError)
+		assert.Equal(t, 30*time.Second, repo.retries[0])
+	})
+}
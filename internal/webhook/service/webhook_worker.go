@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/dto"
+	"github.com/ahmadammarm/go-rest-api-template/internal/webhook/model"
+	webhookRepo "github.com/ahmadammarm/go-rest-api-template/internal/webhook/repository"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" followed by the hex HMAC-SHA256, keyed
+	// with the subscription secret, of the timestamp, a dot and the body.
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type WorkerConfig struct {
+	// PollInterval is how long the worker waits when no delivery is due.
+	PollInterval time.Duration
+	// BatchSize deliveries are claimed and sent concurrently at a time.
+	BatchSize int
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	// The delay before retry n is BaseBackoff * 2^(n-1), capped at MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Timeout bounds every request to a subscriber.
+	Timeout time.Duration
+}
+
+// Worker sends the pending webhook deliveries. Several workers, in one or
+// more processes, may run against the same database.
+type Worker struct {
+	webhookRepo webhookRepo.WebhookRepository
+	client      *http.Client
+	config      WorkerConfig
+}
+
+// Sign returns the HeaderSignature value of a delivery.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run delivers due deliveries until ctx is done.
+func (worker *Worker) Run(ctx context.Context) {
+	log.Println("Webhook worker started")
+	background.Poll(ctx, "webhook worker", worker.config.PollInterval, worker.config.BatchSize, worker.DeliverDue)
+	log.Println("Webhook worker stopped")
+}
+
+// DeliverDue claims one batch of due deliveries, sends them and records the
+// outcome. It returns how many were claimed.
+func (worker *Worker) DeliverDue(ctx context.Context) (int, error) {
+	// Claimed deliveries are hidden from other workers until the lease ends,
+	// by which time every request has timed out.
+	deliveries, err := worker.webhookRepo.ClaimDueDeliveries(worker.config.BatchSize, 2*worker.config.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *model.Delivery) {
+			defer wg.Done()
+			worker.deliver(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (worker *Worker) deliver(ctx context.Context, delivery *model.Delivery) {
+	delivery.Attempts++
+	delivery.ResponseStatus = 0
+	delivery.LastError = ""
+
+	if err := worker.send(ctx, delivery); err != nil {
+		delivery.LastError = err.Error()
+	}
+
+	var retryIn time.Duration
+	switch {
+	case delivery.LastError == "":
+		delivery.Status = model.StatusSucceeded
+	case delivery.Attempts >= worker.config.MaxAttempts:
+		delivery.Status = model.StatusFailed
+		log.Printf("Webhook delivery %d failed after %d attempts: %s", delivery.ID, delivery.Attempts, delivery.LastError)
+	default:
+		delivery.Status = model.StatusPending
+		retryIn = background.Backoff(worker.config.BaseBackoff, worker.config.MaxBackoff, delivery.Attempts)
+	}
+
+	if err := worker.webhookRepo.RecordAttempt(delivery, retryIn); err != nil {
+		log.Printf("Error recording webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// send POSTs the delivery and sets its ResponseStatus. Anything but a 2xx
+// response is an error.
+func (worker *Worker) send(ctx context.Context, delivery *model.Delivery) error {
+	body, err := json.Marshal(dto.Payload{
+		ID:        delivery.ID,
+		Event:     delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "go-rest-api-template-webhooks")
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(HeaderTimestamp, timestamp)
+	request.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, body))
+
+	response, err := worker.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// Drained so that the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	delivery.ResponseStatus = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func NewWorker(webhookRepo webhookRepo.WebhookRepository, config WorkerConfig) *Worker {
+	return &Worker{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: config.Timeout},
+		config:      config,
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.news;
+DROP TABLE IF EXISTS public.users;
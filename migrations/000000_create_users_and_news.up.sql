@@ -0,0 +1,17 @@
+-- The users and news tables of go_rest_template.sql, for databases created
+-- with the migrate command. They are left alone where the dump created them.
+CREATE TABLE IF NOT EXISTS public.users (
+    id serial PRIMARY KEY,
+    email character varying(100) NOT NULL UNIQUE,
+    name character varying(100) NOT NULL,
+    password character varying(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.news (
+    id serial PRIMARY KEY,
+    title character varying(100) NOT NULL,
+    content text NOT NULL,
+    user_id integer CONSTRAINT fk_user_id REFERENCES public.users(id),
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
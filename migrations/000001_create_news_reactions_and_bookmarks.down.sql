@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.news_bookmarks;
+DROP TABLE IF EXISTS public.news_reactions;
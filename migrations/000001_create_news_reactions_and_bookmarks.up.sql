@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS public.news_reactions (
+    news_id integer NOT NULL REFERENCES public.news(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    type character varying(16) NOT NULL CHECK (type IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry')),
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.news_bookmarks (
+    news_id integer NOT NULL REFERENCES public.news(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS news_bookmarks_user_id_idx ON public.news_bookmarks (user_id, created_at DESC);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS public.news_attachments (
+    id serial PRIMARY KEY,
+    -- Set to NULL when the news item is deleted so the stored files can be
+    -- found and purged afterwards.
+    news_id integer REFERENCES public.news(id) ON DELETE SET NULL,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    file_name character varying(255) NOT NULL,
+    content_type character varying(100) NOT NULL,
+    size bigint NOT NULL,
+    storage_key character varying(255) NOT NULL UNIQUE,
+    thumbnail_key character varying(255) NOT NULL DEFAULT '',
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS news_attachments_news_id_idx ON public.news_attachments (news_id);
@@ -0,0 +1,6 @@
+ALTER TABLE public.users
+    DROP COLUMN IF EXISTS avatar_key,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS website,
+    DROP COLUMN IF EXISTS bio;
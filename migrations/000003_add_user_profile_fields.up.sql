@@ -0,0 +1,6 @@
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS bio character varying(500) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS website character varying(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS locale character varying(35) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS timezone character varying(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_key character varying(255) NOT NULL DEFAULT '';
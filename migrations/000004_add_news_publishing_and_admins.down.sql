@@ -0,0 +1,7 @@
+ALTER TABLE public.users
+    DROP COLUMN IF EXISTS is_admin;
+
+DROP INDEX IF EXISTS news_published_at_idx;
+
+ALTER TABLE public.news
+    DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,10 @@
+ALTER TABLE public.news
+    ADD COLUMN IF NOT EXISTS published_at timestamp without time zone;
+
+-- News created before publishing existed was visible to every signed-in user.
+UPDATE public.news SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS news_published_at_idx ON public.news (published_at);
+
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS is_admin boolean NOT NULL DEFAULT false;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS public.webhook_subscriptions (
+    id serial PRIMARY KEY,
+    url character varying(2048) NOT NULL,
+    secret character varying(255) NOT NULL,
+    event_types text[] NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deliveries are the outbox of webhooks: they are inserted in the transaction
+-- of the news change, then sent by the webhook worker. They are kept as the
+-- delivery log.
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    subscription_id integer NOT NULL REFERENCES public.webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type character varying(100) NOT NULL,
+    payload jsonb NOT NULL,
+    -- pending, succeeded or failed once retries are exhausted.
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at timestamp without time zone,
+    response_status integer,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_id_idx ON public.webhook_deliveries (subscription_id, id DESC);
@@ -0,0 +1,16 @@
+-- Domain events, written in the transaction of the change they describe and
+-- dispatched to in-process subscribers afterwards.
+CREATE TABLE IF NOT EXISTS public.outbox_events (
+    id bigserial PRIMARY KEY,
+    event_type character varying(100) NOT NULL,
+    payload jsonb NOT NULL,
+    -- pending, dispatched or failed once retries are exhausted.
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at timestamp without time zone
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_due_idx ON public.outbox_events (next_attempt_at, id) WHERE status = 'pending';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS public.jobs (
+    id bigserial PRIMARY KEY,
+    type character varying(100) NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}',
+    -- Higher priorities run first.
+    priority integer NOT NULL DEFAULT 0,
+    -- pending, running, succeeded, dead once retries are exhausted, or
+    -- cancelled.
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    max_attempts integer NOT NULL DEFAULT 5,
+    run_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- A running job whose worker died is picked up again after this.
+    locked_until timestamp without time zone,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS jobs_due_idx ON public.jobs (priority DESC, run_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS jobs_running_idx ON public.jobs (locked_until) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS jobs_status_idx ON public.jobs (status, id DESC);
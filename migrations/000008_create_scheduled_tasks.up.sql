@@ -0,0 +1,12 @@
+-- The last run of every scheduled task, whichever instance ran it.
+CREATE TABLE IF NOT EXISTS public.scheduled_tasks (
+    name character varying(100) PRIMARY KEY,
+    schedule character varying(100) NOT NULL,
+    -- The time the run was due. An instance only runs a task when it is
+    -- later than this, so a run is not repeated by another replica.
+    last_scheduled_at timestamp with time zone NOT NULL,
+    last_started_at timestamp with time zone NOT NULL,
+    last_duration_ms bigint NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    run_count bigint NOT NULL DEFAULT 0
+);
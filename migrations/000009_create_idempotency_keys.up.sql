@@ -0,0 +1,22 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed when
+-- the request is repeated.
+CREATE TABLE IF NOT EXISTS public.idempotency_keys (
+    -- The route and caller the key was sent to, so that keys of different
+    -- callers or endpoints never clash.
+    scope character varying(255) NOT NULL,
+    key character varying(255) NOT NULL,
+    -- SHA-256 of the request. A repeat with another fingerprint is rejected.
+    fingerprint character(64) NOT NULL,
+    -- NULL while the request is in progress.
+    status_code integer,
+    content_type character varying(255) NOT NULL DEFAULT '',
+    body bytea,
+    -- Until when the instance handling the request holds the key. A request
+    -- whose instance died can be retried once it has passed.
+    locked_until timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    expires_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON public.idempotency_keys (expires_at);
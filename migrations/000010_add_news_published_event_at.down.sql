@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS news_unannounced_idx;
+
+ALTER TABLE public.news
+    DROP COLUMN IF EXISTS published_event_at;
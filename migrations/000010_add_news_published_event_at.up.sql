@@ -0,0 +1,8 @@
+-- When the news.published event of the current publication was written. News
+-- scheduled for later get it from a scheduled task once they are public.
+ALTER TABLE public.news
+    ADD COLUMN IF NOT EXISTS published_event_at timestamp without time zone;
+
+UPDATE public.news SET published_event_at = published_at WHERE published_at <= NOW();
+
+CREATE INDEX IF NOT EXISTS news_unannounced_idx ON public.news (published_at) WHERE published_event_at IS NULL;
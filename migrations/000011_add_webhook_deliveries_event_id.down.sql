@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS webhook_deliveries_event_id_idx;
+
+ALTER TABLE public.webhook_deliveries
+    DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,7 @@
+-- Deliveries are queued from the events of the outbox. An event may be
+-- dispatched more than once, so the outbox event is recorded to queue its
+-- deliveries once only.
+ALTER TABLE public.webhook_deliveries
+    ADD COLUMN IF NOT EXISTS event_id bigint;
+
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_id_idx ON public.webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,4 @@
+-- The worker running a job. A worker whose lease ended, and whose job was
+-- claimed again by another, does not record the outcome of its run.
+ALTER TABLE public.jobs
+    ADD COLUMN IF NOT EXISTS locked_by character varying(255);
@@ -0,0 +1,33 @@
+// Package migrations embeds the schema migrations of the dialects the
+// repositories run on.
+//
+// The Postgres migrations in this directory are applied by the migrate
+// command. The SQLite ones in sqlite/ create the same tables, except for the
+// outbox, webhooks, jobs, scheduled tasks and idempotency keys, which need
+// Postgres; they are applied on connection. Both go through database.Migrate.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var postgresFiles embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// Postgres returns the Postgres migrations.
+func Postgres() fs.FS {
+	return postgresFiles
+}
+
+// SQLite returns the SQLite migrations.
+func SQLite() fs.FS {
+	migrations, err := fs.Sub(sqliteFiles, "sqlite")
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
@@ -0,0 +1,17 @@
+-- The users and news tables, which the Postgres database gets from
+-- go_rest_template.sql.
+CREATE TABLE IF NOT EXISTS users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    email varchar(100) NOT NULL UNIQUE,
+    name varchar(100) NOT NULL,
+    password varchar(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS news (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    title varchar(100) NOT NULL,
+    content text NOT NULL,
+    user_id integer REFERENCES users(id),
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS news_bookmarks;
+DROP TABLE IF EXISTS news_reactions;
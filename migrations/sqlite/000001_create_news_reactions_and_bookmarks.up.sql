@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS news_reactions (
+    news_id integer NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type varchar(16) NOT NULL CHECK (type IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry')),
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS news_bookmarks (
+    news_id integer NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS news_bookmarks_user_id_idx ON news_bookmarks (user_id, created_at DESC);
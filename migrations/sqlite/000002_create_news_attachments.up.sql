@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS news_attachments (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    -- Set to NULL when the news item is deleted so the stored files can be
+    -- found and purged afterwards.
+    news_id integer REFERENCES news(id) ON DELETE SET NULL,
+    user_id integer NOT NULL REFERENCES users(id),
+    file_name varchar(255) NOT NULL,
+    content_type varchar(100) NOT NULL,
+    size bigint NOT NULL,
+    storage_key varchar(255) NOT NULL UNIQUE,
+    thumbnail_key varchar(255) NOT NULL DEFAULT '',
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS news_attachments_news_id_idx ON news_attachments (news_id);
@@ -0,0 +1,6 @@
+-- SQLite adds one column per statement.
+ALTER TABLE users ADD COLUMN bio varchar(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN website varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN locale varchar(35) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN timezone varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_key varchar(255) NOT NULL DEFAULT '';
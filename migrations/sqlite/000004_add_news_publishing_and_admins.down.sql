@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN is_admin;
+
+DROP INDEX IF EXISTS news_published_at_idx;
+
+ALTER TABLE news DROP COLUMN published_at;
@@ -0,0 +1,8 @@
+ALTER TABLE news ADD COLUMN published_at timestamp;
+
+-- News created before publishing existed was visible to every signed-in user.
+UPDATE news SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS news_published_at_idx ON news (published_at);
+
+ALTER TABLE users ADD COLUMN is_admin boolean NOT NULL DEFAULT false;
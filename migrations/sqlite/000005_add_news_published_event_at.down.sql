@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS news_unannounced_idx;
+
+ALTER TABLE news DROP COLUMN published_event_at;
@@ -0,0 +1,7 @@
+-- When the news.published event of the current publication was written. News
+-- scheduled for later get it from a scheduled task once they are public.
+ALTER TABLE news ADD COLUMN published_event_at timestamp;
+
+UPDATE news SET published_event_at = published_at WHERE published_at <= NOW();
+
+CREATE INDEX IF NOT EXISTS news_unannounced_idx ON news (published_at) WHERE published_event_at IS NULL;
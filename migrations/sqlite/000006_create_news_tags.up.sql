@@ -0,0 +1,8 @@
+-- The tags of news, lowercased. Per-tag feeds list the news having one.
+CREATE TABLE IF NOT EXISTS news_tags (
+    news_id integer NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+    tag varchar(50) NOT NULL,
+    PRIMARY KEY (news_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS news_tags_tag_idx ON news_tags (tag);
@@ -0,0 +1,89 @@
+// Package app wires the modules of the service: a Registry builds them from
+// the singletons of a Container in the order they depend on each other,
+// mounts their routes, and starts and stops them in order.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/storage"
+	"github.com/go-playground/validator/v10"
+)
+
+// Module is a part of the service, e.g. the news: its handlers, their routes
+// and its background work.
+type Module interface {
+	// Name identifies the module to the modules depending on it, e.g. "news".
+	Name() string
+	// Init builds the module from the singletons of c, and the modules it
+	// depends on, which are initialized before it.
+	Init(c *Container) error
+	// RegisterRoutes mounts the routes of the module. It is called once per
+	// API version.
+	RegisterRoutes(routes Routes)
+	// Start starts the background work of the module and returns. The work
+	// runs until Stop, or until ctx is done.
+	Start(ctx context.Context) error
+	// Stop stops the background work, waiting for it until ctx is done.
+	Stop(ctx context.Context) error
+}
+
+// Dependent is a Module depending on others, by name.
+type Dependent interface {
+	DependsOn() []string
+}
+
+// Base implements the methods of Module a module can do without: modules
+// embed it and override what they need.
+type Base struct{}
+
+func (Base) Init(c *Container) error         { return nil }
+func (Base) RegisterRoutes(routes Routes)    {}
+func (Base) Start(ctx context.Context) error { return nil }
+func (Base) Stop(ctx context.Context) error  { return nil }
+
+// Config are the settings modules share, read once at startup.
+type Config struct {
+	// Driver is the database DB_DRIVER selects. The outbox, webhooks, jobs
+	// and scheduled tasks need Postgres.
+	Driver database.Dialect
+}
+
+// Postgres tells whether the database is Postgres.
+func (config Config) Postgres() bool {
+	return config.Driver == database.Postgres
+}
+
+// Container holds the singletons every module shares, and the modules
+// initialized so far.
+type Container struct {
+	Config Config
+	DB     *sql.DB
+	// Reads runs the reads that can lag behind the latest changes, such as
+	// lists: on the read replicas of DB, or on DB itself.
+	Reads     database.Querier
+	Validator *validator.Validate
+	Store     storage.Storage
+	// Bus and Queue are where modules subscribe to domain events and
+	// register their job handlers.
+	Bus       *outbox.Bus
+	Queue     *jobs.Queue
+	Scheduler *scheduler.Scheduler
+
+	modules map[string]Module
+}
+
+// Lookup returns the module named name, which the caller depends on.
+func Lookup[T Module](c *Container, name string) (T, error) {
+	module, ok := c.modules[name].(T)
+	if !ok {
+		return module, fmt.Errorf("module %s is not initialized, or is not a %T", name, module)
+	}
+	return module, nil
+}
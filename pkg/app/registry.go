@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Registry runs the lifecycle of modules: Init, RegisterRoutes, Start and
+// Stop.
+type Registry struct {
+	container *Container
+	// modules are in registration order until Init sorts them.
+	modules []Module
+	started []Module
+}
+
+// New registers modules, in any order, to be built from the singletons of
+// container.
+func New(container *Container, modules ...Module) *Registry {
+	return &Registry{container: container, modules: modules}
+}
+
+// Modules returns the modules, in dependency order once initialized.
+func (registry *Registry) Modules() []Module {
+	return registry.modules
+}
+
+// Init initializes the modules, each after the modules it depends on and
+// otherwise in registration order.
+func (registry *Registry) Init() error {
+	ordered, err := sortModules(registry.modules)
+	if err != nil {
+		return err
+	}
+	registry.modules = ordered
+
+	registry.container.modules = map[string]Module{}
+	for _, module := range ordered {
+		if err := module.Init(registry.container); err != nil {
+			return fmt.Errorf("module %s: %w", module.Name(), err)
+		}
+		registry.container.modules[module.Name()] = module
+	}
+
+	return nil
+}
+
+// RegisterRoutes mounts the routes of the modules, in order.
+func (registry *Registry) RegisterRoutes(routes Routes) {
+	for _, module := range registry.modules {
+		module.RegisterRoutes(routes)
+	}
+}
+
+// Start starts the modules in order. When one fails, those started are
+// stopped.
+func (registry *Registry) Start(ctx context.Context) error {
+	for _, module := range registry.modules {
+		if err := module.Start(ctx); err != nil {
+			err = fmt.Errorf("module %s: %w", module.Name(), err)
+			return errors.Join(err, registry.Stop(ctx))
+		}
+		registry.started = append(registry.started, module)
+	}
+
+	log.Printf("Started %d modules", len(registry.started))
+	return nil
+}
+
+// Stop stops the started modules in reverse order, so that a module stops
+// before those it depends on. Every module is stopped even when some fail.
+func (registry *Registry) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(registry.started) - 1; i >= 0; i-- {
+		module := registry.started[i]
+		if err := module.Stop(ctx); err != nil {
+			log.Printf("Failed to stop module %s: %v", module.Name(), err)
+			errs = append(errs, fmt.Errorf("module %s: %w", module.Name(), err))
+		}
+	}
+	registry.started = nil
+
+	return errors.Join(errs...)
+}
+
+// sortModules orders modules after their dependencies, keeping the
+// registration order otherwise.
+func sortModules(modules []Module) ([]Module, error) {
+	byName := make(map[string]Module, len(modules))
+	for _, module := range modules {
+		if _, ok := byName[module.Name()]; ok {
+			return nil, fmt.Errorf("module %s is registered twice", module.Name())
+		}
+		byName[module.Name()] = module
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	ordered := make([]Module, 0, len(modules))
+
+	var visit func(module Module, path []string) error
+	visit = func(module Module, path []string) error {
+		name := module.Name()
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("modules depend on each other: %v", append(path, name))
+		}
+
+		state[name] = visiting
+		if dependent, ok := module.(Dependent); ok {
+			for _, dependency := range dependent.DependsOn() {
+				required, ok := byName[dependency]
+				if !ok {
+					return fmt.Errorf("module %s depends on %s, which is not registered", name, dependency)
+				}
+				if err := visit(required, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+
+		ordered = append(ordered, module)
+		return nil
+	}
+
+	for _, module := range modules {
+		if err := visit(module, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,145 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeModule records its lifecycle in events.
+type fakeModule struct {
+	app.Base
+	name      string
+	dependsOn []string
+	events    *[]string
+	startErr  error
+}
+
+func (module *fakeModule) Name() string        { return module.name }
+func (module *fakeModule) DependsOn() []string { return module.dependsOn }
+
+func (module *fakeModule) Init(c *app.Container) error {
+	for _, dependency := range module.dependsOn {
+		if _, err := app.Lookup[*fakeModule](c, dependency); err != nil {
+			return err
+		}
+	}
+	*module.events = append(*module.events, "init "+module.name)
+	return nil
+}
+
+func (module *fakeModule) Start(ctx context.Context) error {
+	if module.startErr != nil {
+		return module.startErr
+	}
+	*module.events = append(*module.events, "start "+module.name)
+	return nil
+}
+
+func (module *fakeModule) Stop(ctx context.Context) error {
+	*module.events = append(*module.events, "stop "+module.name)
+	return nil
+}
+
+func TestRegistryLifecycle(t *testing.T) {
+	var events []string
+	registry := app.New(&app.Container{},
+		&fakeModule{name: "graphql", dependsOn: []string{"user", "news"}, events: &events},
+		&fakeModule{name: "news", dependsOn: []string{"user"}, events: &events},
+		&fakeModule{name: "user", events: &events},
+		&fakeModule{name: "feed", events: &events},
+	)
+
+	assert.NoError(t, registry.Init())
+	assert.NoError(t, registry.Start(context.Background()))
+	assert.NoError(t, registry.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"init user", "init news", "init graphql", "init feed",
+		"start user", "start news", "start graphql", "start feed",
+		"stop feed", "stop graphql", "stop news", "stop user",
+	}, events)
+}
+
+func TestRegistryInitErrors(t *testing.T) {
+	var events []string
+	tests := []struct {
+		name    string
+		modules []app.Module
+		err     string
+	}{
+		{"duplicate", []app.Module{
+			&fakeModule{name: "user", events: &events},
+			&fakeModule{name: "user", events: &events},
+		}, "module user is registered twice"},
+		{"unknown dependency", []app.Module{
+			&fakeModule{name: "news", dependsOn: []string{"user"}, events: &events},
+		}, "module news depends on user, which is not registered"},
+		{"cycle", []app.Module{
+			&fakeModule{name: "user", dependsOn: []string{"news"}, events: &events},
+			&fakeModule{name: "news", dependsOn: []string{"user"}, events: &events},
+		}, "modules depend on each other: [user news user]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.New(&app.Container{}, tt.modules...).Init()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+	assert.Empty(t, events)
+}
+
+func TestRegistryStartFailure(t *testing.T) {
+	var events []string
+	registry := app.New(&app.Container{},
+		&fakeModule{name: "user", events: &events},
+		&fakeModule{name: "news", events: &events, startErr: errors.New("port in use")},
+		&fakeModule{name: "feed", events: &events},
+	)
+	assert.NoError(t, registry.Init())
+
+	err := registry.Start(context.Background())
+	assert.EqualError(t, err, "module news: port in use")
+	assert.Equal(t, []string{"init user", "init news", "init feed", "start user", "stop user"}, events)
+}
+
+func TestLookup(t *testing.T) {
+	var events []string
+	c := &app.Container{}
+	assert.NoError(t, app.New(c, &fakeModule{name: "user", events: &events}).Init())
+
+	module, err := app.Lookup[*fakeModule](c, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, "user", module.Name())
+
+	_, err = app.Lookup[*fakeModule](c, "news")
+	assert.ErrorContains(t, err, "module news is not initialized")
+}
+
+func TestRunner(t *testing.T) {
+	var runner app.Runner
+	assert.NoError(t, runner.Stop(context.Background()), "not started")
+
+	stopped := make(chan struct{})
+	runner.Start(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	assert.NoError(t, runner.Stop(context.Background()))
+	<-stopped
+
+	// The loop ignores its context.
+	release := make(chan struct{})
+	defer close(release)
+	runner.Start(context.Background(), func(ctx context.Context) {
+		<-release
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, runner.Stop(ctx), context.DeadlineExceeded)
+}
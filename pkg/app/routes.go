@@ -0,0 +1,147 @@
+package app
+
+import (
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Mount registers routes on a router and documents them under the same
+// prefix, so that the OpenAPI document follows the routes. The zero Mount
+// drops them.
+type Mount struct {
+	router     fiber.Router
+	doc        *openapi.Document
+	prefix     string
+	deprecated bool
+}
+
+// NewMount returns a Mount on router, whose routes are under prefix. The
+// operations of a deprecated one are documented as such.
+func NewMount(router fiber.Router, doc *openapi.Document, prefix string, deprecated bool) Mount {
+	return Mount{router: router, doc: doc, prefix: prefix, deprecated: deprecated}
+}
+
+// Add registers routes with register and documents them with operations.
+func (m Mount) Add(register func(fiber.Router), operations []openapi.Operation) {
+	if m.router == nil {
+		return
+	}
+
+	register(m.router)
+	if m.deprecated {
+		m.doc.AddDeprecated(m.prefix, operations...)
+	} else {
+		m.doc.Add(m.prefix, operations...)
+	}
+}
+
+// Group returns a Mount for the routes under prefix, which handlers run
+// before.
+func (m Mount) Group(prefix string, handlers ...fiber.Handler) Mount {
+	if m.router == nil {
+		return m
+	}
+
+	m.router = m.router.Group(prefix, handlers...)
+	m.prefix += prefix
+	return m
+}
+
+// Routes are the groups of an API version modules add their routes to.
+type Routes struct {
+	// Root is the root of the version, e.g. /api/v1. The deprecated
+	// unversioned aliases have a Root only.
+	Root Mount
+	// Versioned is Root in versions, for the routes added since /api/v1,
+	// which have no unversioned alias.
+	Versioned Mount
+	// Public is for anonymous callers, with OptionalJWTAuth and HTTP caching.
+	Public Mount
+	// Admin requires an admin token.
+	Admin Mount
+}
+
+// PerRoute returns router, adding handlers before those of every route
+// registered through it. Unlike the middleware of a group, they run for the
+// requests a route matches only: a group without a prefix runs its middleware
+// on every request reaching it, including those no route matches.
+func PerRoute(router fiber.Router, handlers ...fiber.Handler) fiber.Router {
+	return perRouteRouter{Router: router, handlers: handlers}
+}
+
+type perRouteRouter struct {
+	fiber.Router
+	handlers []fiber.Handler
+}
+
+func (r perRouteRouter) with(handlers []fiber.Handler) []fiber.Handler {
+	return append(append([]fiber.Handler{}, r.handlers...), handlers...)
+}
+
+func (r perRouteRouter) Get(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Get(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Head(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Head(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Post(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Post(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Put(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Put(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Delete(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Delete(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Connect(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Connect(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Options(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Options(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Trace(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Trace(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Patch(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Patch(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Add(method string, path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.Add(method, path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) All(path string, handlers ...fiber.Handler) fiber.Router {
+	r.Router.All(path, r.with(handlers)...)
+	return r
+}
+
+func (r perRouteRouter) Group(prefix string, handlers ...fiber.Handler) fiber.Router {
+	return perRouteRouter{Router: r.Router.Group(prefix, handlers...), handlers: r.handlers}
+}
+
+func (r perRouteRouter) Route(prefix string, fn func(router fiber.Router), name ...string) fiber.Router {
+	group := r.Group(prefix)
+	if len(name) > 0 {
+		group.Name(name[0])
+	}
+	fn(group)
+	return group
+}
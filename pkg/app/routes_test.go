@@ -0,0 +1,54 @@
+package app_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/app"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func pingRouters(router fiber.Router) {
+	router.Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+}
+
+var pingOperations = []openapi.Operation{{Method: "GET", Path: "/ping", Summary: "Ping"}}
+
+func TestMount(t *testing.T) {
+	server := fiber.New()
+	doc := openapi.New(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	root := app.NewMount(server.Group("/api/v1"), doc, "/api/v1", false)
+	root.Group("/admin").Add(pingRouters, pingOperations)
+	app.Mount{}.Group("/public").Add(pingRouters, pingOperations)
+
+	resp, err := server.Test(httptest.NewRequest("GET", "/api/v1/admin/ping", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.True(t, doc.Has("GET", "/api/v1/admin/ping"))
+	assert.Equal(t, []string{"GET /api/v1/admin/ping"}, doc.Operations())
+}
+
+func TestPerRoute(t *testing.T) {
+	server := fiber.New()
+	calls := 0
+	router := app.PerRoute(server, func(c *fiber.Ctx) error {
+		calls++
+		c.Set("X-Marked", "true")
+		return c.Next()
+	})
+	router.Group("/v0").Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+
+	resp, err := server.Test(httptest.NewRequest("GET", "/v0/ping", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("X-Marked"))
+
+	resp, err = server.Test(httptest.NewRequest("GET", "/missing", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-Marked"))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,37 @@
+package app
+
+import "context"
+
+// Runner runs the background loop of a module, e.g. a worker's Run, from its
+// Start to its Stop.
+type Runner struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Start runs run in a goroutine, with a context done on Stop or when ctx is.
+func (runner *Runner) Start(ctx context.Context, run func(ctx context.Context)) {
+	ctx, runner.cancel = context.WithCancel(ctx)
+	runner.done = make(chan struct{})
+
+	go func() {
+		defer close(runner.done)
+		run(ctx)
+	}()
+}
+
+// Stop cancels the context of the loop and waits for it to return, until ctx
+// is done. It does nothing when the loop was not started.
+func (runner *Runner) Stop(ctx context.Context) error {
+	if runner.cancel == nil {
+		return nil
+	}
+	runner.cancel()
+
+	select {
+	case <-runner.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
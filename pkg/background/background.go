@@ -0,0 +1,64 @@
+// Package background holds what the background workers share: the polling
+// loop, the retry backoff and the recovery of panicking handlers.
+package background
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Backoff returns the delay before retrying work that failed attempts times:
+// base * 2^(attempts-1), capped at limit.
+func Backoff(base time.Duration, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+
+	return min(delay, limit)
+}
+
+// Poll calls step until ctx is done. step handles a batch of due work and
+// returns how much it handled; Poll waits interval before calling it again,
+// unless the batch was full. Errors are logged, prefixed with name.
+func Poll(ctx context.Context, name string, interval time.Duration, batchSize int, step func(ctx context.Context) (int, error)) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		// Both may be ready: stopping wins.
+		if ctx.Err() != nil {
+			return
+		}
+
+		handled, err := step(ctx)
+		if err != nil {
+			log.Printf("Error in %s: %v", name, err)
+		}
+
+		// A full batch means more may be due already.
+		wait := interval
+		if handled > 0 && handled == batchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// Call calls run, turning a panic into an error.
+func Call(run func() error) (err error) {
+	defer func() {
+		if pan := recover(); pan != nil {
+			err = fmt.Errorf("panic: %v", pan)
+		}
+	}()
+
+	return run()
+}
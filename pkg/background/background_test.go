@@ -0,0 +1,52 @@
+package background_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, background.Backoff(5*time.Second, time.Minute, 1))
+	assert.Equal(t, 10*time.Second, background.Backoff(5*time.Second, time.Minute, 2))
+	assert.Equal(t, 20*time.Second, background.Backoff(5*time.Second, time.Minute, 3))
+	assert.Equal(t, time.Minute, background.Backoff(5*time.Second, time.Minute, 50))
+}
+
+func TestPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Full batches are followed right away, well before the interval.
+	var calls int
+	done := make(chan struct{})
+	go func() {
+		background.Poll(ctx, "test", time.Hour, 2, func(ctx context.Context) (int, error) {
+			calls++
+			if calls == 3 {
+				cancel()
+				return 1, errors.New("boom")
+			}
+			return 2, nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Poll did not return")
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestCall(t *testing.T) {
+	assert.NoError(t, background.Call(func() error { return nil }))
+	assert.EqualError(t, background.Call(func() error { return errors.New("boom") }), "boom")
+	assert.EqualError(t, background.Call(func() error { panic("oops") }), "panic: oops")
+}
@@ -0,0 +1,26 @@
+// Package cache stores byte values under string keys, with a time to live.
+package cache
+
+import (
+	"context"
+	"expvar"
+	"time"
+)
+
+// Cache is implemented by Memory, a per-process LRU, and Redis, shared by
+// every process using the same server.
+type Cache interface {
+	// Get returns the value of key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, or until it is evicted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys. Missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Hits and Misses count lookups per cached data set, e.g. "news". They are
+// published through expvar.
+var (
+	Hits   = expvar.NewMap("cache_hits")
+	Misses = expvar.NewMap("cache_misses")
+)
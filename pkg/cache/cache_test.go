@@ -0,0 +1,224 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/cache"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		memory := cache.NewMemory(2)
+		assert.NoError(t, memory.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.NoError(t, memory.Set(ctx, "b", []byte("2"), time.Minute))
+
+		_, ok, _ := memory.Get(ctx, "a")
+		assert.True(t, ok)
+
+		assert.NoError(t, memory.Set(ctx, "c", []byte("3"), time.Minute))
+		assert.Equal(t, 2, memory.Len())
+
+		_, ok, _ = memory.Get(ctx, "b")
+		assert.False(t, ok)
+		value, ok, _ := memory.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		memory := cache.NewMemory(10)
+		assert.NoError(t, memory.Set(ctx, "a", []byte("1"), time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+
+		_, ok, _ := memory.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, memory.Len())
+	})
+
+	t.Run("deletes", func(t *testing.T) {
+		memory := cache.NewMemory(10)
+		assert.NoError(t, memory.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.NoError(t, memory.Delete(ctx, "a", "missing"))
+
+		_, ok, _ := memory.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+}
+
+func TestFenced(t *testing.T) {
+	ctx := context.Background()
+	fenced := cache.NewFenced(cache.NewMemory(10))
+
+	generation := fenced.Generation()
+	assert.NoError(t, fenced.Fill(ctx, generation, "a", []byte("1"), time.Minute))
+	value, ok, _ := fenced.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	// A fill that started before a deletion stores nothing.
+	generation = fenced.Generation()
+	assert.NoError(t, fenced.Delete(ctx, "a"))
+	assert.NoError(t, fenced.Fill(ctx, generation, "a", []byte("stale"), time.Minute))
+	_, ok, _ = fenced.Get(ctx, "a")
+	assert.False(t, ok)
+
+	assert.NoError(t, fenced.Fill(ctx, fenced.Generation(), "a", []byte("2"), time.Minute))
+	value, _, _ = fenced.Get(ctx, "a")
+	assert.Equal(t, []byte("2"), value)
+}
+
+// fakeRedis serves GET, SET, DEL, AUTH, SELECT and PING over the Redis
+// protocol, ignoring expiry. Other commands, HELLO included, are unknown, so
+// that clients fall back to RESP2.
+type fakeRedis struct {
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{values: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server, listener.Addr().String()
+}
+
+func (server *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		server.mu.Lock()
+		server.commands = append(server.commands, strings.Join(args, " "))
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[1] == "secret" {
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "SELECT":
+			reply = "+OK\r\n"
+		case "PING":
+			reply = "+PONG\r\n"
+		case "SET":
+			server.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "GET":
+			if value, ok := server.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "DEL":
+			deleted := 0
+			for _, key := range args[1:] {
+				if _, ok := server.values[key]; ok {
+					delete(server.values, key)
+					deleted++
+				}
+			}
+			reply = fmt.Sprintf(":%d\r\n", deleted)
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		server.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		length, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:length])
+	}
+
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server, address := startFakeRedis(t)
+
+	redis, err := cache.NewRedis("redis://:secret@"+address+"/2", 2, time.Second)
+	assert.NoError(t, err)
+	defer redis.Close()
+
+	_, ok, err := redis.Get(ctx, "news:all")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, redis.Set(ctx, "news:all", []byte("value\r\nwith a line break"), 30*time.Second))
+	value, ok, err := redis.Get(ctx, "news:all")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value\r\nwith a line break"), value)
+
+	assert.NoError(t, redis.Delete(ctx, "news:all", "news:id:1"))
+	_, ok, err = redis.Get(ctx, "news:all")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Contains(t, server.commands, "auth secret")
+	assert.Contains(t, server.commands, "select 2")
+	assert.Contains(t, server.commands, "set news:all value\r\nwith a line break ex 30")
+}
+
+func TestRedisRejectsWrongPassword(t *testing.T) {
+	_, address := startFakeRedis(t)
+
+	_, err := cache.NewRedis("redis://:wrong@"+address, 1, time.Second)
+	assert.EqualError(t, err, "WRONGPASS invalid password")
+}
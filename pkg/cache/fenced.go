@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Fenced is a Cache whose deletions fence off the fills in flight: a value
+// read from the source before a Delete is not stored after it, when the
+// Delete already dropped the entry it replaces. Fills take a Generation
+// before reading the source and store through Fill.
+//
+// Only deletions made through this Fenced are seen. A fill racing a change
+// made by another process sharing the cache can still store a stale value,
+// which lasts until it expires.
+type Fenced struct {
+	Cache
+
+	mu         sync.RWMutex
+	generation uint64
+}
+
+func NewFenced(cache Cache) *Fenced {
+	return &Fenced{Cache: cache}
+}
+
+// Generation returns the number of deletions made so far.
+func (cache *Fenced) Generation() uint64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.generation
+}
+
+// Fill stores value under key for ttl, unless a deletion happened since
+// generation. A deletion either comes first and is seen, or waits for the
+// value to be stored and drops it.
+func (cache *Fenced) Fill(ctx context.Context, generation uint64, key string, value []byte, ttl time.Duration) error {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	if cache.generation != generation {
+		return nil
+	}
+	return cache.Cache.Set(ctx, key, value, ttl)
+}
+
+func (cache *Fenced) Delete(ctx context.Context, keys ...string) error {
+	cache.mu.Lock()
+	cache.generation++
+	cache.mu.Unlock()
+
+	return cache.Cache.Delete(ctx, keys...)
+}
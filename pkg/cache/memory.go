@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process Cache holding up to a number of entries. The least
+// recently used entry is evicted to make room, and expired entries are
+// dropped when they are read or reach the back of the list.
+type Memory struct {
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemory(capacity int) *Memory {
+	return &Memory{
+		capacity: max(capacity, 1),
+		now:      time.Now,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (memory *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	memory.mu.Lock()
+	defer memory.mu.Unlock()
+
+	element, ok := memory.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*entry)
+	if !memory.now().Before(entry.expiresAt) {
+		memory.remove(element)
+		return nil, false, nil
+	}
+
+	memory.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (memory *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	memory.mu.Lock()
+	defer memory.mu.Unlock()
+
+	expiresAt := memory.now().Add(ttl)
+	if element, ok := memory.entries[key]; ok {
+		entry := element.Value.(*entry)
+		entry.value, entry.expiresAt = value, expiresAt
+		memory.order.MoveToFront(element)
+		return nil
+	}
+
+	memory.entries[key] = memory.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for memory.order.Len() > memory.capacity {
+		memory.remove(memory.order.Back())
+	}
+
+	return nil
+}
+
+func (memory *Memory) Delete(ctx context.Context, keys ...string) error {
+	memory.mu.Lock()
+	defer memory.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := memory.entries[key]; ok {
+			memory.remove(element)
+		}
+	}
+
+	return nil
+}
+
+// Len returns how many entries are held, expired ones included.
+func (memory *Memory) Len() int {
+	memory.mu.Lock()
+	defer memory.mu.Unlock()
+	return memory.order.Len()
+}
+
+func (memory *Memory) remove(element *list.Element) {
+	memory.order.Remove(element)
+	delete(memory.entries, element.Value.(*entry).key)
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Cache on a server speaking the Redis protocol (Redis, Valkey,
+// KeyDB...), shared by every instance of the API.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects to the server at rawURL, written as
+// redis://[:password@]host[:port][/database], with up to poolSize
+// connections. Every command is bounded by timeout.
+func NewRedis(rawURL string, poolSize int, timeout time.Duration) (*Redis, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	options.PoolSize = max(poolSize, 1)
+	options.DialTimeout = timeout
+	options.ReadTimeout = timeout
+	options.WriteTimeout = timeout
+
+	client := redis.NewClient(options)
+
+	// Fail at startup rather than on the first request.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Redis{client: client}, nil
+}
+
+func (cache *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := cache.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (cache *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	// A ttl of zero would keep the value forever.
+	return cache.client.Set(ctx, key, value, max(ttl, time.Millisecond)).Err()
+}
+
+func (cache *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return cache.client.Del(ctx, keys...).Err()
+}
+
+// Close closes the connections.
+func (cache *Redis) Close() error {
+	return cache.client.Close()
+}
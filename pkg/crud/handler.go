@@ -0,0 +1,105 @@
+package crud
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Resource describes what the generic handlers serve.
+type Resource[ID any] struct {
+	// Name is the resource in logs, e.g. "news".
+	Name string
+	// ParseID reads the :id route parameter, e.g. IntID.
+	ParseID func(param string) (ID, error)
+	// NotFound, or an error wrapping it, is answered with 404.
+	NotFound error
+	// Messages replace the default messages of the error responses by status
+	// code: "Bad Request", "Not Found" and "Internal Server Error".
+	Messages map[int]string
+}
+
+func (resource Resource[ID]) respond(context *fiber.Ctx, statusCode int, data any, message string) error {
+	if custom, ok := resource.Messages[statusCode]; ok {
+		message = custom
+	}
+	return response.JSONResponse(context, statusCode, message, data)
+}
+
+// fail answers err, which is NotFound or an unexpected error.
+func (resource Resource[ID]) fail(context *fiber.Ctx, err error) error {
+	if resource.NotFound != nil && errors.Is(err, resource.NotFound) {
+		return resource.respond(context, 404, nil, "Not Found")
+	}
+
+	log.Printf("Error handling %s request: %v", resource.Name, err)
+	return resource.respond(context, 500, nil, "Internal Server Error")
+}
+
+// id parses the :id route parameter, answering 400 when it is invalid.
+func (resource Resource[ID]) id(context *fiber.Ctx) (ID, bool, error) {
+	id, err := resource.ParseID(context.Params("id"))
+	if err != nil {
+		return id, false, resource.respond(context, 400, nil, "Bad Request")
+	}
+	return id, true, nil
+}
+
+// IntID parses positive integer IDs.
+func IntID(param string) (int, error) {
+	id, err := strconv.Atoi(param)
+	if err == nil && id < 1 {
+		err = errors.New("id must be positive")
+	}
+	return id, err
+}
+
+// List returns a handler answering what list returns with message.
+func List[T any, ID any](resource Resource[ID], message string, list func() (T, error)) fiber.Handler {
+	return func(context *fiber.Ctx) error {
+		items, err := list()
+		if err != nil {
+			return resource.fail(context, err)
+		}
+
+		return response.JSONResponse(context, 200, message, items)
+	}
+}
+
+// Get returns a handler answering the item get returns for the :id route
+// parameter with message.
+func Get[T any, ID any](resource Resource[ID], message string, get func(id ID) (T, error)) fiber.Handler {
+	return func(context *fiber.Ctx) error {
+		id, ok, err := resource.id(context)
+		if !ok {
+			return err
+		}
+
+		item, err := get(id)
+		if err != nil {
+			return resource.fail(context, err)
+		}
+
+		return response.JSONResponse(context, 200, message, item)
+	}
+}
+
+// Delete returns a handler calling remove with the :id route parameter and
+// answering message.
+func Delete[ID any](resource Resource[ID], message string, remove func(id ID) error) fiber.Handler {
+	return func(context *fiber.Ctx) error {
+		id, ok, err := resource.id(context)
+		if !ok {
+			return err
+		}
+
+		if err := remove(id); err != nil {
+			return resource.fail(context, err)
+		}
+
+		return response.JSONResponse(context, 200, message, nil)
+	}
+}
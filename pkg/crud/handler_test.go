@@ -0,0 +1,85 @@
+package crud_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+)
+
+var noteResource = crud.Resource[int]{
+	Name:     "note",
+	ParseID:  crud.IntID,
+	NotFound: errNoteNotFound,
+	Messages: map[int]string{404: "Note Not Found"},
+}
+
+func call(t *testing.T, app *fiber.App, method string, target string) (int, response.Response) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(method, target, nil))
+	assert.NoError(t, err)
+
+	var body response.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestHandlers(t *testing.T) {
+	notes := map[int]note{1: {ID: 1, Title: "Hello"}}
+	failing := errors.New("connection reset")
+
+	app := fiber.New()
+	app.Get("/notes", crud.List(noteResource, "Get Notes Success", func() ([]note, error) {
+		return []note{notes[1]}, nil
+	}))
+	app.Get("/notes/:id", crud.Get(noteResource, "Success", func(id int) (*note, error) {
+		if id == 500 {
+			return nil, failing
+		}
+		if item, ok := notes[id]; ok {
+			return &item, nil
+		}
+		// Services wrap the errors of repositories.
+		return nil, fmt.Errorf("error getting note: %w", errNoteNotFound)
+	}))
+	app.Delete("/notes/:id", crud.Delete(noteResource, "Deleted", func(id int) error {
+		if _, ok := notes[id]; !ok {
+			return errNoteNotFound
+		}
+		delete(notes, id)
+		return nil
+	}))
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		status  int
+		message string
+	}{
+		{"list", "GET", "/notes", 200, "Get Notes Success"},
+		{"get", "GET", "/notes/1", 200, "Success"},
+		{"invalid id", "GET", "/notes/abc", 400, "Bad Request"},
+		{"zero id", "GET", "/notes/0", 400, "Bad Request"},
+		{"wrapped not found", "GET", "/notes/2", 404, "Note Not Found"},
+		{"failure", "GET", "/notes/500", 500, "Internal Server Error"},
+		{"delete", "DELETE", "/notes/1", 200, "Deleted"},
+		{"delete again", "DELETE", "/notes/1", 404, "Note Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, app, tt.method, tt.target)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.message, body.Message)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package crud has the reads and deletes every module repeats: a generic
+// Repository over a table mapping, and generic handlers answering with
+// response.JSONResponse.
+package crud
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+// Scanner is a row, or rows positioned on one.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// Table maps the rows of a table, possibly joined with others, to T.
+type Table[T any] struct {
+	// Name is the table rows are selected from and deleted in.
+	Name string
+	// Alias, when set, names the table in Columns and conditions, e.g. "n"
+	// in "n.id".
+	Alias string
+	// Joins follow the table in SELECTs, e.g. "JOIN users u ON n.user_id = u.id".
+	Joins string
+	// Key is the primary key column, without the alias.
+	Key string
+	// Columns are selected in order and read by Scan.
+	Columns []string
+	// GroupBy is needed when Columns aggregate joined rows.
+	GroupBy string
+	// OrderBy is the order of lists, unless one is given.
+	OrderBy string
+	// Scan reads Columns into item.
+	Scan func(row Scanner, item *T) error
+	// NotFound is returned when no row matches.
+	NotFound error
+}
+
+// key is Key as conditions refer to it.
+func (table *Table[T]) key() string {
+	if table.Alias == "" {
+		return table.Key
+	}
+	return table.Alias + "." + table.Key
+}
+
+// query selects the rows matching condition, all of them when it is empty.
+func (table *Table[T]) query(condition string, orderBy string) string {
+	var query strings.Builder
+	query.WriteString(`SELECT ` + strings.Join(table.Columns, ", ") + ` FROM ` + table.Name)
+	if table.Alias != "" {
+		query.WriteString(` ` + table.Alias)
+	}
+	if table.Joins != "" {
+		query.WriteString(` ` + table.Joins)
+	}
+	if condition != "" {
+		query.WriteString(` WHERE ` + condition)
+	}
+	if table.GroupBy != "" {
+		query.WriteString(` GROUP BY ` + table.GroupBy)
+	}
+	if orderBy != "" {
+		query.WriteString(` ORDER BY ` + orderBy)
+	}
+	return query.String()
+}
+
+// Repository reads and deletes the rows of a Table by their ID. Modules
+// embed it in their repositories and write the rest of their queries
+// themselves.
+type Repository[T any, ID comparable] struct {
+	// db is the database the repository was built on, or the transaction it
+	// is bound to.
+	db database.Querier
+	// reads run lists: on db, or on the read replicas once Replicated.
+	reads database.Querier
+	table Table[T]
+}
+
+func New[T any, ID comparable](db *sql.DB, table Table[T]) *Repository[T, ID] {
+	return &Repository[T, ID]{db: db, reads: db, table: table}
+}
+
+// Replicated returns a copy of the repository running lists on reads, e.g.
+// the read replicas of its database, for lists that can lag behind the
+// latest changes.
+func (repository *Repository[T, ID]) Replicated(reads database.Querier) *Repository[T, ID] {
+	replicated := *repository
+	replicated.reads = reads
+	return &replicated
+}
+
+// With returns a copy of the repository running its queries on q, e.g. the
+// transaction of a repository's WithTx.
+func (repository *Repository[T, ID]) With(q database.Querier) *Repository[T, ID] {
+	bound := *repository
+	bound.db, bound.reads = q, q
+	return &bound
+}
+
+// Get returns the row with the id, or Table.NotFound.
+func (repository *Repository[T, ID]) Get(id ID) (*T, error) {
+	return repository.Find(repository.table.key()+` = $1`, id)
+}
+
+// Find returns the first row matching condition, or Table.NotFound.
+func (repository *Repository[T, ID]) Find(condition string, args ...any) (*T, error) {
+	var item T
+	if err := repository.table.Scan(repository.db.QueryRow(repository.table.query(condition, ""), args...), &item); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.table.NotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// All returns every row, in Table.OrderBy.
+func (repository *Repository[T, ID]) All() ([]T, error) {
+	return repository.Where("", "")
+}
+
+// Where returns the rows matching condition in orderBy, or in Table.OrderBy
+// when it is empty.
+func (repository *Repository[T, ID]) Where(condition string, orderBy string, args ...any) ([]T, error) {
+	if orderBy == "" {
+		orderBy = repository.table.OrderBy
+	}
+
+	rows, err := repository.reads.Query(repository.table.query(condition, orderBy), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := repository.table.Scan(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Delete deletes the row with the id, or returns Table.NotFound.
+func (repository *Repository[T, ID]) Delete(id ID) error {
+	result, err := repository.db.Exec(`DELETE FROM `+repository.table.Name+` WHERE `+repository.table.Key+` = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return ExpectOne(result, repository.table.NotFound)
+}
+
+// ExpectOne turns a change that matched no row into notFound.
+func ExpectOne(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package crud_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/crud"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+type note struct {
+	ID     int
+	Title  string
+	Author string
+}
+
+var errNoteNotFound = errors.New("note not found")
+
+var noteTable = crud.Table[note]{
+	Name:     "notes",
+	Alias:    "n",
+	Joins:    "JOIN users u ON n.user_id = u.id",
+	Key:      "id",
+	Columns:  []string{"n.id", "n.title", "u.name"},
+	OrderBy:  "n.id",
+	Scan:     func(row crud.Scanner, item *note) error { return row.Scan(&item.ID, &item.Title, &item.Author) },
+	NotFound: errNoteNotFound,
+}
+
+func TestRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	notes := crud.New[note, int](db, noteTable)
+
+	t.Run("found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT n.id, n.title, u.name FROM notes n JOIN users u ON n.user_id = u.id WHERE n.id = \$1$`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "name"}).AddRow(1, "Hello", "Ammar"))
+
+		item, err := notes.Get(1)
+		assert.NoError(t, err)
+		assert.Equal(t, &note{ID: 1, Title: "Hello", Author: "Ammar"}, item)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE n.title = \$1`).
+			WithArgs("Missing").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "name"}))
+
+		_, err := notes.Find(`n.title = $1`, "Missing")
+		assert.ErrorIs(t, err, errNoteNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Where(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	replica, replicaMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer replica.Close()
+
+	notes := crud.New[note, int](db, noteTable)
+
+	// The lists of a replicated repository run on the replica.
+	replicaMock.ExpectQuery(`SELECT n.id, n.title, u.name FROM notes n JOIN users u ON n.user_id = u.id ORDER BY n.id$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "name"}).AddRow(1, "Hello", "Ammar").AddRow(2, "World", "Sholum"))
+
+	all, err := notes.Replicated(replica).All()
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+
+	mock.ExpectQuery(`WHERE u.name = \$1 ORDER BY n.title DESC$`).
+		WithArgs("Nobody").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "name"}))
+
+	none, err := notes.Where(`u.name = $1`, "n.title DESC", "Nobody")
+	assert.NoError(t, err)
+	assert.Equal(t, []note{}, none)
+
+	mock.ExpectQuery(`FROM notes`).WillReturnError(errors.New("connection reset"))
+
+	_, err = notes.All()
+	assert.EqualError(t, err, "connection reset")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	notes := crud.New[note, int](db, noteTable)
+
+	mock.ExpectExec(`DELETE FROM notes WHERE id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, notes.Delete(1))
+
+	mock.ExpectExec(`DELETE FROM notes WHERE id = \$1`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, notes.Delete(2), errNoteNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_With(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	notes := crud.New[note, int](db, noteTable)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM notes`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = database.NewTxManager(db).Run(t.Context(), func(tx *database.Tx) error {
+		return notes.With(tx).Delete(1)
+	})
+	assert.ErrorIs(t, err, errNoteNotFound)
+	assert.NotErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+// Package databasetest runs repositories against a real database in tests,
+// without external services.
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ahmadammarm/go-rest-api-template/migrations"
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+// SQLite returns a new in-memory SQLite database with every migration
+// applied, closed when the test ends.
+func SQLite(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, err := database.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatalf("opening SQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := database.Migrate(context.Background(), db, migrations.SQLite()); err != nil {
+		t.Fatalf("migrating SQLite: %v", err)
+	}
+
+	return db
+}
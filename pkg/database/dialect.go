@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is the SQL flavour of a database. Repositories write their queries
+// for Postgres, with $1 placeholders and RETURNING clauses, and the
+// connections of other dialects adapt them: see OpenSQLite.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// ParseDialect returns the dialect called name, e.g. by the DB_DRIVER
+// setting.
+func ParseDialect(name string) (Dialect, error) {
+	switch dialect := Dialect(strings.ToLower(strings.TrimSpace(name))); dialect {
+	case Postgres, SQLite:
+		return dialect, nil
+	default:
+		return "", fmt.Errorf("unknown database driver %q", name)
+	}
+}
+
+// Rebind rewrites the $n placeholders of a Postgres query into those of
+// dialect: ?n for SQLite, which binds the same argument wherever $n is
+// repeated. Quoted strings and identifiers are left alone.
+func (dialect Dialect) Rebind(query string) string {
+	if dialect != SQLite || !strings.Contains(query, "$") {
+		return query
+	}
+
+	var rebound strings.Builder
+	rebound.Grow(len(query))
+
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			// A doubled quote escapes itself and keeps the string open.
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			c = '?'
+		}
+		rebound.WriteByte(c)
+	}
+
+	return rebound.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,40 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+func TestParseDialect(t *testing.T) {
+	dialect, err := database.ParseDialect(" SQLite ")
+	assert.NoError(t, err)
+	assert.Equal(t, database.SQLite, dialect)
+
+	_, err = database.ParseDialect("mysql")
+	assert.EqualError(t, err, `unknown database driver "mysql"`)
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"placeholders", `SELECT * FROM news WHERE ($1 = 0 OR user_id = $1) LIMIT $12`,
+			`SELECT * FROM news WHERE (?1 = 0 OR user_id = ?1) LIMIT ?12`},
+		{"strings", `UPDATE users SET password = '$2a$10''$3' WHERE id = $1`,
+			`UPDATE users SET password = '$2a$10''$3' WHERE id = ?1`},
+		{"identifiers", `SELECT "$1" FROM t WHERE a = $2`, `SELECT "$1" FROM t WHERE a = ?2`},
+		{"no placeholders", `SELECT $ FROM t`, `SELECT $ FROM t`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, database.SQLite.Rebind(tt.query))
+			assert.Equal(t, tt.query, database.Postgres.Rebind(tt.query))
+		})
+	}
+}
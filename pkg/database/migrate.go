@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Migrate applies the *.up.sql files of migrations that are not applied yet,
+// in name order, each in a transaction. Applied migrations are recorded in
+// the schema_migrations table by file name, without the .up.sql suffix, and
+// returned.
+func Migrate(ctx context.Context, db *sql.DB, migrations fs.FS) ([]string, error) {
+	return migrate(ctx, db, migrations, true)
+}
+
+// Baseline records the migrations that are not applied yet as applied,
+// without running them, for a database whose schema is already up to date,
+// e.g. one migrated by hand. It returns them.
+func Baseline(ctx context.Context, db *sql.DB, migrations fs.FS) ([]string, error) {
+	return migrate(ctx, db, migrations, false)
+}
+
+// migrate records the migrations that are not applied yet, running them
+// first when run is set.
+func migrate(ctx context.Context, db *sql.DB, migrations fs.FS, run bool) ([]string, error) {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version varchar(255) PRIMARY KEY,
+		applied_at timestamp DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	transactions := NewTxManager(db)
+	var applied []string
+	for _, file := range files {
+		version := strings.TrimSuffix(file, ".up.sql")
+
+		statements, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			return applied, err
+		}
+
+		var done bool
+		err = transactions.Run(ctx, func(tx *Tx) error {
+			var count int
+			if err := tx.QueryRowContext(ctx, `SELECT COUNT(1) FROM schema_migrations WHERE version = $1`, version).Scan(&count); err != nil {
+				return err
+			}
+			if done = count > 0; done {
+				return nil
+			}
+
+			if run {
+				if _, err := tx.ExecContext(ctx, string(statements)); err != nil {
+					return err
+				}
+			}
+
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %s: %w", version, err)
+		}
+
+		if !done {
+			applied = append(applied, version)
+		}
+	}
+
+	return applied, nil
+}
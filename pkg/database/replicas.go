@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Replicas routes reads to the read replicas of a primary database, in turn.
+// Replicas failing their health check are skipped, and reads go to the primary
+// when none is healthy. Writes always go to the primary. Repositories get it
+// as the Querier of the reads that can lag behind the latest changes.
+type Replicas struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+func NewReplicas(primary *sql.DB, replicas ...*sql.DB) *Replicas {
+	set := &Replicas{primary: primary}
+	for _, db := range replicas {
+		replica := &replica{db: db}
+		replica.healthy.Store(true)
+		set.replicas = append(set.replicas, replica)
+	}
+
+	return set
+}
+
+// Monitor checks the health of the replicas every interval until ctx is done.
+func (set *Replicas) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			set.Check(ctx, interval)
+		}
+	}
+}
+
+// Check pings every replica within timeout, and marks whether it is healthy.
+func (set *Replicas) Check(ctx context.Context, timeout time.Duration) {
+	for i, replica := range set.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := replica.db.PingContext(pingCtx)
+		cancel()
+
+		if healthy := err == nil; replica.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Printf("Read replica %d is back", i)
+			} else {
+				log.Printf("Read replica %d is down, reading from the primary: %v", i, err)
+			}
+		}
+	}
+}
+
+// pick returns the next healthy replica, or the primary and nil.
+func (set *Replicas) pick() (*sql.DB, *replica) {
+	for range set.replicas {
+		replica := set.replicas[set.next.Add(1)%uint64(len(set.replicas))]
+		if replica.healthy.Load() {
+			return replica.db, replica
+		}
+	}
+
+	return set.primary, nil
+}
+
+// unreachable tells whether err comes from failing to reach the database,
+// rather than from the server rejecting the query.
+func unreachable(ctx context.Context, err error) bool {
+	var sqlErr interface{ SQLState() string }
+	return err != nil && ctx.Err() == nil && !errors.As(err, &sqlErr)
+}
+
+func (set *Replicas) Query(query string, args ...any) (*sql.Rows, error) {
+	return set.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext runs query on a replica, or on the primary when the replica
+// cannot be reached. That replica is then skipped until its next health
+// check.
+func (set *Replicas) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	db, replica := set.pick()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if replica != nil && unreachable(ctx, err) {
+		replica.healthy.Store(false)
+		log.Printf("Read replica unreachable, reading from the primary: %v", err)
+		return set.primary.QueryContext(ctx, query, args...)
+	}
+
+	return rows, err
+}
+
+func (set *Replicas) QueryRow(query string, args ...any) *sql.Row {
+	return set.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext runs query on a replica. Its errors are only known when
+// the row is scanned, so it does not fall back to the primary.
+func (set *Replicas) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	db, _ := set.pick()
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+func (set *Replicas) Exec(query string, args ...any) (sql.Result, error) {
+	return set.primary.Exec(query, args...)
+}
+
+func (set *Replicas) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return set.primary.ExecContext(ctx, query, args...)
+}
@@ -0,0 +1,71 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+func TestReplicas(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer primary.Close()
+
+	replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer replica.Close()
+
+	replicas := database.NewReplicas(primary, replica)
+	var reads database.Querier = replicas
+
+	// Reads go to the replica, server errors included.
+	replicaMock.ExpectQuery("SELECT id FROM news").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows, err := reads.Query("SELECT id FROM news")
+	assert.NoError(t, err)
+	rows.Close()
+
+	replicaMock.ExpectQuery("SELECT id FROM news").WillReturnError(&pq.Error{Code: "42P01"})
+	_, err = reads.Query("SELECT id FROM news")
+	assert.Error(t, err)
+
+	// Writes go to the primary.
+	primaryMock.ExpectExec("DELETE FROM news").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = reads.Exec("DELETE FROM news")
+	assert.NoError(t, err)
+
+	// An unreachable replica falls back to the primary until it is healthy
+	// again.
+	replicaMock.ExpectQuery("SELECT id FROM news").WillReturnError(errors.New("connection reset by peer"))
+	primaryMock.ExpectQuery("SELECT id FROM news").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	primaryMock.ExpectQuery("SELECT id FROM news").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for range 2 {
+		rows, err = reads.Query("SELECT id FROM news")
+		assert.NoError(t, err)
+		rows.Close()
+	}
+
+	replicaMock.ExpectPing()
+	replicas.Check(context.Background(), time.Second)
+	replicaMock.ExpectQuery("SELECT id FROM news").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows, err = reads.Query("SELECT id FROM news")
+	assert.NoError(t, err)
+	rows.Close()
+
+	// A replica failing its health check is skipped.
+	replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	replicas.Check(context.Background(), time.Second)
+	primaryMock.ExpectQuery("SELECT id FROM news").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows, err = reads.Query("SELECT id FROM news")
+	assert.NoError(t, err)
+	rows.Close()
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteTimeFormat is how times are written to SQLite: in UTC and with a fixed
+// width, so that they compare as text the way Postgres timestamps compare.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000"
+
+// sqliteBase is the driver the sqlite package registers with database/sql,
+// whose connections have the functions registered in that package.
+var sqliteBase driver.Driver
+
+func init() {
+	// NOW() is Postgres' current time, which queries compare timestamps with.
+	sqlite.MustRegisterScalarFunction("now", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(sqliteTimeFormat), nil
+	})
+
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sqliteBase = db.Driver()
+	db.Close()
+}
+
+// OpenSQLite opens the SQLite database file at path, or a new in-memory
+// database for ":memory:", for local development and tests.
+//
+// Its connections run the queries written for Postgres: $n placeholders are
+// rebound, times are written in UTC and NOW() is defined. RETURNING is native
+// to the bundled SQLite, and foreign keys are enforced as in Postgres. Queries
+// relying on other Postgres features, such as INTERVAL or SKIP LOCKED, fail.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db := sql.OpenDB(sqliteConnector{dsn: path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"})
+
+	// SQLite has a single writer, and an in-memory database lives as long as
+	// its connection, which is therefore kept.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+type sqliteConnector struct {
+	dsn string
+}
+
+func (connector sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	return connector.Driver().Open(connector.dsn)
+}
+
+func (connector sqliteConnector) Driver() driver.Driver {
+	return &sqliteDriver{}
+}
+
+type sqliteDriver struct{}
+
+func (*sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := sqliteBase.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteConn{conn.(sqliteDriverConn)}, nil
+}
+
+// sqliteDriverConn is what the SQLite driver's connections implement.
+type sqliteDriverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// sqliteConn rebinds the queries and converts the arguments of a SQLite
+// connection.
+type sqliteConn struct {
+	sqliteDriverConn
+}
+
+func (conn *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return conn.sqliteDriverConn.Prepare(SQLite.Rebind(query))
+}
+
+func (conn *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return conn.sqliteDriverConn.PrepareContext(ctx, SQLite.Rebind(query))
+}
+
+func (conn *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.sqliteDriverConn.ExecContext(ctx, SQLite.Rebind(query), args)
+}
+
+func (conn *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return conn.sqliteDriverConn.QueryContext(ctx, SQLite.Rebind(query), args)
+}
+
+// CheckNamedValue converts arguments as database/sql does, then writes times
+// in sqliteTimeFormat, whether they were given as a time.Time or a Valuer
+// such as sql.NullTime.
+func (conn *sqliteConn) CheckNamedValue(arg *driver.NamedValue) error {
+	value, err := driver.DefaultParameterConverter.ConvertValue(arg.Value)
+	if err != nil {
+		return err
+	}
+
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(sqliteTimeFormat)
+	}
+	arg.Value = value
+
+	return nil
+}
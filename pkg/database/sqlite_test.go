@@ -0,0 +1,126 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+func TestOpenSQLite(t *testing.T) {
+	db, err := database.OpenSQLite(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE events (id integer PRIMARY KEY, name text NOT NULL, at timestamp)`)
+	assert.NoError(t, err)
+
+	t.Run("runs Postgres queries", func(t *testing.T) {
+		var id int
+		err := db.QueryRow(`INSERT INTO events (name, at) VALUES ($1, $2) RETURNING id`, "past", time.Now().Add(-time.Hour)).Scan(&id)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		future := time.Now().Add(time.Hour)
+		_, err = db.Exec(`INSERT INTO events (name, at) VALUES ($1, $2)`, "future", &future)
+		assert.NoError(t, err)
+
+		var name string
+		err = db.QueryRow(`SELECT name FROM events WHERE at <= NOW() AND ($1 = '' OR name = $1)`, "").Scan(&name)
+		assert.NoError(t, err)
+		assert.Equal(t, "past", name)
+	})
+
+	t.Run("writes times in UTC", func(t *testing.T) {
+		at := time.Date(2025, 4, 10, 17, 13, 28, 934175000, time.FixedZone("CEST", 2*60*60))
+		_, err := db.Exec(`INSERT INTO events (name, at) VALUES ($1, $2)`, "utc", at)
+		assert.NoError(t, err)
+
+		var stored string
+		err = db.QueryRow(`SELECT CAST(at AS text) FROM events WHERE name = $1`, "utc").Scan(&stored)
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-04-10 15:13:28.934175", stored)
+
+		var read time.Time
+		err = db.QueryRow(`SELECT at FROM events WHERE name = $1`, "utc").Scan(&read)
+		assert.NoError(t, err)
+		assert.True(t, at.Equal(read))
+	})
+
+	t.Run("enforces foreign keys", func(t *testing.T) {
+		_, err := db.Exec(`CREATE TABLE tickets (event_id integer NOT NULL REFERENCES events(id))`)
+		assert.NoError(t, err)
+
+		_, err = db.Exec(`INSERT INTO tickets (event_id) VALUES ($1)`, 404)
+		assert.ErrorContains(t, err, "FOREIGN KEY constraint failed")
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := database.OpenSQLite(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrations := fstest.MapFS{
+		"000001_create_news.up.sql":   {Data: []byte(`CREATE TABLE news (id integer PRIMARY KEY); CREATE INDEX news_id_idx ON news (id);`)},
+		"000001_create_news.down.sql": {Data: []byte(`DROP TABLE news;`)},
+		"000002_add_title.up.sql":     {Data: []byte(`ALTER TABLE news ADD COLUMN title text;`)},
+	}
+
+	applied, err := database.Migrate(ctx, db, migrations)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"000001_create_news", "000002_add_title"}, applied)
+
+	_, err = db.Exec(`INSERT INTO news (title) VALUES ($1)`, "Hello")
+	assert.NoError(t, err)
+
+	t.Run("skips applied migrations", func(t *testing.T) {
+		migrations["000003_add_content.up.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE news ADD COLUMN content text;`)}
+
+		applied, err := database.Migrate(ctx, db, migrations)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"000003_add_content"}, applied)
+	})
+
+	t.Run("rolls a failed migration back", func(t *testing.T) {
+		migrations["000004_broken.up.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE news ADD COLUMN body text; SELECT * FROM missing;`)}
+
+		applied, err := database.Migrate(ctx, db, migrations)
+		assert.ErrorContains(t, err, "migration 000004_broken")
+		assert.Empty(t, applied)
+
+		_, err = db.Exec(`SELECT body FROM news`)
+		assert.Error(t, err)
+	})
+}
+
+func TestBaseline(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := database.OpenSQLite(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrations := fstest.MapFS{
+		"000001_create_news.up.sql": {Data: []byte(`CREATE TABLE news (id integer PRIMARY KEY);`)},
+		"000002_add_title.up.sql":   {Data: []byte(`ALTER TABLE news ADD COLUMN title text;`)},
+	}
+
+	_, err = db.Exec(`CREATE TABLE news (id integer PRIMARY KEY, title text)`)
+	assert.NoError(t, err)
+
+	recorded, err := database.Baseline(ctx, db, migrations)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"000001_create_news", "000002_add_title"}, recorded)
+
+	migrations["000003_add_content.up.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE news ADD COLUMN content text;`)}
+	applied, err := database.Migrate(ctx, db, migrations)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"000003_add_content"}, applied)
+}
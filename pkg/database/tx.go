@@ -0,0 +1,146 @@
+// Package database runs repository queries: in transactions, on read
+// replicas, and on Postgres or SQLite.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Querier is what repositories run their queries on. *sql.DB, *sql.Tx and
+// *Tx implement it, so a repository works alike inside and outside a
+// transaction.
+type Querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// MaxAttempts is how many times a transaction is run when it keeps failing
+// with a serialization failure or a deadlock.
+const MaxAttempts = 3
+
+// TxManager runs functions in transactions, for services to make several
+// repository calls atomically.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// Tx is a transaction run by a TxManager. Repositories bound to it with their
+// WithTx method run their queries in it.
+type Tx struct {
+	*sql.Tx
+	savepoints  int
+	afterCommit []func()
+}
+
+// Run calls fn in a transaction, which is committed when fn returns nil and
+// rolled back when it returns an error or panics.
+//
+// When the transaction fails with a serialization failure or a deadlock, fn
+// is called again in a new transaction, up to MaxAttempts times. It must
+// therefore leave effects outside the database to AfterCommit.
+func (manager *TxManager) Run(ctx context.Context, fn func(tx *Tx) error) error {
+	return manager.RunWith(ctx, nil, fn)
+}
+
+// RunWith is Run with options, e.g. the serializable isolation level.
+func (manager *TxManager) RunWith(ctx context.Context, options *sql.TxOptions, fn func(tx *Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		if err = manager.run(ctx, options, fn); !retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (manager *TxManager) run(ctx context.Context, options *sql.TxOptions, fn func(tx *Tx) error) (err error) {
+	sqlTx, err := manager.db.BeginTx(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	tx := &Tx{Tx: sqlTx}
+	defer func() {
+		if pan := recover(); pan != nil {
+			_ = sqlTx.Rollback()
+			panic(pan)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		return err
+	}
+
+	for _, callback := range tx.afterCommit {
+		callback()
+	}
+
+	return nil
+}
+
+// Run calls fn in a savepoint of tx. When fn returns an error or panics, what
+// it did is rolled back and the rest of the transaction can go on.
+func (tx *Tx) Run(fn func(tx *Tx) error) (err error) {
+	tx.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", tx.savepoints)
+	if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
+		return err
+	}
+
+	callbacks := len(tx.afterCommit)
+	rollback := func() {
+		_, _ = tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint)
+		tx.afterCommit = tx.afterCommit[:callbacks]
+	}
+
+	defer func() {
+		if pan := recover(); pan != nil {
+			rollback()
+			panic(pan)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		rollback()
+		return err
+	}
+
+	_, err = tx.Exec("RELEASE SAVEPOINT " + savepoint)
+	return err
+}
+
+// AfterCommit registers callback to run once tx has committed, e.g. to drop
+// cache entries. It is not run when tx, or the savepoint it was registered
+// in, is rolled back.
+func (tx *Tx) AfterCommit(callback func()) {
+	tx.afterCommit = append(tx.afterCommit, callback)
+}
+
+// retryable tells whether err is a serialization failure or a deadlock,
+// after which the transaction can succeed when run again.
+func retryable(err error) bool {
+	var sqlErr interface{ SQLState() string }
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+
+	state := sqlErr.SQLState()
+	return state == "40001" || state == "40P01"
+}
@@ -0,0 +1,141 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/database"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commits", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO news").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		committed := false
+		err = database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+			tx.AfterCommit(func() { committed = true })
+			_, err := tx.Exec("INSERT INTO news (title) VALUES ($1)", "Hello")
+			return err
+		})
+		assert.NoError(t, err)
+		assert.True(t, committed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		committed := false
+		err = database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+			tx.AfterCommit(func() { committed = true })
+			return errors.New("news not found")
+		})
+		assert.EqualError(t, err, "news not found")
+		assert.False(t, committed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		err = database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+			attempts++
+			_, err := tx.Exec("UPDATE users SET name = $1", "Ann")
+			return err
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("gives up after MaxAttempts", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		for range database.MaxAttempts {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+
+		err = database.NewTxManager(db).Run(ctx, func(tx *database.Tx) error {
+			return &pq.Error{Code: "40P01"}
+		})
+		assert.Equal(t, &pq.Error{Code: "40P01"}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSavepoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var callbacks []string
+	err = database.NewTxManager(db).Run(context.Background(), func(tx *database.Tx) error {
+		err := tx.Run(func(tx *database.Tx) error {
+			tx.AfterCommit(func() { callbacks = append(callbacks, "rolled back") })
+			return errors.New("tag already exists")
+		})
+		assert.EqualError(t, err, "tag already exists")
+
+		return tx.Run(func(tx *database.Tx) error {
+			tx.AfterCommit(func() { callbacks = append(callbacks, "released") })
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"released"}, callbacks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package idempotency_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/idempotency"
+)
+
+func TestBegin(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"fingerprint", "status_code", "content_type", "body"}
+
+	tests := []struct {
+		name     string
+		stored   []driver.Value
+		want     *idempotency.Response
+		wantErr  error
+		released bool
+	}{
+		{name: "new key"},
+		{name: "completed", stored: []driver.Value{"abc", 201, "application/json", []byte(`{}`)},
+			want: &idempotency.Response{StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`)}},
+		{name: "in progress", stored: []driver.Value{"abc", nil, "", nil}, wantErr: idempotency.ErrInProgress},
+		{name: "different request", stored: []driver.Value{"def", 201, "application/json", []byte(`{}`)}, wantErr: idempotency.ErrMismatch},
+		{name: "released in between", released: true, wantErr: idempotency.ErrInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			claim := mock.ExpectQuery("INSERT INTO idempotency_keys").
+				WithArgs("scope", "key", "abc", int64(60000), int64(86400000))
+			switch {
+			case tt.stored != nil:
+				claim.WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT fingerprint, status_code, content_type, body FROM idempotency_keys").
+					WithArgs("scope", "key").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(tt.stored...))
+			case tt.released:
+				claim.WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT fingerprint").WillReturnRows(sqlmock.NewRows(columns))
+			default:
+				claim.WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+			}
+
+			response, err := idempotency.NewStore(db).Begin(ctx, "scope", "key", "abc", 24*time.Hour, time.Minute)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCompleteAndRelease(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	store := idempotency.NewStore(db)
+
+	mock.ExpectExec("UPDATE idempotency_keys").
+		WithArgs(201, "application/json", []byte(`{}`), "scope", "key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.Complete(ctx, "scope", "key", idempotency.Response{StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`)}))
+
+	mock.ExpectExec("DELETE FROM idempotency_keys (.+) status_code IS NULL").
+		WithArgs("scope", "other").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.Release(ctx, "scope", "other"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
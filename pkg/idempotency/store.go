@@ -0,0 +1,114 @@
+// Package idempotency records the responses to requests sent with an
+// idempotency key, so that repeating a request does not repeat its effects.
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrMismatch is returned when a key is reused for a different request.
+	ErrMismatch = errors.New("idempotency key reused with a different request")
+	// ErrInProgress is returned when the first request with a key has not
+	// completed yet.
+	ErrInProgress = errors.New("request with this idempotency key in progress")
+)
+
+// Response is what is replayed to a repeated request.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Begin claims key within scope for a request with fingerprint, for lease at
+// most. It returns nil when the request should run, then be completed with
+// Complete or Release. When the key was already used for the same request it
+// returns that response instead.
+//
+// Keys are kept for ttl. A key whose request has not completed when its lease
+// runs out, because the instance handling it stopped, can be claimed again.
+func (store *Store) Begin(ctx context.Context, scope, key, fingerprint string, ttl, lease time.Duration) (*Response, error) {
+	var claimed bool
+	err := store.db.QueryRowContext(ctx, `INSERT INTO idempotency_keys (scope, key, fingerprint, locked_until, expires_at)
+              VALUES ($1, $2, $3, NOW() + $4 * INTERVAL '1 millisecond', NOW() + $5 * INTERVAL '1 millisecond')
+              ON CONFLICT (scope, key) DO UPDATE
+              SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = '', body = NULL,
+                  locked_until = EXCLUDED.locked_until, created_at = NOW(), expires_at = EXCLUDED.expires_at
+              WHERE idempotency_keys.expires_at <= NOW()
+                 OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= NOW()
+                     AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+              RETURNING true`,
+		scope, key, fingerprint, lease.Milliseconds(), ttl.Milliseconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// The key was used before. A concurrent upsert of the same key waits for
+	// this one, so only one request at a time can claim it.
+	var storedFingerprint string
+	var statusCode sql.NullInt64
+	var response Response
+	err = store.db.QueryRowContext(ctx, `SELECT fingerprint, status_code, content_type, body FROM idempotency_keys
+              WHERE scope = $1 AND key = $2`, scope, key).
+		Scan(&storedFingerprint, &statusCode, &response.ContentType, &response.Body)
+	if err == sql.ErrNoRows {
+		// Released in between: the other request failed and can be retried.
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if storedFingerprint != fingerprint {
+		return nil, ErrMismatch
+	}
+	if !statusCode.Valid {
+		return nil, ErrInProgress
+	}
+
+	response.StatusCode = int(statusCode.Int64)
+	return &response, nil
+}
+
+// Complete stores the response to the request holding key, to be replayed
+// until the key expires.
+func (store *Store) Complete(ctx context.Context, scope, key string, response Response) error {
+	_, err := store.db.ExecContext(ctx, `UPDATE idempotency_keys
+              SET status_code = $1, content_type = $2, body = $3
+              WHERE scope = $4 AND key = $5`,
+		response.StatusCode, response.ContentType, response.Body, scope, key)
+	return err
+}
+
+// Release frees key after its request failed without effect, so that it can
+// be retried.
+func (store *Store) Release(ctx context.Context, scope, key string) error {
+	_, err := store.db.ExecContext(ctx, `DELETE FROM idempotency_keys
+              WHERE scope = $1 AND key = $2 AND status_code IS NULL`, scope, key)
+	return err
+}
+
+// Prune deletes the expired keys, and returns how many it deleted.
+func Prune(ctx context.Context, db *sql.DB) (int64, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package imageresize
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const jpegQuality = 85
+
+// MaxSide and MaxPixels bound the images Decode accepts. A small file can
+// declare an enormous image, which decoding would allocate in full.
+const (
+	MaxSide   = 10000
+	MaxPixels = 40_000_000
+)
+
+// ErrTooLarge is returned for images whose declared size is over the limits.
+var ErrTooLarge = errors.New("image too large")
+
+// Decode decodes data, rejecting images over MaxSide or MaxPixels.
+func Decode(data []byte) (image.Image, error) {
+	return DecodeWithin(data, MaxSide, MaxPixels)
+}
+
+// DecodeWithin decodes data, rejecting images with a side over maxSide or
+// more than maxPixels pixels before decoding them.
+func DecodeWithin(data []byte, maxSide int, maxPixels int) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width > maxSide || config.Height > maxSide || config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Fit scales src down so that it fits inside maxWidth x maxHeight while
+// keeping its aspect ratio. Images that already fit are returned unchanged.
+func Fit(src image.Image, maxWidth int, maxHeight int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxWidth && height <= maxHeight {
+		return src
+	}
+
+	if width*maxHeight > height*maxWidth {
+		height = max(1, height*maxWidth/width)
+		width = maxWidth
+	} else {
+		width = max(1, width*maxHeight/height)
+		height = maxHeight
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Square crops the center of src to a square and scales it to size x size.
+func Square(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	return dst
+}
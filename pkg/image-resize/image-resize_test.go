@@ -0,0 +1,50 @@
+package imageresize_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	imageresize "github.com/ahmadammarm/go-rest-api-template/pkg/image-resize"
+)
+
+// pngHeader returns the signature and IHDR chunk of a PNG declaring a width
+// x height RGBA image, without any pixel data.
+func pngHeader(width uint32, height uint32) []byte {
+	ihdr := binary.BigEndian.AppendUint32([]byte("IHDR"), width)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, height)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+
+	header := []byte("\x89PNG\r\n\x1a\n")
+	header = binary.BigEndian.AppendUint32(header, uint32(len(ihdr)-4))
+	header = append(header, ihdr...)
+	return binary.BigEndian.AppendUint32(header, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestDecode(t *testing.T) {
+	var small bytes.Buffer
+	assert.NoError(t, png.Encode(&small, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	img, err := imageresize.Decode(small.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+
+	t.Run("rejects a declared size over the limits", func(t *testing.T) {
+		_, err := imageresize.Decode(pngHeader(100000, 100000))
+		assert.ErrorIs(t, err, imageresize.ErrTooLarge)
+		assert.EqualError(t, err, "image too large: 100000x100000")
+
+		_, err = imageresize.Decode(pngHeader(9000, 9000))
+		assert.ErrorIs(t, err, imageresize.ErrTooLarge)
+	})
+
+	t.Run("within custom limits", func(t *testing.T) {
+		_, err := imageresize.DecodeWithin(small.Bytes(), 30, 1000)
+		assert.ErrorIs(t, err, imageresize.ErrTooLarge)
+	})
+}
@@ -0,0 +1,243 @@
+package jobs_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/jobs"
+)
+
+var jobColumns = []string{"id", "type", "payload", "priority", "status", "attempts", "max_attempts", "run_at", "last_error", "created_at", "updated_at"}
+
+var config = jobs.Config{
+	Concurrency:  1,
+	PollInterval: time.Millisecond,
+	Timeout:      time.Second,
+	BaseBackoff:  10 * time.Second,
+	MaxBackoff:   time.Minute,
+}
+
+func jobRow(id int64, jobType string, payload string, attempts int, maxAttempts int) *sqlmock.Rows {
+	return sqlmock.NewRows(jobColumns).
+		AddRow(id, jobType, []byte(payload), 0, jobs.StatusRunning, attempts, maxAttempts, time.Now(), "", time.Now(), time.Now())
+}
+
+// sameWorker matches the ID of a worker: any at first, then the same one.
+type sameWorker struct {
+	id *string
+}
+
+func (worker sameWorker) Match(value driver.Value) bool {
+	id, ok := value.(string)
+	if !ok || id == "" {
+		return false
+	}
+	if *worker.id == "" {
+		*worker.id = id
+	}
+	return id == *worker.id
+}
+
+type sendEmail struct {
+	To string `json:"to"`
+}
+
+func TestEnqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	queue := jobs.NewQueue(db, 5)
+
+	t.Run("defaults", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO jobs \\(type, payload, priority, max_attempts, run_at\\)").
+			WithArgs("email.send", []byte(`{"to":"a@example.com"}`), 0, 5, int64(0)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		id, err := queue.Enqueue(context.Background(), "email.send", sendEmail{To: "a@example.com"}, jobs.Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO jobs").
+			WithArgs("email.send", []byte(`{"to":""}`), 10, 2, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		_, err := queue.Enqueue(context.Background(), "email.send", sendEmail{}, jobs.Options{
+			Priority:    10,
+			RunAt:       time.Now().Add(time.Hour),
+			MaxAttempts: 2,
+		})
+		assert.NoError(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunNext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	queue := jobs.NewQueue(db, 5)
+	worker := jobs.NewWorker(queue, config)
+
+	var sent []string
+	jobs.Register(queue, "email.send", func(ctx context.Context, payload sendEmail) error {
+		if payload.To == "" {
+			return errors.New("no recipient")
+		}
+		sent = append(sent, payload.To)
+		return nil
+	})
+
+	self := sameWorker{id: new(string)}
+	claim := func(rows *sqlmock.Rows) {
+		mock.ExpectQuery("UPDATE jobs\\s+SET status = 'running', attempts = attempts \\+ 1(.+) locked_by = \\$3(.+)" +
+			"OR \\(status = 'running' AND locked_until < NOW\\(\\) AND attempts < max_attempts\\)(.+) FOR UPDATE SKIP LOCKED").
+			WithArgs(pq.Array([]string{"email.send"}), int64(2000), self).
+			WillReturnRows(rows)
+	}
+
+	t.Run("nothing due", func(t *testing.T) {
+		claim(sqlmock.NewRows(jobColumns))
+
+		ran, err := worker.RunNext(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, ran)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		claim(jobRow(1, "email.send", `{"to":"a@example.com"}`, 1, 5))
+		mock.ExpectExec("UPDATE jobs SET status = 'succeeded'(.+) WHERE id = \\$1 AND status = 'running' AND locked_by = \\$2").
+			WithArgs(int64(1), self).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ran, err := worker.RunNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, []string{"a@example.com"}, sent)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		claim(jobRow(2, "email.send", `{}`, 2, 5))
+		mock.ExpectExec("UPDATE jobs SET status = \\$1, locked_until = NULL, locked_by = NULL, last_error = \\$2").
+			WithArgs(jobs.StatusPending, "no recipient", int64(20000), int64(2), self).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ran, err := worker.RunNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, ran)
+	})
+
+	t.Run("dead after the last attempt", func(t *testing.T) {
+		claim(jobRow(2, "email.send", `{}`, 5, 5))
+		mock.ExpectExec("UPDATE jobs SET status = \\$1").
+			WithArgs(jobs.StatusDead, "no recipient", sqlmock.AnyArg(), int64(2), self).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := worker.RunNext(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("payload that does not decode", func(t *testing.T) {
+		claim(jobRow(3, "email.send", `[]`, 1, 5))
+		mock.ExpectExec("UPDATE jobs SET status = \\$1").
+			WithArgs(jobs.StatusPending, sqlmock.AnyArg(), int64(10000), int64(3), self).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := worker.RunNext(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("lease lost to another worker", func(t *testing.T) {
+		claim(jobRow(4, "email.send", `{"to":"b@example.com"}`, 1, 5))
+		mock.ExpectExec("UPDATE jobs SET status = 'succeeded'").
+			WithArgs(int64(4), self).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ran, err := worker.RunNext(context.Background())
+		assert.True(t, ran)
+		assert.EqualError(t, err, "error recording job 4: lease lost to another worker")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryAndCancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	queue := jobs.NewQueue(db, 5)
+	ctx := context.Background()
+
+	mock.ExpectQuery("UPDATE jobs\\s+SET status = 'pending', attempts = 0(.+) WHERE id = \\$1 AND status IN \\('dead', 'cancelled'\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(jobRow(1, "email.send", `{}`, 0, 5))
+	_, err = queue.Retry(ctx, 1)
+	assert.NoError(t, err)
+
+	// Not dead: the job exists, so it is a conflict.
+	mock.ExpectQuery("UPDATE jobs").WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows(jobColumns))
+	mock.ExpectQuery("SELECT (.+) FROM jobs WHERE id = \\$1").WithArgs(int64(2)).WillReturnRows(jobRow(2, "email.send", `{}`, 1, 5))
+	_, err = queue.Retry(ctx, 2)
+	assert.EqualError(t, err, "job cannot be retried")
+
+	mock.ExpectQuery("UPDATE jobs SET status = 'cancelled'").WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(jobColumns))
+	mock.ExpectQuery("SELECT (.+) FROM jobs WHERE id = \\$1").WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(jobColumns))
+	_, err = queue.Cancel(ctx, 3)
+	assert.EqualError(t, err, "job not found")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShutdownCancelsRunningJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	queue := jobs.NewQueue(db, 5)
+	started := make(chan struct{})
+	jobs.Register(queue, "slow", func(ctx context.Context, payload struct{}) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	mock.ExpectQuery("UPDATE jobs").WillReturnRows(jobRow(1, "slow", `{}`, 1, 5))
+	mock.ExpectExec("UPDATE jobs SET status = \\$1").
+		WithArgs(jobs.StatusPending, "context canceled", int64(10000), int64(1), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	worker := jobs.NewWorker(queue, config)
+	worker.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, worker.Shutdown(ctx), context.DeadlineExceeded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE jobs SET status = 'dead'(.+) WHERE status = 'running' AND locked_until < NOW\\(\\) AND attempts >= max_attempts").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	reaped, err := jobs.NewQueue(db, 5).Reap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), reaped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
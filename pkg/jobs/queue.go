@@ -0,0 +1,315 @@
+// Package jobs is a background job queue stored in Postgres.
+//
+// Jobs are enqueued with a type and a JSON payload, and run by a Worker with
+// the handler registered for their type. A job that fails is retried with
+// exponential backoff until it runs out of attempts and becomes dead. Workers
+// in several processes may share the queue.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Job statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	// StatusDead is final: every attempt failed. Retrying makes it pending
+	// again.
+	StatusDead      = "dead"
+	StatusCancelled = "cancelled"
+)
+
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Priority    int             `json:"priority"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       string          `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
+
+// Options tune an enqueued job. The zero value runs the job now, at priority
+// 0, with the default number of attempts.
+type Options struct {
+	// Priority orders due jobs, higher first.
+	Priority int
+	// RunAt delays the job until then.
+	RunAt time.Time
+	// MaxAttempts overrides the attempts of the queue.
+	MaxAttempts int
+}
+
+// handlerFunc runs a job with its raw payload.
+type handlerFunc func(ctx context.Context, job *Job) error
+
+// Queue stores jobs and the handlers of their types.
+type Queue struct {
+	db          *sql.DB
+	maxAttempts int
+
+	mu       sync.RWMutex
+	handlers map[string]handlerFunc
+}
+
+// NewQueue returns a queue giving jobs maxAttempts attempts unless they are
+// enqueued with their own.
+func NewQueue(db *sql.DB, maxAttempts int) *Queue {
+	return &Queue{db: db, maxAttempts: maxAttempts, handlers: map[string]handlerFunc{}}
+}
+
+// Register sets the handler of jobType. The payload of a job is decoded into
+// a T before handler runs; a payload that does not decode fails the job.
+func Register[T any](queue *Queue, jobType string, handler func(ctx context.Context, payload T) error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	queue.handlers[jobType] = func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("error decoding payload: %w", err)
+		}
+		return handler(ctx, payload)
+	}
+}
+
+func (queue *Queue) handler(jobType string) handlerFunc {
+	queue.mu.RLock()
+	defer queue.mu.RUnlock()
+	return queue.handlers[jobType]
+}
+
+// types returns the job types with a handler, which are the ones this
+// process claims.
+func (queue *Queue) types() []string {
+	queue.mu.RLock()
+	defer queue.mu.RUnlock()
+
+	types := make([]string, 0, len(queue.handlers))
+	for jobType := range queue.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// execer is what jobs are enqueued on: the database, or the transaction of
+// the change that needs the job.
+type execer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Enqueue adds a job with payload, JSON encoded, and returns its ID.
+func (queue *Queue) Enqueue(ctx context.Context, jobType string, payload any, options Options) (int64, error) {
+	return queue.enqueue(ctx, queue.db, jobType, payload, options)
+}
+
+// EnqueueTx is Enqueue in tx: the job only exists if tx commits.
+func (queue *Queue) EnqueueTx(ctx context.Context, tx *sql.Tx, jobType string, payload any, options Options) (int64, error) {
+	return queue.enqueue(ctx, tx, jobType, payload, options)
+}
+
+func (queue *Queue) enqueue(ctx context.Context, q execer, jobType string, payload any, options Options) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	maxAttempts := options.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = queue.maxAttempts
+	}
+
+	// The delay is computed here and added to the clock of the database,
+	// which is the one jobs are claimed with.
+	var delay time.Duration
+	if !options.RunAt.IsZero() {
+		delay = max(time.Until(options.RunAt), 0)
+	}
+
+	var id int64
+	err = q.QueryRowContext(ctx, `INSERT INTO jobs (type, payload, priority, max_attempts, run_at)
+              VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 millisecond') RETURNING id`,
+		jobType, data, options.Priority, maxAttempts, delay.Milliseconds()).Scan(&id)
+	return id, err
+}
+
+const jobColumns = `id, type, payload, priority, status, attempts, max_attempts, run_at, last_error, created_at, updated_at`
+
+func scanJob(scanner interface{ Scan(dest ...any) error }) (*Job, error) {
+	var job Job
+	err := scanner.Scan(&job.ID, &job.Type, &job.Payload, &job.Priority, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LastError, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns the latest jobs, newest first, filtered by status and type
+// when they are not empty.
+func (queue *Queue) List(ctx context.Context, status string, jobType string, limit int) ([]Job, error) {
+	rows, err := queue.db.QueryContext(ctx, `SELECT `+jobColumns+` FROM jobs
+              WHERE ($1 = '' OR status = $1) AND ($2 = '' OR type = $2)
+              ORDER BY id DESC LIMIT $3`, status, jobType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (queue *Queue) Get(ctx context.Context, id int64) (*Job, error) {
+	job, err := scanJob(queue.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("job not found")
+	}
+	return job, err
+}
+
+// Retry makes a dead or cancelled job pending again, due now, with a fresh
+// set of attempts.
+func (queue *Queue) Retry(ctx context.Context, id int64) (*Job, error) {
+	job, err := scanJob(queue.db.QueryRowContext(ctx, `UPDATE jobs
+              SET status = 'pending', attempts = 0, run_at = NOW(), last_error = '', updated_at = NOW()
+              WHERE id = $1 AND status IN ('dead', 'cancelled')
+              RETURNING `+jobColumns, id))
+	if err == sql.ErrNoRows {
+		return nil, queue.notChanged(ctx, id, "job cannot be retried")
+	}
+	return job, err
+}
+
+// Cancel keeps a pending job from running. Running jobs cannot be cancelled.
+func (queue *Queue) Cancel(ctx context.Context, id int64) (*Job, error) {
+	job, err := scanJob(queue.db.QueryRowContext(ctx, `UPDATE jobs SET status = 'cancelled', updated_at = NOW()
+              WHERE id = $1 AND status = 'pending'
+              RETURNING `+jobColumns, id))
+	if err == sql.ErrNoRows {
+		return nil, queue.notChanged(ctx, id, "job cannot be cancelled")
+	}
+	return job, err
+}
+
+// notChanged tells a job that does not exist from one in the wrong status.
+func (queue *Queue) notChanged(ctx context.Context, id int64, message string) error {
+	if _, err := queue.Get(ctx, id); err != nil {
+		return err
+	}
+	return errors.New(message)
+}
+
+// claim locks the next due job of types for lease on behalf of worker and
+// returns it, or nil when none is due. Running jobs whose lease ended,
+// because their worker died, are claimed again while they have attempts
+// left; Reap buries the others.
+func (queue *Queue) claim(ctx context.Context, types []string, lease time.Duration, worker string) (*Job, error) {
+	job, err := scanJob(queue.db.QueryRowContext(ctx, `UPDATE jobs
+              SET status = 'running', attempts = attempts + 1, locked_until = NOW() + $2 * INTERVAL '1 millisecond',
+                  locked_by = $3, updated_at = NOW()
+              WHERE id = (
+                  SELECT id FROM jobs
+                  WHERE type = ANY($1) AND ((status = 'pending' AND run_at <= NOW())
+                      OR (status = 'running' AND locked_until < NOW() AND attempts < max_attempts))
+                  ORDER BY priority DESC, run_at, id
+                  LIMIT 1
+                  FOR UPDATE SKIP LOCKED)
+              RETURNING `+jobColumns, pq.Array(types), lease.Milliseconds(), worker))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// errLeaseLost is returned by finish when the job was claimed again by
+// another worker while it ran.
+var errLeaseLost = errors.New("lease lost to another worker")
+
+// finish records the outcome of a run by worker. A failed job is retried in
+// retryIn, or becomes dead when it has no attempts left.
+func (queue *Queue) finish(ctx context.Context, job *Job, worker string, runErr error, retryIn time.Duration) error {
+	var result sql.Result
+	var err error
+	if runErr == nil {
+		result, err = queue.db.ExecContext(ctx, `UPDATE jobs SET status = 'succeeded', locked_until = NULL, locked_by = NULL,
+              last_error = '', updated_at = NOW()
+              WHERE id = $1 AND status = 'running' AND locked_by = $2`, job.ID, worker)
+	} else {
+		status := StatusPending
+		if job.Attempts >= job.MaxAttempts {
+			status = StatusDead
+		}
+		job.Status = status
+
+		result, err = queue.db.ExecContext(ctx, `UPDATE jobs SET status = $1, locked_until = NULL, locked_by = NULL, last_error = $2,
+              run_at = NOW() + $3 * INTERVAL '1 millisecond', updated_at = NOW()
+              WHERE id = $4 AND status = 'running' AND locked_by = $5`, status, runErr.Error(), retryIn.Milliseconds(), job.ID, worker)
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errLeaseLost
+	}
+
+	return nil
+}
+
+// Reap makes dead the running jobs whose lease ended on their last attempt,
+// which claim leaves alone, and returns how many it found.
+func (queue *Queue) Reap(ctx context.Context) (int64, error) {
+	result, err := queue.db.ExecContext(ctx, `UPDATE jobs SET status = 'dead', locked_until = NULL, locked_by = NULL,
+              last_error = 'lease ended on the last attempt', updated_at = NOW()
+              WHERE status = 'running' AND locked_until < NOW() AND attempts >= max_attempts`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Prune deletes the succeeded and cancelled jobs last updated more than
+// olderThan ago, and returns how many it deleted.
+func (queue *Queue) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
+	result, err := queue.db.ExecContext(ctx, `DELETE FROM jobs
+              WHERE status IN ('succeeded', 'cancelled') AND updated_at < NOW() - $1 * INTERVAL '1 millisecond'`,
+		olderThan.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
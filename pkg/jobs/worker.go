@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+)
+
+type Config struct {
+	// Concurrency is how many jobs run at the same time.
+	Concurrency int
+	// PollInterval is how long an idle worker waits before looking for due
+	// jobs again.
+	PollInterval time.Duration
+	// Timeout bounds a run. A job still running after twice as long, because
+	// its worker died, is claimed again.
+	Timeout time.Duration
+	// The delay before retry n is BaseBackoff * 2^(n-1), capped at MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Worker runs the jobs of a Queue whose type has a handler.
+type Worker struct {
+	queue  *Queue
+	config Config
+	// id tells the jobs of this worker from those of others.
+	id string
+
+	// stop ends the loops claiming jobs.
+	polling context.Context
+	stop    context.CancelFunc
+	// cancel aborts the jobs still running when Shutdown gives up on them.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(queue *Queue, config Config) *Worker {
+	polling, stop := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		queue:   queue,
+		config:  config,
+		id:      workerID(),
+		polling: polling,
+		stop:    stop,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// workerID returns an ID unique to a worker: the host, the process and a
+// random suffix.
+func workerID() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Start runs Concurrency loops claiming and running jobs, until Shutdown.
+func (worker *Worker) Start() {
+	log.Printf("Job worker started with %d runners", worker.config.Concurrency)
+	for range worker.config.Concurrency {
+		worker.wg.Add(1)
+		go worker.loop()
+	}
+}
+
+// Shutdown stops claiming jobs and waits for the running ones to finish.
+// When ctx is done first, their context is cancelled; they are retried
+// later if they fail because of it.
+func (worker *Worker) Shutdown(ctx context.Context) error {
+	worker.stop()
+
+	done := make(chan struct{})
+	go func() {
+		worker.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		worker.cancel()
+		log.Println("Job worker stopped")
+		return nil
+	case <-ctx.Done():
+		worker.cancel()
+		<-done
+		log.Println("Job worker stopped, running jobs were cancelled")
+		return ctx.Err()
+	}
+}
+
+// loop claims and runs one job at a time. Jobs run with the context of the
+// worker rather than the one of the loop, so that stopping the loop lets
+// them finish.
+func (worker *Worker) loop() {
+	defer worker.wg.Done()
+
+	background.Poll(worker.polling, "job worker", worker.config.PollInterval, 1, func(context.Context) (int, error) {
+		ran, err := worker.RunNext(worker.ctx)
+		if ran {
+			return 1, err
+		}
+		return 0, err
+	})
+}
+
+// RunNext claims the next due job and runs it. It returns false when no job
+// was due.
+func (worker *Worker) RunNext(ctx context.Context) (bool, error) {
+	types := worker.queue.types()
+	if len(types) == 0 {
+		return false, nil
+	}
+
+	job, err := worker.queue.claim(ctx, types, 2*worker.config.Timeout, worker.id)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	runErr := worker.run(ctx, job)
+
+	var retryIn time.Duration
+	if runErr != nil {
+		retryIn = background.Backoff(worker.config.BaseBackoff, worker.config.MaxBackoff, job.Attempts)
+	}
+
+	// Recorded even when ctx was cancelled by Shutdown.
+	if err := worker.queue.finish(context.WithoutCancel(ctx), job, worker.id, runErr, retryIn); err != nil {
+		return true, fmt.Errorf("error recording job %d: %w", job.ID, err)
+	}
+
+	switch {
+	case runErr == nil:
+	case job.Status == StatusDead:
+		log.Printf("Job %d (%s) is dead after %d attempts: %v", job.ID, job.Type, job.Attempts, runErr)
+	default:
+		log.Printf("Job %d (%s) failed, retrying in %s: %v", job.ID, job.Type, retryIn, runErr)
+	}
+
+	return true, nil
+}
+
+// run calls the handler of job within Timeout. A panicking handler fails the
+// job.
+func (worker *Worker) run(ctx context.Context, job *Job) error {
+	handler := worker.queue.handler(job.Type)
+	if handler == nil {
+		return fmt.Errorf("no handler for job type %s", job.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, worker.config.Timeout)
+	defer cancel()
+
+	return background.Call(func() error { return handler(ctx, job) })
+}
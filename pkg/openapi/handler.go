@@ -0,0 +1,38 @@
+package openapi
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	//go:embed ui/swagger.html
+	swaggerPage string
+	//go:embed ui/redoc.html
+	redocPage string
+)
+
+const specPath = "/openapi.json"
+
+// Routers serves the document at /openapi.json, with Swagger UI at /docs and
+// Redoc at /docs/redoc. The pages load their assets from a CDN.
+func (doc *Document) Routers(router fiber.Router) {
+	router.Get(specPath, doc.serveJSON)
+	router.Get("/docs", servePage(swaggerPage))
+	router.Get("/docs/redoc", servePage(redocPage))
+}
+
+func (doc *Document) serveJSON(context *fiber.Ctx) error {
+	return context.JSON(doc)
+}
+
+func servePage(page string) fiber.Handler {
+	page = strings.ReplaceAll(page, "{{SPEC_URL}}", specPath)
+
+	return func(context *fiber.Ctx) error {
+		context.Type("html", "utf-8")
+		return context.SendString(page)
+	}
+}
@@ -0,0 +1,338 @@
+// Package openapi builds an OpenAPI 3.1 document from route descriptions and
+// the DTO structs they accept and return.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/response"
+)
+
+// Security tells which credentials an operation accepts.
+type Security int
+
+const (
+	// Public operations take no credentials.
+	Public Security = iota
+	// BearerAuth operations require a JWT.
+	BearerAuth
+	// OptionalBearerAuth operations work anonymously and use a JWT when sent.
+	OptionalBearerAuth
+)
+
+// Param describes a query parameter.
+type Param struct {
+	Name        string
+	Description string
+	// Type is a JSON Schema type, "string" when empty.
+	Type     string
+	Required bool
+}
+
+// Operation describes a route the way it is registered on a fiber.Router.
+// Path uses Fiber syntax (/news/:id); path parameters are derived from it.
+type Operation struct {
+	Method   string
+	Path     string
+	Summary  string
+	Tags     []string
+	Security Security
+	Query    []Param
+	// Request is the JSON body, decoded into a value of this type.
+	Request any
+	// Files lists the multipart/form-data file fields of an upload.
+	Files []string
+	// Response is the data of the response.Response envelope. It is nil for
+	// operations answering with "data": null.
+	Response any
+	// Status of a successful call, 200 when zero.
+	Status int
+	// ContentType is set for operations answering with a raw body instead of
+	// the JSON envelope, e.g. feeds and files.
+	ContentType string
+	// Errors lists the failure statuses, which use the JSON envelope.
+	Errors []int
+	// Deprecated operations are kept for compatibility only.
+	Deprecated bool
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type OperationObject struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Deprecated  bool                      `json:"deprecated,omitempty"`
+	Security    []map[string][]string     `json:"security,omitempty"`
+	Parameters  []Parameter               `json:"parameters,omitempty"`
+	RequestBody *RequestBody              `json:"requestBody,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+}
+
+// Document is an OpenAPI 3.1 document. It is encoded with encoding/json.
+type Document struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       Info                                   `json:"info"`
+	Paths      map[string]map[string]*OperationObject `json:"paths"`
+	Components Components                             `json:"components"`
+
+	componentTypes map[string]reflect.Type
+}
+
+const bearerScheme = "bearerAuth"
+
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   map[string]map[string]*OperationObject{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		componentTypes: map[string]reflect.Type{},
+	}
+}
+
+// Add documents operations registered on a router mounted at prefix.
+func (doc *Document) Add(prefix string, operations ...Operation) {
+	doc.add(prefix, false, operations)
+}
+
+// AddDeprecated documents operations of a deprecated router mounted at prefix.
+func (doc *Document) AddDeprecated(prefix string, operations ...Operation) {
+	doc.add(prefix, true, operations)
+}
+
+func (doc *Document) add(prefix string, deprecated bool, operations []Operation) {
+	for _, operation := range operations {
+		operation.Deprecated = operation.Deprecated || deprecated
+		path := strings.TrimSuffix(prefix, "/") + operation.Path
+		method := strings.ToLower(operation.Method)
+
+		item, ok := doc.Paths[PathTemplate(path)]
+		if !ok {
+			item = map[string]*OperationObject{}
+			doc.Paths[PathTemplate(path)] = item
+		}
+		item[method] = doc.operationObject(method, path, operation)
+	}
+}
+
+// Has reports whether the route registered with the Fiber path is documented.
+func (doc *Document) Has(method, path string) bool {
+	item, ok := doc.Paths[PathTemplate(path)]
+	if !ok {
+		return false
+	}
+	_, ok = item[strings.ToLower(method)]
+	return ok
+}
+
+// Operations lists the documented routes as "METHOD /path" with Fiber-style
+// paths turned into templates.
+func (doc *Document) Operations() []string {
+	var operations []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// PathTemplate turns a Fiber route path into an OpenAPI path template:
+// /news/:id becomes /news/{id} and a trailing * becomes {path}.
+func PathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "{" + strings.TrimSuffix(segment[1:], "?") + "}"
+		case segment == "*" || segment == "+":
+			segments[i] = "{path}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParameters(path string) []Parameter {
+	var parameters []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		name := ""
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = strings.TrimSuffix(segment[1:], "?")
+		case segment == "*" || segment == "+":
+			name = "path"
+		default:
+			continue
+		}
+
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "Id") {
+			schema = &Schema{Type: "integer"}
+		}
+		parameters = append(parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return parameters
+}
+
+func (doc *Document) operationObject(method, path string, operation Operation) *OperationObject {
+	object := &OperationObject{
+		OperationID: operationID(method, path),
+		Summary:     operation.Summary,
+		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
+		Parameters:  pathParameters(path),
+		Responses:   map[string]ResponseObject{},
+	}
+
+	switch operation.Security {
+	case BearerAuth:
+		object.Security = []map[string][]string{{bearerScheme: {}}}
+	case OptionalBearerAuth:
+		object.Security = []map[string][]string{{}, {bearerScheme: {}}}
+	}
+
+	for _, param := range operation.Query {
+		kind := param.Type
+		if kind == "" {
+			kind = "string"
+		}
+		object.Parameters = append(object.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: kind},
+		})
+	}
+
+	if operation.Request != nil {
+		object.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: doc.schemaFor(reflect.TypeOf(operation.Request))},
+			},
+		}
+	}
+
+	if len(operation.Files) > 0 {
+		form := &Schema{Type: "object", Properties: map[string]*Schema{}, Required: operation.Files}
+		for _, field := range operation.Files {
+			form.Properties[field] = &Schema{Type: "string", Format: "binary"}
+		}
+		object.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"multipart/form-data": {Schema: form}},
+		}
+	}
+
+	status := operation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if status < http.StatusOK || status == http.StatusNoContent {
+		// e.g. 101 Switching Protocols, answered without a body.
+		object.Responses[strconv.Itoa(status)] = ResponseObject{Description: http.StatusText(status)}
+	} else if operation.ContentType != "" {
+		object.Responses[strconv.Itoa(status)] = ResponseObject{
+			Description: http.StatusText(status),
+			Content: map[string]MediaType{
+				operation.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}},
+			},
+		}
+	} else {
+		object.Responses[strconv.Itoa(status)] = doc.envelope(status, operation.Response)
+	}
+
+	for _, errorStatus := range operation.Errors {
+		object.Responses[strconv.Itoa(errorStatus)] = doc.envelope(errorStatus, nil)
+	}
+	if operation.Security == BearerAuth || operation.Security == OptionalBearerAuth {
+		if _, ok := object.Responses["401"]; !ok {
+			object.Responses["401"] = doc.envelope(http.StatusUnauthorized, nil)
+		}
+	}
+
+	return object
+}
+
+// envelope describes a response.Response carrying data.
+func (doc *Document) envelope(status int, data any) ResponseObject {
+	schema := doc.schemaFor(reflect.TypeOf(response.Response{}))
+	if data != nil {
+		schema = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"message": {Type: "string"},
+				"data":    doc.schemaFor(reflect.TypeOf(data)),
+			},
+			Required: []string{"message", "data"},
+		}
+	}
+
+	return ResponseObject{
+		Description: http.StatusText(status),
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func operationID(method, path string) string {
+	var builder strings.Builder
+	builder.WriteString(method)
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, ":*?{}")
+		if segment == "" {
+			continue
+		}
+		builder.WriteString(pascalCase(strings.NewReplacer(".", "_").Replace(segment)))
+	}
+	return builder.String()
+}
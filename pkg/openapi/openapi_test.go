@@ -0,0 +1,70 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/openapi"
+)
+
+type tagRequest struct {
+	Name     string     `json:"name" validate:"required,min=2,max=20"`
+	Email    string     `json:"email,omitempty" validate:"omitempty,email"`
+	Kind     string     `json:"kind" validate:"required,oneof=a b"`
+	Count    int        `json:"count" validate:"gte=1"`
+	Labels   []string   `json:"labels" validate:"max=3,dive,min=1"`
+	StartsAt *time.Time `json:"starts_at"`
+	Extra    *any       `json:"extra"`
+	Secret   string     `json:"-"`
+}
+
+func schemaJSON(t *testing.T, doc *openapi.Document, name string) map[string]any {
+	t.Helper()
+
+	encoded, err := json.Marshal(doc.Components.Schemas[name])
+	assert.NoError(t, err)
+
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal(encoded, &schema))
+	return schema
+}
+
+func TestSchemaFromValidateTags(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "Test", Version: "1"})
+	doc.Add("", openapi.Operation{Method: "POST", Path: "/things", Request: tagRequest{}})
+
+	schema := schemaJSON(t, doc, "tagRequest")
+	properties := schema["properties"].(map[string]any)
+
+	assert.ElementsMatch(t, []any{"name", "kind"}, schema["required"])
+	assert.Equal(t, map[string]any{"type": "string", "minLength": 2.0, "maxLength": 20.0}, properties["name"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "email"}, properties["email"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"a", "b"}}, properties["kind"])
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": 1.0}, properties["count"])
+	assert.Equal(t, map[string]any{
+		"type":     "array",
+		"maxItems": 3.0,
+		"items":    map[string]any{"type": "string", "minLength": 1.0},
+	}, properties["labels"])
+	assert.Equal(t, map[string]any{"type": []any{"string", "null"}, "format": "date-time"}, properties["starts_at"])
+	assert.Equal(t, map[string]any{}, properties["extra"])
+	assert.NotContains(t, properties, "Secret")
+}
+
+func TestAddPaths(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "Test", Version: "1"})
+	doc.Add("/api/v1/admin", openapi.Operation{Method: "DELETE", Path: "/news/:id", Security: openapi.BearerAuth})
+	doc.Add("", openapi.Operation{Method: "GET", Path: "/files/*"})
+
+	assert.True(t, doc.Has("DELETE", "/api/v1/admin/news/:id"))
+	assert.False(t, doc.Has("GET", "/api/v1/admin/news/:id"))
+	assert.Equal(t, []string{"DELETE /api/v1/admin/news/{id}", "GET /files/{path}"}, doc.Operations())
+
+	operation := doc.Paths["/api/v1/admin/news/{id}"]["delete"]
+	assert.Equal(t, "deleteApiV1AdminNewsId", operation.OperationID)
+	assert.Equal(t, "integer", operation.Parameters[0].Schema.Type)
+	assert.Contains(t, operation.Responses, "401")
+}
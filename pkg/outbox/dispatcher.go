@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+)
+
+// Event statuses.
+const (
+	StatusPending    = "pending"
+	StatusDispatched = "dispatched"
+	// StatusFailed is final: every attempt failed.
+	StatusFailed = "failed"
+)
+
+type Config struct {
+	// PollInterval is how long the dispatcher waits when no event is due.
+	PollInterval time.Duration
+	// BatchSize events are locked and dispatched in one transaction.
+	BatchSize int
+	// MaxAttempts is how many times an event is dispatched before it fails.
+	MaxAttempts int
+	// The delay before retry n is BaseBackoff * 2^(n-1), capped at MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Dispatcher hands the events of the outbox to a Bus. Dispatchers in several
+// processes may share a database: each locks the events it dispatches with
+// FOR UPDATE SKIP LOCKED, so the others pass over them.
+type Dispatcher struct {
+	db     *sql.DB
+	bus    *Bus
+	config Config
+}
+
+func NewDispatcher(db *sql.DB, bus *Bus, config Config) *Dispatcher {
+	return &Dispatcher{db: db, bus: bus, config: config}
+}
+
+// Run dispatches due events until ctx is done.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	log.Println("Outbox dispatcher started")
+	background.Poll(ctx, "outbox dispatcher", dispatcher.config.PollInterval, dispatcher.config.BatchSize, dispatcher.DispatchDue)
+	log.Println("Outbox dispatcher stopped")
+}
+
+// DispatchDue dispatches one batch of due events and records the outcome. It
+// returns how many events were dispatched. The events stay locked while
+// their subscribers run, so subscribers should be quick.
+func (dispatcher *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	tx, err := dispatcher.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, event_type, payload, attempts, created_at FROM outbox_events
+              WHERE status = 'pending' AND next_attempt_at <= NOW()
+              ORDER BY id
+              LIMIT $1
+              FOR UPDATE SKIP LOCKED`, dispatcher.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(&event.ID, &event.Type, &event.Payload, &event.Attempts, &event.CreatedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := dispatcher.dispatch(ctx, tx, event); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), tx.Commit()
+}
+
+// dispatch runs the subscribers of event and records the outcome in tx.
+func (dispatcher *Dispatcher) dispatch(ctx context.Context, tx *sql.Tx, event Event) error {
+	attempts := event.Attempts + 1
+
+	dispatchErr := dispatcher.bus.Dispatch(ctx, event)
+	if dispatchErr == nil {
+		_, err := tx.ExecContext(ctx, `UPDATE outbox_events SET status = $1, attempts = $2, last_error = '', dispatched_at = NOW()
+              WHERE id = $3`, StatusDispatched, attempts, event.ID)
+		return err
+	}
+
+	status := StatusPending
+	var retryIn time.Duration
+	if attempts >= dispatcher.config.MaxAttempts {
+		status = StatusFailed
+		log.Printf("Outbox event %d (%s) failed after %d attempts: %v", event.ID, event.Type, attempts, dispatchErr)
+	} else {
+		retryIn = background.Backoff(dispatcher.config.BaseBackoff, dispatcher.config.MaxBackoff, attempts)
+		log.Printf("Outbox event %d (%s) failed, retrying in %s: %v", event.ID, event.Type, retryIn, dispatchErr)
+	}
+
+	_, err := tx.ExecContext(ctx, `UPDATE outbox_events SET status = $1, attempts = $2, last_error = $3,
+              next_attempt_at = NOW() + $4 * INTERVAL '1 millisecond' WHERE id = $5`,
+		status, attempts, dispatchErr.Error(), retryIn.Milliseconds(), event.ID)
+	return err
+}
+
+// Prune deletes the events dispatched more than olderThan ago, and returns
+// how many it deleted.
+func Prune(ctx context.Context, db *sql.DB, olderThan time.Duration) (int64, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM outbox_events
+              WHERE status = 'dispatched' AND dispatched_at < NOW() - $1 * INTERVAL '1 millisecond'`,
+		olderThan.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,97 @@
+// Package outbox implements a transactional outbox: domain events are
+// written in the transaction of the change they describe, then a Dispatcher
+// hands them to the subscribers registered on a Bus.
+//
+// Delivery is at least once. A subscriber may see an event again when
+// another subscriber of it failed, or when the dispatcher stopped before
+// recording it, so subscribers must be idempotent. Events are dispatched in
+// order, except that a failed event is retried after the ones behind it.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+)
+
+// Event is an event read back from the outbox.
+type Event struct {
+	ID        int64
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+	// Attempts is how many times the event was dispatched before.
+	Attempts int
+}
+
+// Decode unmarshals the payload of the event into v.
+func (event Event) Decode(v any) error {
+	return json.Unmarshal(event.Payload, v)
+}
+
+// Write adds an event with data, JSON encoded, to the outbox in tx. The event
+// is only dispatched if tx commits.
+func Write(tx *sql.Tx, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO outbox_events (event_type, payload) VALUES ($1, $2)`, eventType, payload)
+	return err
+}
+
+// Handler processes an event. A returned error makes the dispatcher retry the
+// event later.
+type Handler func(ctx context.Context, event Event) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus routes events to the subscribers of their type. Subscribers are meant
+// to be registered while the application starts, before the dispatcher runs.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: map[string][]subscriber{}}
+}
+
+// Subscribe calls handler for every event of eventType. The name shows in
+// logs and errors.
+func (bus *Bus) Subscribe(eventType string, name string, handler Handler) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	bus.subscribers[eventType] = append(bus.subscribers[eventType], subscriber{name: name, handler: handler})
+	log.Printf("%s subscribed to %s", name, eventType)
+}
+
+// Dispatch calls every subscriber of the event, even when one fails, and
+// returns their errors joined. A panicking subscriber fails with an error.
+func (bus *Bus) Dispatch(ctx context.Context, event Event) error {
+	bus.mu.RLock()
+	subscribers := bus.subscribers[event.Type]
+	bus.mu.RUnlock()
+
+	var errs []error
+	for _, subscriber := range subscribers {
+		err := background.Call(func() error { return subscriber.handler(ctx, event) })
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscriber.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
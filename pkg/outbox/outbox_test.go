@@ -0,0 +1,142 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/outbox"
+)
+
+var config = outbox.Config{
+	PollInterval: time.Second,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	BaseBackoff:  5 * time.Second,
+	MaxBackoff:   time.Minute,
+}
+
+var eventColumns = []string{"id", "event_type", "payload", "attempts", "created_at"}
+
+func TestWrite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_events \\(event_type, payload\\) VALUES \\(\\$1, \\$2\\)").
+		WithArgs("user.registered", []byte(`{"id":1}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, outbox.Write(tx, "user.registered", map[string]int{"id": 1}))
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBusDispatch(t *testing.T) {
+	bus := outbox.NewBus()
+
+	var calls []string
+	bus.Subscribe("news.deleted", "first", func(ctx context.Context, event outbox.Event) error {
+		var data struct{ ID int }
+		assert.NoError(t, event.Decode(&data))
+		assert.Equal(t, 3, data.ID)
+		calls = append(calls, "first")
+		return errors.New("boom")
+	})
+	bus.Subscribe("news.deleted", "second", func(ctx context.Context, event outbox.Event) error {
+		calls = append(calls, "second")
+		panic("oops")
+	})
+	bus.Subscribe("news.created", "other", func(ctx context.Context, event outbox.Event) error {
+		calls = append(calls, "other")
+		return nil
+	})
+
+	err := bus.Dispatch(context.Background(), outbox.Event{Type: "news.deleted", Payload: []byte(`{"id":3}`)})
+	assert.EqualError(t, err, "first: boom\nsecond: panic: oops")
+	assert.Equal(t, []string{"first", "second"}, calls)
+
+	assert.NoError(t, bus.Dispatch(context.Background(), outbox.Event{Type: "user.registered"}))
+}
+
+func TestDispatchDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	bus := outbox.NewBus()
+	var dispatched []int64
+	bus.Subscribe("news.created", "test", func(ctx context.Context, event outbox.Event) error {
+		dispatched = append(dispatched, event.ID)
+		if event.ID == 2 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	dispatcher := outbox.NewDispatcher(db, bus, config)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, event_type, payload, attempts, created_at FROM outbox_events (.+) FOR UPDATE SKIP LOCKED").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(1, "news.created", []byte(`{}`), 0, time.Now()).
+			AddRow(2, "news.created", []byte(`{}`), 0, time.Now()).
+			AddRow(3, "news.created", []byte(`{}`), 2, time.Now()).
+			AddRow(4, "user.registered", []byte(`{}`), 0, time.Now()))
+	mock.ExpectExec("UPDATE outbox_events SET status = \\$1, attempts = \\$2, last_error = '', dispatched_at = NOW\\(\\)").
+		WithArgs(outbox.StatusDispatched, 1, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox_events SET status = \\$1, attempts = \\$2, last_error = \\$3").
+		WithArgs(outbox.StatusPending, 1, "test: unavailable", int64(5000), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox_events SET status = \\$1, attempts = \\$2, last_error = '', dispatched_at = NOW\\(\\)").
+		WithArgs(outbox.StatusDispatched, 3, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Events nobody subscribed to are done too.
+	mock.ExpectExec("UPDATE outbox_events SET status = \\$1, attempts = \\$2, last_error = '', dispatched_at = NOW\\(\\)").
+		WithArgs(outbox.StatusDispatched, 1, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	count, err := dispatcher.DispatchDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []int64{1, 2, 3}, dispatched)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("last attempt", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM outbox_events").
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(2, "news.created", []byte(`{}`), 2, time.Now()))
+		mock.ExpectExec("UPDATE outbox_events SET status = \\$1").
+			WithArgs(outbox.StatusFailed, 3, "test: unavailable", int64(0), int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		count, err := dispatcher.DispatchDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolled back when recording fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM outbox_events").
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(1, "news.created", []byte(`{}`), 0, time.Now()))
+		mock.ExpectExec("UPDATE outbox_events").WillReturnError(errors.New("connection lost"))
+		mock.ExpectRollback()
+
+		_, err := dispatcher.DispatchDue(context.Background())
+		assert.EqualError(t, err, "connection lost")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
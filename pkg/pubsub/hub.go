@@ -0,0 +1,144 @@
+// Package pubsub is an in-process hub that fans events out to subscribers,
+// e.g. clients of a Server-Sent Events or WebSocket stream.
+package pubsub
+
+import (
+	"encoding/json"
+	"expvar"
+	"log"
+	"sync"
+)
+
+// Dropped counts the subscribers disconnected for falling behind.
+var Dropped = expvar.NewInt("pubsub_dropped_subscribers")
+
+// Event is a published message. IDs increase by one with every event, so a
+// client can resume after the last ID it received.
+type Event struct {
+	ID   uint64          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type Config struct {
+	// ReplaySize is how many recent events are kept for subscribers that
+	// resume with a last event ID.
+	ReplaySize int
+	// ClientBuffer is how many events a subscriber may fall behind before it
+	// is disconnected.
+	ClientBuffer int
+}
+
+type Hub struct {
+	config Config
+
+	mu          sync.Mutex
+	lastID      uint64
+	replay      []Event
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub(config Config) *Hub {
+	if config.ReplaySize < 0 {
+		config.ReplaySize = 0
+	}
+	if config.ClientBuffer < 1 {
+		config.ClientBuffer = 1
+	}
+
+	return &Hub{
+		config:      config,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscription receives the events published after it was made. Its channel
+// is closed when the subscription is closed or dropped for being too slow.
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+}
+
+func (subscription *Subscription) Events() <-chan Event {
+	return subscription.events
+}
+
+// Close unsubscribes. It is safe to call more than once, and after the hub
+// dropped the subscription.
+func (subscription *Subscription) Close() {
+	subscription.hub.mu.Lock()
+	defer subscription.hub.mu.Unlock()
+
+	subscription.hub.remove(subscription)
+}
+
+// Publish sends data, encoded as JSON, to every subscriber. A subscriber
+// whose buffer is full is dropped rather than slowing down the others.
+func (hub *Hub) Publish(eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", eventType, err)
+		return
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.lastID++
+	event := Event{ID: hub.lastID, Type: eventType, Data: encoded}
+
+	if hub.config.ReplaySize > 0 {
+		if len(hub.replay) == hub.config.ReplaySize {
+			hub.replay = hub.replay[1:]
+		}
+		hub.replay = append(hub.replay, event)
+	}
+
+	for subscription := range hub.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			log.Printf("Dropping a subscriber %d events behind", len(subscription.events))
+			Dropped.Add(1)
+			hub.remove(subscription)
+		}
+	}
+}
+
+// Subscribe starts a subscription. With a lastEventID other than 0, the
+// events after it that are still in the replay buffer are delivered first.
+// An ID the hub has not reached yet, e.g. from before a restart, replays
+// nothing.
+func (hub *Hub) Subscribe(lastEventID uint64) *Subscription {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	var missed []Event
+	if lastEventID != 0 && lastEventID < hub.lastID {
+		for _, event := range hub.replay {
+			if event.ID > lastEventID {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		hub:    hub,
+		events: make(chan Event, hub.config.ClientBuffer+len(missed)),
+	}
+	for _, event := range missed {
+		subscription.events <- event
+	}
+
+	hub.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// remove must be called with mu held.
+func (hub *Hub) remove(subscription *Subscription) {
+	if _, ok := hub.subscribers[subscription]; !ok {
+		return
+	}
+	delete(hub.subscribers, subscription)
+	close(subscription.events)
+}
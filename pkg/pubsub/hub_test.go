@@ -0,0 +1,110 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/pubsub"
+)
+
+func drain(subscription *pubsub.Subscription) []pubsub.Event {
+	var events []pubsub.Event
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.Config{ReplaySize: 10, ClientBuffer: 10})
+	first := hub.Subscribe(0)
+	second := hub.Subscribe(0)
+
+	hub.Publish("news.created", map[string]int{"id": 1})
+
+	for _, subscription := range []*pubsub.Subscription{first, second} {
+		events := drain(subscription)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, uint64(1), events[0].ID)
+			assert.Equal(t, "news.created", events[0].Type)
+			assert.JSONEq(t, `{"id":1}`, string(events[0].Data))
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.Config{ReplaySize: 3, ClientBuffer: 1})
+	for i := 0; i < 5; i++ {
+		hub.Publish("news.updated", i)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID uint64
+		want        []uint64
+	}{
+		{name: "New subscriber", lastEventID: 0, want: nil},
+		{name: "Resume", lastEventID: 3, want: []uint64{4, 5}},
+		{name: "Older than the buffer", lastEventID: 1, want: []uint64{3, 4, 5}},
+		{name: "Up to date", lastEventID: 5, want: nil},
+		{name: "Unknown ID", lastEventID: 42, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subscription := hub.Subscribe(test.lastEventID)
+			defer subscription.Close()
+
+			var ids []uint64
+			for _, event := range drain(subscription) {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, test.want, ids)
+		})
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.Config{ClientBuffer: 2})
+	slow := hub.Subscribe(0)
+	fast := hub.Subscribe(0)
+	dropped := pubsub.Dropped.Value()
+
+	for i := 0; i < 3; i++ {
+		hub.Publish("news.created", i)
+		drain(fast)
+	}
+
+	var received int
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, 2, received, "the channel is closed once the buffer overflows")
+	assert.Equal(t, dropped+1, pubsub.Dropped.Value())
+
+	hub.Publish("news.created", 3)
+	assert.Len(t, drain(fast), 1)
+
+	slow.Close()
+}
+
+func TestClose(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.Config{ClientBuffer: 1})
+	subscription := hub.Subscribe(0)
+
+	subscription.Close()
+	subscription.Close()
+
+	_, ok := <-subscription.Events()
+	assert.False(t, ok)
+
+	hub.Publish("news.deleted", 1)
+}
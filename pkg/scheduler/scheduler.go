@@ -0,0 +1,293 @@
+// Package scheduler runs periodic tasks on cron schedules.
+//
+// Every replica of the application runs the same schedules; a Postgres
+// advisory lock and the last run recorded in the scheduled_tasks table make
+// sure a run happens on one of them only.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/background"
+	"github.com/lib/pq"
+	"github.com/robfig/cron/v3"
+)
+
+// Clock is the time source of a Scheduler, replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// TaskFunc is the work of a task. A returned error is recorded as the last
+// error of the task.
+type TaskFunc func(ctx context.Context) error
+
+type task struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	run      TaskFunc
+	next     time.Time
+}
+
+// Status is the schedule and last run of a task.
+type Status struct {
+	Name      string `json:"name"`
+	Schedule  string `json:"schedule"`
+	NextRunAt string `json:"next_run_at"`
+	// The last run, on any replica. They are empty before the first run.
+	LastRunAt      string `json:"last_run_at,omitempty"`
+	LastDurationMs int64  `json:"last_duration_ms"`
+	LastError      string `json:"last_error,omitempty"`
+	Runs           int64  `json:"runs"`
+}
+
+type Scheduler struct {
+	db    *sql.DB
+	clock Clock
+
+	mu    sync.Mutex
+	tasks []*task
+}
+
+// New returns a scheduler using clock, or the system clock when it is nil.
+func New(db *sql.DB, clock Clock) *Scheduler {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &Scheduler{db: db, clock: clock}
+}
+
+// everySchedule runs at the multiples of interval, counted from a fixed
+// origin rather than from when the process started, so that replicas started
+// at different times agree on the runs and make each once only.
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (schedule everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(schedule.interval).Add(schedule.interval)
+}
+
+// Register adds a task running on spec, a standard five field cron
+// expression or a descriptor such as "@daily" or "@every 1h". Times are
+// in the local time zone of the process, unless spec starts with
+// "CRON_TZ=<zone>". "@every" runs are aligned on multiples of the interval:
+// "@every 15m" runs on the hour and every quarter past.
+func (scheduler *Scheduler) Register(name string, spec string, run TaskFunc) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule of task %s: %w", name, err)
+	}
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = everySchedule{interval: every.Delay}
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	for _, task := range scheduler.tasks {
+		if task.name == name {
+			return fmt.Errorf("task %s is already registered", name)
+		}
+	}
+
+	scheduler.tasks = append(scheduler.tasks, &task{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		run:      run,
+		next:     schedule.Next(scheduler.clock.Now()),
+	})
+	return nil
+}
+
+// Run runs the tasks when they are due, until ctx is done. Runs missed while
+// the process was down are not caught up.
+func (scheduler *Scheduler) Run(ctx context.Context) {
+	log.Println("Scheduler started")
+
+	for {
+		wait := time.Minute
+		if next, ok := scheduler.nextRun(); ok {
+			wait = max(next.Sub(scheduler.clock.Now()), 0)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped")
+			return
+		case <-scheduler.clock.After(wait):
+		}
+
+		scheduler.RunDue(ctx)
+	}
+}
+
+func (scheduler *Scheduler) nextRun() (time.Time, bool) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	var next time.Time
+	for _, task := range scheduler.tasks {
+		if next.IsZero() || task.next.Before(next) {
+			next = task.next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// RunDue runs the tasks due at the time of the clock, concurrently, and
+// waits for them.
+func (scheduler *Scheduler) RunDue(ctx context.Context) {
+	now := scheduler.clock.Now()
+
+	type run struct {
+		task        *task
+		scheduledAt time.Time
+	}
+	var due []run
+
+	scheduler.mu.Lock()
+	for _, task := range scheduler.tasks {
+		if !task.next.After(now) {
+			due = append(due, run{task: task, scheduledAt: task.next})
+			task.next = task.schedule.Next(now)
+		}
+	}
+	scheduler.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, run := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scheduler.runTask(ctx, run.task, run.scheduledAt); err != nil {
+				log.Printf("Error running task %s: %v", run.task.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// lockKey derives the advisory lock of a task from its name.
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("scheduler:" + name))
+	return int64(hash.Sum64())
+}
+
+// runTask runs a task unless another replica holds its lock or already ran
+// it for scheduledAt. The lock is held by a transaction, so that it is
+// released even if the process dies.
+func (scheduler *Scheduler) runTask(ctx context.Context, task *task, scheduledAt time.Time) error {
+	tx, err := scheduler.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, lockKey(task.name)).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	var lastScheduledAt time.Time
+	err = tx.QueryRowContext(ctx, `SELECT last_scheduled_at FROM scheduled_tasks WHERE name = $1`, task.name).Scan(&lastScheduledAt)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && !lastScheduledAt.Before(scheduledAt) {
+		return nil
+	}
+
+	startedAt := scheduler.clock.Now()
+	runErr := background.Call(func() error { return task.run(ctx) })
+	duration := scheduler.clock.Now().Sub(startedAt)
+
+	var lastError string
+	if runErr != nil {
+		lastError = runErr.Error()
+		log.Printf("Task %s failed after %s: %v", task.name, duration, runErr)
+	} else {
+		log.Printf("Task %s done in %s", task.name, duration)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO scheduled_tasks (name, schedule, last_scheduled_at, last_started_at, last_duration_ms, last_error, run_count)
+              VALUES ($1, $2, $3, $4, $5, $6, 1)
+              ON CONFLICT (name) DO UPDATE SET schedule = EXCLUDED.schedule, last_scheduled_at = EXCLUDED.last_scheduled_at,
+                  last_started_at = EXCLUDED.last_started_at, last_duration_ms = EXCLUDED.last_duration_ms,
+                  last_error = EXCLUDED.last_error, run_count = scheduled_tasks.run_count + 1`,
+		task.name, task.spec, scheduledAt, startedAt, duration.Milliseconds(), lastError)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Statuses returns the registered tasks by name, with their last run.
+func (scheduler *Scheduler) Statuses(ctx context.Context) ([]Status, error) {
+	scheduler.mu.Lock()
+	statuses := make([]Status, 0, len(scheduler.tasks))
+	names := make([]string, 0, len(scheduler.tasks))
+	for _, task := range scheduler.tasks {
+		statuses = append(statuses, Status{Name: task.name, Schedule: task.spec, NextRunAt: task.next.Format(time.RFC3339)})
+		names = append(names, task.name)
+	}
+	scheduler.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	if len(statuses) == 0 {
+		return statuses, nil
+	}
+
+	rows, err := scheduler.db.QueryContext(ctx, `SELECT name, last_started_at, last_duration_ms, last_error, run_count
+              FROM scheduled_tasks WHERE name = ANY($1)`, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byName := map[string]*Status{}
+	for i := range statuses {
+		byName[statuses[i].Name] = &statuses[i]
+	}
+
+	for rows.Next() {
+		var name string
+		var lastRunAt time.Time
+		var lastDurationMs, runs int64
+		var lastError string
+		if err := rows.Scan(&name, &lastRunAt, &lastDurationMs, &lastError, &runs); err != nil {
+			return nil, err
+		}
+
+		status := byName[name]
+		status.LastRunAt = lastRunAt.Format(time.RFC3339)
+		status.LastDurationMs = lastDurationMs
+		status.LastError = lastError
+		status.Runs = runs
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,262 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/scheduler"
+)
+
+// fakeClock only moves when the test advances it. After fires when the clock
+// reaches the deadline.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	clock.waiters = append(clock.waiters, waiter{at: clock.now.Add(d), ch: ch})
+	return ch
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.now = clock.now.Add(d)
+	var pending []waiter
+	for _, waiter := range clock.waiters {
+		if waiter.at.After(clock.now) {
+			pending = append(pending, waiter)
+		} else {
+			waiter.ch <- clock.now
+		}
+	}
+	clock.waiters = pending
+}
+
+func newClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 10, 59, 0, 0, time.UTC)}
+}
+
+func TestRegister(t *testing.T) {
+	tasks := scheduler.New(nil, newClock())
+
+	assert.NoError(t, tasks.Register("prune", "0 * * * *", func(ctx context.Context) error { return nil }))
+	assert.EqualError(t, tasks.Register("prune", "@daily", func(ctx context.Context) error { return nil }),
+		"task prune is already registered")
+	assert.ErrorContains(t, tasks.Register("broken", "61 * * * *", func(ctx context.Context) error { return nil }),
+		"invalid schedule of task broken")
+}
+
+func TestRunDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	clock := newClock()
+	tasks := scheduler.New(db, clock)
+
+	runs := 0
+	assert.NoError(t, tasks.Register("prune", "0 * * * *", func(ctx context.Context) error {
+		runs++
+		clock.Advance(1500 * time.Millisecond)
+		return errors.New("disk full")
+	}))
+	dueAt := time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+
+	t.Run("not due", func(t *testing.T) {
+		tasks.RunDue(context.Background())
+		assert.Equal(t, 0, runs)
+	})
+
+	t.Run("due", func(t *testing.T) {
+		clock.Advance(time.Minute)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_try_advisory_xact_lock\\(\\$1\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+		mock.ExpectQuery("SELECT last_scheduled_at FROM scheduled_tasks WHERE name = \\$1").
+			WithArgs("prune").
+			WillReturnRows(sqlmock.NewRows([]string{"last_scheduled_at"}).AddRow(dueAt.Add(-time.Hour)))
+		mock.ExpectExec("INSERT INTO scheduled_tasks (.+) ON CONFLICT \\(name\\) DO UPDATE").
+			WithArgs("prune", "0 * * * *", dueAt, dueAt, int64(1500), "disk full").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tasks.RunDue(context.Background())
+		assert.Equal(t, 1, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// The next run is an hour later.
+		tasks.RunDue(context.Background())
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("locked by another replica", func(t *testing.T) {
+		clock.Advance(time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+			WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+		mock.ExpectRollback()
+
+		tasks.RunDue(context.Background())
+		assert.Equal(t, 1, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already run by another replica", func(t *testing.T) {
+		clock.Advance(time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+			WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+		mock.ExpectQuery("SELECT last_scheduled_at FROM scheduled_tasks").
+			WillReturnRows(sqlmock.NewRows([]string{"last_scheduled_at"}).AddRow(dueAt.Add(2 * time.Hour)))
+		mock.ExpectRollback()
+
+		tasks.RunDue(context.Background())
+		assert.Equal(t, 1, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRunDueAcrossReplicas(t *testing.T) {
+	// The second replica started 25 seconds after the first.
+	first, second := newClock(), newClock()
+	second.Advance(25 * time.Second)
+
+	firstDB, firstMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer firstDB.Close()
+	secondDB, secondMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer secondDB.Close()
+
+	runs := 0
+	count := func(ctx context.Context) error {
+		runs++
+		return nil
+	}
+	firstTasks := scheduler.New(firstDB, first)
+	assert.NoError(t, firstTasks.Register("announce", "@every 1m", count))
+	secondTasks := scheduler.New(secondDB, second)
+	assert.NoError(t, secondTasks.Register("announce", "@every 1m", count))
+
+	// Both are due on the minute, not a minute after they started.
+	dueAt := time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	first.Advance(dueAt.Sub(first.Now()) + 30*time.Second)
+	second.Advance(dueAt.Sub(second.Now()) + 30*time.Second)
+
+	firstMock.ExpectBegin()
+	firstMock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	firstMock.ExpectQuery("SELECT last_scheduled_at").
+		WillReturnRows(sqlmock.NewRows([]string{"last_scheduled_at"}).AddRow(dueAt.Add(-time.Minute)))
+	firstMock.ExpectExec("INSERT INTO scheduled_tasks").
+		WithArgs("announce", "@every 1m", dueAt, sqlmock.AnyArg(), sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	firstMock.ExpectCommit()
+	firstTasks.RunDue(context.Background())
+
+	// The second replica finds the run of the first.
+	secondMock.ExpectBegin()
+	secondMock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	secondMock.ExpectQuery("SELECT last_scheduled_at").
+		WillReturnRows(sqlmock.NewRows([]string{"last_scheduled_at"}).AddRow(dueAt))
+	secondMock.ExpectRollback()
+	secondTasks.RunDue(context.Background())
+
+	assert.Equal(t, 1, runs)
+	assert.NoError(t, firstMock.ExpectationsWereMet())
+	assert.NoError(t, secondMock.ExpectationsWereMet())
+}
+
+func TestRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	clock := newClock()
+	tasks := scheduler.New(db, clock)
+
+	ran := make(chan struct{})
+	assert.NoError(t, tasks.Register("prune", "@every 1m", func(ctx context.Context) error {
+		close(ran)
+		return nil
+	}))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectQuery("SELECT last_scheduled_at").
+		WillReturnRows(sqlmock.NewRows([]string{"last_scheduled_at"}))
+	mock.ExpectExec("INSERT INTO scheduled_tasks").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		tasks.Run(ctx)
+		close(stopped)
+	}()
+
+	// Advance until the scheduler waits on the clock and wakes up.
+	for {
+		select {
+		case <-ran:
+			cancel()
+			<-stopped
+			return
+		case <-time.After(time.Millisecond):
+			clock.Advance(10 * time.Second)
+		}
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	tasks := scheduler.New(db, newClock())
+	assert.NoError(t, tasks.Register("outbox.prune", "@daily", func(ctx context.Context) error { return nil }))
+	assert.NoError(t, tasks.Register("jobs.prune", "0 * * * *", func(ctx context.Context) error { return nil }))
+
+	lastRun := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT name, last_started_at, last_duration_ms, last_error, run_count\\s+FROM scheduled_tasks WHERE name = ANY\\(\\$1\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "last_started_at", "last_duration_ms", "last_error", "run_count"}).
+			AddRow("jobs.prune", lastRun, 12, "", 3))
+
+	statuses, err := tasks.Statuses(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []scheduler.Status{
+		{Name: "jobs.prune", Schedule: "0 * * * *", NextRunAt: "2026-01-01T11:00:00Z", LastRunAt: "2026-01-01T10:00:00Z", LastDurationMs: 12, Runs: 3},
+		{Name: "outbox.prune", Schedule: "@daily", NextRunAt: "2026-01-02T00:00:00Z"},
+	}, statuses)
+}
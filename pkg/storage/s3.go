@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle addresses objects as <endpoint>/<bucket>/<key>, which most
+	// S3 compatible servers (MinIO, Ceph, LocalStack) expect.
+	UsePathStyle bool
+}
+
+// S3Storage talks to any S3 compatible object store using AWS Signature V4.
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func (storage *S3Storage) objectURL(key string) (*url.URL, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(storage.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	object := *endpoint
+	if storage.config.UsePathStyle {
+		object.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + storage.config.Bucket + "/" + key
+	} else {
+		object.Host = storage.config.Bucket + "." + endpoint.Host
+		object.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + key
+	}
+
+	return &object, nil
+}
+
+func (storage *S3Storage) do(ctx context.Context, method string, key string, body []byte, contentType string) (*http.Response, error) {
+	object, err := storage.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, object.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	storage.signRequest(request, body)
+
+	return storage.client.Do(request)
+}
+
+func (storage *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	response, err := storage.do(ctx, http.MethodPut, key, payload, contentType)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return s3ResponseError(response)
+}
+
+func (storage *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	response, err := storage.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s3ResponseError(response); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	response, err := storage.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err := s3ResponseError(response); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// SignedURL returns a presigned GET URL that the client downloads from the
+// object store directly.
+func (storage *S3Storage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	object, err := storage.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := storage.now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", storage.config.AccessKeyID+"/"+storage.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		s3EncodePath(object.Path),
+		s3CanonicalQuery(query),
+		"host:" + object.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", storage.signature(now, canonicalRequest))
+	object.RawQuery = s3CanonicalQuery(query)
+
+	return object.String(), nil
+}
+
+func (storage *S3Storage) signRequest(request *http.Request, body []byte) {
+	now := storage.now().UTC()
+	payloadHash := s3Hash(body)
+
+	request.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		s3EncodePath(request.URL.Path),
+		s3CanonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, storage.config.AccessKeyID, storage.scope(now), signedHeaders, storage.signature(now, canonicalRequest)))
+}
+
+func (storage *S3Storage) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + storage.config.Region + "/s3/aws4_request"
+}
+
+func (storage *S3Storage) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		storage.scope(now),
+		s3Hash([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+storage.config.SecretAccessKey), now.Format(s3DateFormat))
+	key = s3HMAC(key, storage.config.Region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3Encode percent-encodes everything except the RFC 3986 unreserved
+// characters, as required by Signature V4.
+func s3Encode(value string, keepSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && keepSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func s3EncodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return s3Encode(path, true)
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Encode(key, false)+"="+s3Encode(value, false))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func s3ResponseError(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("s3 request failed with status %d: %s", response.StatusCode, strings.TrimSpace(string(message)))
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	if config.Endpoint == "" || config.Bucket == "" || config.Region == "" {
+		return nil, errors.New("s3 endpoint, bucket and region are required")
+	}
+
+	return &S3Storage{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
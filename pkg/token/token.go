@@ -0,0 +1,52 @@
+// Package token issues and verifies the signed tokens users authenticate
+// with, whichever API they call.
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claims are what a token says about its user.
+type Claims struct {
+	UserID int  `json:"user_id"`
+	Admin  bool `json:"admin"`
+	jwt.RegisteredClaims
+}
+
+// Issue signs a token for the user userID, an admin when admin is set, which
+// expires after ttl.
+func Issue(secret string, userID int, admin bool, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"apps":    "go-rest-api-template",
+		"user_id": userID,
+		"admin":   admin,
+		"exp":     time.Now().Add(ttl).Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+// Parse verifies that signed was issued with secret and has not expired,
+// and returns its claims.
+func Parse(signed string, secret string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(signed, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token claims")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,47 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ahmadammarm/go-rest-api-template/pkg/token"
+)
+
+const testSecret = "test-secret"
+
+func TestIssue(t *testing.T) {
+	signed, err := token.Issue(testSecret, 7, true, time.Hour)
+	assert.NoError(t, err)
+
+	claims, err := token.Parse(signed, testSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+	assert.True(t, claims.Admin)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	expired, err := token.Issue(testSecret, 7, false, -time.Minute)
+	assert.NoError(t, err)
+	_, err = token.Parse(expired, testSecret)
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestParse(t *testing.T) {
+	signed, err := token.Issue(testSecret, 7, false, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = token.Parse(signed, "other-secret")
+	assert.Error(t, err)
+
+	_, err = token.Parse("not-a-token", testSecret)
+	assert.Error(t, err)
+
+	// Only HMAC signatures are accepted.
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"user_id": 7}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	_, err = token.Parse(unsigned, testSecret)
+	assert.Error(t, err)
+}